@@ -1,42 +1,68 @@
 package main
 
 import (
+	"fmt"
 	"os"
-
-	"github.com/openshift/cluster-dns-operator/pkg/operator"
-	operatorconfig "github.com/openshift/cluster-dns-operator/pkg/operator/config"
+	"strings"
 
 	"github.com/sirupsen/logrus"
-
-	"sigs.k8s.io/controller-runtime/pkg/metrics"
-	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 )
 
-func main() {
-	metrics.DefaultBindAddress = ":60000"
+// command is a subcommand of dns-operator.
+type command struct {
+	// name is the name of the subcommand on the command line.
+	name string
 
-	// Collect operator configuration.
-	coreDNSImage := os.Getenv("IMAGE")
-	if len(coreDNSImage) == 0 {
-		logrus.Fatalf("IMAGE environment variable is required")
-	}
-	cliImage := os.Getenv("OPENSHIFT_CLI_IMAGE")
-	if len(cliImage) == 0 {
-		logrus.Fatalf("OPENSHIFT_CLI_IMAGE environment variable is required")
-	}
+	// description is a one-line description of the subcommand.
+	description string
+
+	// run runs the subcommand with the arguments that follow its name.
+	run func(args []string) error
+}
 
-	operatorConfig := operatorconfig.Config{
-		OperatorReleaseVersion: os.Getenv("RELEASE_VERSION"),
-		CoreDNSImage:           coreDNSImage,
-		OpenshiftCLIImage:      cliImage,
+// commands are the subcommands of dns-operator.
+var commands = []command{
+	{
+		name:        "start",
+		description: "Start the operator (the default if no subcommand is given)",
+		run:         runStart,
+	},
+	{
+		name:        "render",
+		description: "Print the objects the operator applies for a DNS, without a cluster",
+		run:         runRender,
+	},
+}
+
+func main() {
+	// Run the operator if no subcommand is given, as before subcommands
+	// existed.
+	name, args := "start", os.Args[1:]
+	if len(args) != 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
 	}
 
-	// Set up and start the operator.
-	op, err := operator.New(operatorConfig)
-	if err != nil {
-		logrus.Fatalf("failed to create operator: %v", err)
+	if name == "help" {
+		usage()
+		return
 	}
-	if err := op.Start(signals.SetupSignalHandler()); err != nil {
-		logrus.Fatalf("failed to start operator: %v", err)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(args); err != nil {
+				logrus.Fatalf("%s: %v", name, err)
+			}
+			return
+		}
+	}
+	usage()
+	logrus.Fatalf("unknown command %q", name)
+}
+
+// usage prints the subcommands to standard error.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
 	}
+	fmt.Fprintf(os.Stderr, "\nRun \"%s <command> -h\" for the flags of a command.\n", os.Args[0])
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorclient "github.com/openshift/cluster-dns-operator/pkg/operator/client"
+	operatorconfig "github.com/openshift/cluster-dns-operator/pkg/operator/config"
+	operatorcontroller "github.com/openshift/cluster-dns-operator/pkg/operator/controller"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// runRender prints the objects that the operator applies for a dns and a
+// cluster network config read from files, without a cluster.
+func runRender(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	dnsFile := flags.String("dns", "", "Path to a YAML file of the DNS to render (required).")
+	networkFile := flags.String("network", "", "Path to a YAML file of the cluster network config (required).")
+	coreDNSImage := flags.String("coredns-image", os.Getenv("IMAGE"), "The CoreDNS image to render (env IMAGE).")
+	coreDNSVersion := flags.String("coredns-version", operatorconfig.DefaultCoreDNSVersion, "The version of CoreDNS in the CoreDNS image.")
+	var coreDNSPlugins listValue
+	flags.Var(&coreDNSPlugins, "coredns-plugins", "Comma-separated plugins that the CoreDNS image is built with in addition to those of its CoreDNS release.")
+	cliImage := flags.String("openshift-cli-image", os.Getenv("OPENSHIFT_CLI_IMAGE"), "The openshift client image to render (env OPENSHIFT_CLI_IMAGE).")
+	operandNamespace := flags.String("operand-namespace", operatorconfig.DefaultOperandNamespace, "The namespace of the dns resources.")
+	var cluster operatorcontroller.RenderCluster
+	flags.Int64Var(&cluster.Nodes, "nodes", 0, "The number of schedulable nodes of the cluster, for replicas, sizing and the pod disruption budget.")
+	flags.Int64Var(&cluster.Cores, "cores", 0, "The number of allocatable cores of the cluster, for replicas.")
+	flags.Int64Var(&cluster.Services, "services", 0, "The number of Services in the cluster, for ClusterProportional sizing.")
+	flags.Int64Var(&cluster.Endpoints, "endpoints", 0, "The number of Endpoints in the cluster, for ClusterProportional sizing.")
+	flags.BoolVar(&cluster.EndpointSlices, "endpoint-slices", false, "Whether the API serves EndpointSlices.")
+	flags.BoolVar(&cluster.Monitoring, "monitoring", false, "Whether the cluster serves the ServiceMonitor and PrometheusRule APIs.")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+	for name, value := range map[string]string{
+		"dns":                 *dnsFile,
+		"network":             *networkFile,
+		"coredns-image":       *coreDNSImage,
+		"openshift-cli-image": *cliImage,
+	} {
+		if len(value) == 0 {
+			return fmt.Errorf("flag -%s is required", name)
+		}
+	}
+
+	dns := &operatorv1.DNS{}
+	if err := readObject(*dnsFile, dns); err != nil {
+		return err
+	}
+	if len(dns.Name) == 0 {
+		return fmt.Errorf("dns in %s has no name", *dnsFile)
+	}
+	network := &configv1.Network{}
+	if err := readObject(*networkFile, network); err != nil {
+		return err
+	}
+
+	v, err := version.Parse(*coreDNSVersion)
+	if err != nil {
+		return fmt.Errorf("invalid flag -coredns-version: %v", err)
+	}
+	config := operatorcontroller.Config{
+		CoreDNSImage:      *coreDNSImage,
+		CoreDNSVersion:    v,
+		CoreDNSPlugins:    coreDNSPlugins,
+		OpenshiftCLIImage: *cliImage,
+		OperandNamespace:  *operandNamespace,
+	}
+
+	objs, notes, err := operatorcontroller.Render(config, cluster, dns, network)
+	if err != nil {
+		return fmt.Errorf("failed to render dns %s: %v", dns.Name, err)
+	}
+	if err := writeNotes(os.Stdout, notes); err != nil {
+		return err
+	}
+	return writeObjects(os.Stdout, objs)
+}
+
+// writeNotes writes notes to w as a header of YAML comments, so that the
+// reader knows which objects depend on the cluster that was described by
+// flags.
+func writeNotes(w io.Writer, notes []string) error {
+	if len(notes) == 0 {
+		return nil
+	}
+	lines := []string{"# Rendered without a cluster; the cluster is described by flags:"}
+	for _, note := range notes {
+		lines = append(lines, "# - "+note)
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// readObject reads the YAML file at path into obj.  Unknown fields are an
+// error.
+func readObject(path string, obj runtime.Object) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := yaml.UnmarshalStrict(data, obj); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return nil
+}
+
+// writeObjects writes objs to w as a stream of YAML documents, with the kind
+// and API version of each object set.
+func writeObjects(w io.Writer, objs []runtime.Object) error {
+	scheme := operatorclient.GetScheme()
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return fmt.Errorf("failed to get kind of %T: %v", obj, err)
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %v", gvk.Kind, err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
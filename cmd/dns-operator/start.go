@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/cluster-dns-operator/pkg/operator"
+	operatorconfig "github.com/openshift/cluster-dns-operator/pkg/operator/config"
+
+	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
+)
+
+// runStart loads the operator configuration and runs the operator until it
+// is signalled to stop.
+func runStart(args []string) error {
+	var configFile string
+	var flagConfig operatorconfig.Config
+	flags := startFlags(&flagConfig, &configFile)
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+
+	operatorConfig, err := loadConfig(configFile, flags)
+	if err != nil {
+		return err
+	}
+
+	// Set up and start the operator.
+	op, err := operator.New(operatorConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create operator: %v", err)
+	}
+	if err := op.Start(signals.SetupSignalHandler()); err != nil {
+		return fmt.Errorf("failed to start operator: %v", err)
+	}
+	return nil
+}
+
+// startFlags returns the flags of the start command, bound to the fields of
+// config and to configFile.  The fields keep their values unless the flags
+// are set.
+func startFlags(config *operatorconfig.Config, configFile *string) *flag.FlagSet {
+	flags := flag.NewFlagSet("start", flag.ContinueOnError)
+	flags.StringVar(configFile, "config", "", "Path to a YAML file of operator configuration.  Environment variables and flags override it.")
+	flags.StringVar(&config.CoreDNSImage, "coredns-image", config.CoreDNSImage, "The CoreDNS image to manage (env IMAGE).")
+	flags.StringVar(&config.CoreDNSVersion, "coredns-version", config.CoreDNSVersion, fmt.Sprintf("The version of CoreDNS in the CoreDNS image (env COREDNS_VERSION, default %q).", operatorconfig.DefaultCoreDNSVersion))
+	flags.Var((*listValue)(&config.CoreDNSPlugins), "coredns-plugins", "Comma-separated plugins that the CoreDNS image is built with in addition to those of its CoreDNS release (env COREDNS_PLUGINS).")
+	flags.StringVar(&config.OpenshiftCLIImage, "openshift-cli-image", config.OpenshiftCLIImage, "The openshift client image to manage (env OPENSHIFT_CLI_IMAGE).")
+	flags.StringVar(&config.OperatorReleaseVersion, "release-version", config.OperatorReleaseVersion, "The current version of the operator (env RELEASE_VERSION).")
+	flags.StringVar(&config.LogLevel, "log-level", config.LogLevel, "The minimum level of logged messages: debug, info, warning or error (env LOG_LEVEL).")
+	flags.StringVar(&config.LogFormat, "log-format", config.LogFormat, "The format of logged messages: text or json (env LOG_FORMAT).")
+	flags.StringVar(&config.MetricsBindAddress, "metrics-bind-address", config.MetricsBindAddress, fmt.Sprintf("The address on which metrics are served (default %q).", operatorconfig.DefaultMetricsBindAddress))
+	flags.StringVar(&config.HealthBindAddress, "health-bind-address", config.HealthBindAddress, "The address on which the health endpoints are served (default the metrics address).")
+	flags.BoolVar(&config.LeaderElection, "leader-elect", config.LeaderElection, "Enable leader election (env LEADER_ELECTION).")
+	flags.StringVar(&config.LeaderElectionNamespace, "leader-election-namespace", config.LeaderElectionNamespace, fmt.Sprintf("The namespace of the leader election lock (default %q).", operatorconfig.DefaultLeaderElectionNamespace))
+	flags.StringVar(&config.LeaderElectionID, "leader-election-id", config.LeaderElectionID, fmt.Sprintf("The name of the leader election lock (env LEADER_ELECTION_ID, default %q).", operatorconfig.DefaultLeaderElectionID))
+	flags.StringVar(&config.OperatorNamespace, "operator-namespace", config.OperatorNamespace, fmt.Sprintf("The namespace in which the operator runs (env OPERATOR_NAMESPACE, default %q).", operatorconfig.DefaultOperatorNamespace))
+	flags.StringVar(&config.OperandNamespace, "operand-namespace", config.OperandNamespace, fmt.Sprintf("The namespace of the dns resources (default %q).", operatorconfig.DefaultOperandNamespace))
+	flags.DurationVar(&config.ResyncPeriod.Duration, "resync-period", config.ResyncPeriod.Duration, fmt.Sprintf("How often each DNS is reconciled when nothing changes (default %v).", operatorconfig.DefaultResyncPeriod))
+	return flags
+}
+
+// loadConfig builds the operator configuration from, in increasing order of
+// precedence, the config file if one is given, the environment, and the
+// flags that were set.  The configuration is defaulted and validated.
+func loadConfig(configFile string, flags *flag.FlagSet) (operatorconfig.Config, error) {
+	var config operatorconfig.Config
+	if len(configFile) != 0 {
+		loaded, err := operatorconfig.Load(configFile)
+		if err != nil {
+			return config, err
+		}
+		config = loaded
+	}
+
+	if err := applyEnv(&config); err != nil {
+		return config, err
+	}
+
+	// Copy the flags that were set onto the configuration by setting them
+	// again on flags bound to it.
+	var ignored string
+	bound := startFlags(&config, &ignored)
+	var flagErr error
+	flags.Visit(func(f *flag.Flag) {
+		if err := bound.Set(f.Name, f.Value.String()); err != nil && flagErr == nil {
+			flagErr = fmt.Errorf("invalid value for flag -%s: %v", f.Name, err)
+		}
+	})
+	if flagErr != nil {
+		return config, flagErr
+	}
+
+	config.SetDefaults()
+	if err := config.Validate(); err != nil {
+		return config, fmt.Errorf("invalid configuration: %v", err)
+	}
+	return config, nil
+}
+
+// applyEnv sets the fields of config for which environment variables are
+// set.
+func applyEnv(config *operatorconfig.Config) error {
+	for name, field := range map[string]*string{
+		"IMAGE":               &config.CoreDNSImage,
+		"COREDNS_VERSION":     &config.CoreDNSVersion,
+		"OPENSHIFT_CLI_IMAGE": &config.OpenshiftCLIImage,
+		"RELEASE_VERSION":     &config.OperatorReleaseVersion,
+		"LOG_LEVEL":           &config.LogLevel,
+		"LOG_FORMAT":          &config.LogFormat,
+		"LEADER_ELECTION_ID":  &config.LeaderElectionID,
+		"OPERATOR_NAMESPACE":  &config.OperatorNamespace,
+	} {
+		if value := os.Getenv(name); len(value) != 0 {
+			*field = value
+		}
+	}
+	if value := os.Getenv("COREDNS_PLUGINS"); len(value) != 0 {
+		if err := (*listValue)(&config.CoreDNSPlugins).Set(value); err != nil {
+			return fmt.Errorf("invalid COREDNS_PLUGINS environment variable: %v", err)
+		}
+	}
+	if value := os.Getenv("LEADER_ELECTION"); len(value) != 0 {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid LEADER_ELECTION environment variable: %v", err)
+		}
+		config.LeaderElection = enabled
+	}
+	return nil
+}
+
+// listValue is a flag of comma-separated values.  Setting it replaces the
+// values.
+type listValue []string
+
+func (v *listValue) String() string {
+	return strings.Join(*v, ",")
+}
+
+func (v *listValue) Set(value string) error {
+	*v = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) != 0 {
+			*v = append(*v, item)
+		}
+	}
+	return nil
+}
@@ -2,9 +2,18 @@
 // sources:
 // assets/dns/cluster-role-binding.yaml (223B)
 // assets/dns/cluster-role.yaml (210B)
-// assets/dns/configmap.yaml (390B)
-// assets/dns/daemonset.yaml (4.302kB)
-// assets/dns/namespace.yaml (189B)
+// assets/dns/configmap.yaml (3.106kB)
+// assets/dns/daemonset.yaml (4.912kB)
+// assets/dns/deployment.yaml (771B)
+// assets/dns/metrics-role-binding.yaml (292B)
+// assets/dns/metrics-role.yaml (301B)
+// assets/dns/metrics-service-monitor.yaml (266B)
+// assets/dns/metrics-service.yaml (287B)
+// assets/dns/namespace.yaml (286B)
+// assets/dns/node-local-cache-configmap.yaml (845B)
+// assets/dns/node-local-cache-daemonset.yaml (4.659kB)
+// assets/dns/poddisruptionbudget.yaml (229B)
+// assets/dns/prometheus-rule.yaml (235B)
 // assets/dns/service-account.yaml (85B)
 // assets/dns/service.yaml (306B)
 
@@ -75,7 +84,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _assetsDnsClusterRoleBindingYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xce\x31\x8e\x83\x40\x0c\x05\xd0\x7e\x4e\xe1\x0b\xc0\x6a\xbb\xd5\x74\x9b\xdc\x80\x48\xe9\xcd\x8c\x09\x0e\x60\xa3\xb1\x87\x22\xa7\x8f\x10\x4a\x45\x3a\x17\xfe\xff\xfd\x89\x25\x47\xb8\xce\xd5\x9c\x4a\xa7\x33\x5d\x58\x32\xcb\x23\xe0\xca\x77\x2a\xc6\x2a\x11\x4a\x8f\xa9\xc5\xea\xa3\x16\x7e\xa1\xb3\x4a\x3b\xfd\x59\xcb\xfa\xb3\xfd\x86\x85\x1c\x33\x3a\xc6\x00\x00\x20\xb8\x50\x04\x5d\x49\x6c\xe4\xc1\x9b\x2c\x16\xac\xf6\x4f\x4a\x6e\x31\x34\x70\x78\x37\x2a\x1b\x27\xfa\x4f\x49\xab\x78\xf8\xc4\xf6\xe7\xe3\xb6\x15\xd3\xa9\xa7\xe8\x4c\x1d\x0d\x3b\x74\x9a\x1d\xbe\xd3\xef\x00\x00\x00\xff\xff\xfa\x62\xe7\x50\xdf\x00\x00\x00")
+var _assetsDnsClusterRoleBindingYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xce\x31\x8e\x83\x40\x0c\x05\xd0\x7e\x4e\xe1\x0b\xc0\x6a\xbb\xd5\x74\x9b\xdc\x80\x48\xe9\xcd\x8c\x09\x0e\x60\xa3\xb1\x87\x22\xa7\x8f\x10\x4a\x45\x3a\x17\xfe\xff\xfd\x89\x25\x47\xb8\xce\xd5\x9c\x4a\xa7\x33\x5d\x58\x32\xcb\x23\xe0\xca\x77\x2a\xc6\x2a\x11\x4a\x8f\xa9\xc5\xea\xa3\x16\x7e\xa1\xb3\x4a\x3b\xfd\x59\xcb\xfa\xb3\xfd\x86\x85\x1c\x33\x3a\xc6\x00\x00\x20\xb8\x50\x04\x5d\x49\x6c\xe4\xc1\x9b\x2c\x16\xac\xf6\x4f\x4a\x6e\x31\x34\x70\x78\x37\x2a\x1b\x27\xfa\x4f\x49\xab\x78\xf8\xc4\xf6\xe7\xe3\xb6\x15\xd3\xa9\xa7\xe8\x4c\x1d\x0d\x3b\x74\x9a\x1d\xbe\xd3\xef\x01\x00\xfa\x62\xe7\x50\xdf\x00\x00\x00")
 
 func assetsDnsClusterRoleBindingYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -95,7 +104,7 @@ func assetsDnsClusterRoleBindingYaml() (*asset, error) {
 	return a, nil
 }
 
-var _assetsDnsClusterRoleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2c\x8d\xbd\x6e\xc5\x30\x08\x46\x77\x3f\x05\xba\x7b\x52\x75\xab\xbc\x76\xe8\xde\xa1\x3b\xd7\xa6\x0a\x8a\x03\x16\xe0\x54\xea\xd3\x5f\xe5\x67\x3b\xe7\x08\xf4\xad\x2c\x35\xc3\x67\x1b\x1e\x64\xdf\xda\x28\x61\xe7\x1f\x32\x67\x95\x0c\xf6\xc4\x32\xe3\x88\x45\x8d\xff\x31\x58\x65\x5e\x3f\x7c\x66\x7d\xdb\xdf\xd3\x46\x81\x15\x03\x73\x02\x10\xdc\x28\x83\x76\x12\x5f\xf8\x37\xa6\x2a\x9e\x6c\x34\xf2\x9c\x26\xc0\xce\x5f\xa6\xa3\xfb\x71\x39\xc1\xe3\x91\x00\x8c\x5c\x87\x15\xba\x1b\x49\xed\xca\x12\x7e\x9a\x93\xed\x5c\xe8\x92\xae\xf5\x82\x63\xc3\x3b\x5e\x7d\x27\x7b\xde\xbf\x8d\x3d\x4e\xf8\xc3\x28\x4b\x7a\x05\x00\x00\xff\xff\xcb\xdd\xd7\x2a\xd2\x00\x00\x00")
+var _assetsDnsClusterRoleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2c\x8d\xb1\x4e\x04\x31\x0c\x44\x7b\x7f\x85\x75\x7d\x16\xd1\xa1\xb4\x14\xf4\x14\xf4\xbe\xc4\x68\xad\xcb\xd9\x91\xed\x2c\x12\x5f\x8f\x8e\xbd\x6e\xe6\xe9\x8d\xe6\x26\xda\x2b\xbe\x8f\x15\xc9\xfe\x69\x83\x81\xa6\x7c\xb1\x87\x98\x56\xf4\x2b\xb5\x8d\x56\xee\xe6\xf2\x4b\x29\xa6\xdb\xed\x2d\x36\xb1\x97\xe3\x15\xee\x9c\xd4\x29\xa9\x02\xa2\xd2\x9d\x2b\xda\x64\x8d\x5d\xbe\xb3\x74\x0d\xf0\x35\x38\x2a\x14\xa4\x29\x1f\x6e\x6b\xc6\xc3\x2c\x78\xb9\x00\xa2\x73\xd8\xf2\xc6\x4f\xc6\xda\xa7\x89\x66\xfc\x1b\xc1\x7e\x48\xe3\xb3\x4c\xeb\x67\x78\x7c\xc4\xa4\x93\x1f\xec\xd7\xe7\x76\x48\x24\x20\x16\xfc\xa1\x6c\x3b\xfc\x0d\x00\xcb\xdd\xd7\x2a\xd2\x00\x00\x00")
 
 func assetsDnsClusterRoleYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -115,7 +124,7 @@ func assetsDnsClusterRoleYaml() (*asset, error) {
 	return a, nil
 }
 
-var _assetsDnsConfigmapYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8c\x4f\x4b\x33\x31\x18\xc4\xef\xfb\x29\x06\xde\xf3\xbb\xb5\x2c\x2b\xb8\xd7\x9e\xbd\x7a\x7f\x4c\x66\x9b\xd0\x6c\x12\x9e\x24\x45\x51\xbf\xbb\xb4\x85\x68\xc1\x39\xcd\x3f\x7e\x27\x1f\xed\x82\x43\x8a\xab\x3f\x3e\x4b\x1e\x24\xfb\x17\x6a\xf1\x29\x2e\x38\xef\x87\x7f\x88\xb2\x11\x12\xed\xd5\x94\x2c\x86\x10\x25\x0a\x2b\xa4\x42\x5b\xac\x7e\xe3\x60\xa5\xca\x32\x00\x87\xa4\x5c\x7d\xe0\x82\xcf\x01\x00\xc6\x65\x9e\xe6\x09\x1f\xd7\x70\x11\x55\x93\x96\x1e\x1d\x25\x54\xd7\xe3\xa9\xbd\x52\x23\x2b\x0b\x4c\x68\xa5\x52\xc7\x90\x8c\x04\xf8\xf8\x5f\xac\xd5\x51\x34\x0b\x7c\x7e\xbc\x99\x1f\xec\x45\x39\xd9\x02\x1f\x0b\x4d\x53\xde\x2d\x2d\x97\xaa\x94\xed\xae\x5c\x25\x84\xea\x34\xb5\xa3\xfb\x1b\xdf\xdf\x5f\xdd\x65\x4d\x1b\xab\x63\x2b\x58\x9e\xf6\xf3\xf4\x7b\x78\x7b\xc7\x88\x1d\xab\xd9\x29\x4b\x0a\xe7\xd1\xa4\xb8\xf6\x83\x11\xe3\x88\xe9\xa1\x17\xca\x90\xc4\x0e\x37\xfe\x77\x00\x00\x00\xff\xff\xef\xe8\x25\x0a\x86\x01\x00\x00")
+var _assetsDnsConfigmapYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x56\x6d\x6f\xdb\x36\x10\xfe\xee\x5f\x71\x70\x02\xb4\x05\x1a\xb5\x45\x96\x00\x33\x30\x60\x5a\x92\x01\xc1\x12\x27\x73\xb2\xa2\xdd\x97\x80\x15\xcf\x11\x11\x89\x24\x48\x2a\x4e\xc6\xf1\xbf\x0f\x94\x28\x9a\xb2\xec\x2c\x1b\x0a\x13\xb0\x74\xf7\xdc\xdb\xc3\xe3\x51\x0f\x8c\xd3\x19\x9c\x08\xbe\x64\xf7\x97\x44\x4e\x88\x64\x9f\x51\x69\x26\xf8\x0c\x1e\x3f\x4d\xf6\x80\x93\x1a\x81\x70\xda\x3e\x68\x49\x0a\x04\xa2\x10\x34\x1a\x20\x06\x54\xc3\x0d\xab\x71\x42\x89\x21\xb3\x09\xc0\x1e\x9c\x08\x85\x4b\x56\x21\x30\x0d\x04\x0c\xd6\xb2\x22\x06\xc1\x94\xc4\x78\x91\x42\x4e\x51\x21\x4d\x8d\x21\x1a\xcd\xe0\xef\x09\x00\x80\xb5\x07\x40\x71\xc9\x38\xc2\x54\x8a\x8a\x15\xcf\x53\xe7\xa2\x66\xc5\x4c\x09\xd9\x82\x18\xbc\x60\x35\x33\x41\xe3\x97\x22\x06\x2b\x2f\x03\x6b\xb3\xdf\x1b\x54\x0c\xf5\x35\xaa\x1b\x2c\x04\xa7\xce\x59\xcb\x96\x90\x9d\x3d\x61\x2d\xcd\xc9\xf9\xe9\x42\x3b\x07\x36\x1a\xfb\xb5\x2a\x99\xf7\xa0\x8d\xb5\x8a\xf0\x7b\x1c\xa1\x6d\xe6\xdd\x20\xa7\x49\xd4\x81\xc0\xa7\x3e\x7c\x0b\x8e\xf2\x93\x8b\x20\xf4\x8b\x14\x55\x0c\xf1\xa7\xe0\xb8\xe9\x7c\x23\x2f\x6b\xb3\xbc\x30\x4c\xf0\xbe\x08\x5f\xdc\xf3\xed\xb3\x6c\x0d\xcd\xb3\xc4\xe8\x6d\xa0\x49\x5d\xc6\x3f\x6f\x7f\x23\x1a\x55\x60\x5f\x16\xc7\x75\xc1\x43\xcd\x16\x07\x31\xb1\x7f\x29\xf8\x97\x4a\x14\x0f\x9e\x4b\xdd\x07\x3d\x15\x35\x61\x5c\x27\x2e\xf6\xe0\x5b\x8f\xf2\x5b\x36\x27\x35\x26\x5a\xef\xcc\xdb\xcd\xbf\x74\x96\x89\x2a\x36\x56\x3e\xff\x0a\xf9\xfc\x6b\x4c\x3f\xc6\x78\x89\x4e\x55\x08\x8a\x30\xff\x72\x7a\x75\x99\x9f\xcf\xa3\x6a\x18\x19\x2b\x8d\xdb\x22\x9e\xcf\xa1\x2b\xe7\xfc\xfa\xf1\x07\xe7\x72\x6b\x3b\x64\x9e\xe7\x79\x08\xf7\xdf\xb2\x21\x5c\xaf\x50\xc1\xd4\xda\xa9\xb5\xd0\x92\x00\xce\x4d\x9d\x83\xe3\x8f\xa3\x68\xde\x51\x17\x38\x8d\x1b\xa4\xc7\x31\xc4\x74\x4b\x51\xdf\x85\xb3\xab\xb3\xc5\xe2\x6a\xb1\xc5\xfb\xb8\x0f\x46\x4d\x33\x86\xf4\x87\x5c\xe1\x4a\x31\x83\x3a\x3d\xe6\x21\xb9\x20\xe9\xa5\x9e\xf8\xbc\x25\xec\x57\x25\xea\x44\x19\x5c\x80\x36\x42\x6e\xa4\xee\x47\x97\x2f\xed\x92\x98\xa2\xec\xaa\xec\x8c\xfd\xd3\xad\x70\x6e\xdb\x76\xf4\x46\x69\xb0\xf5\xfb\xc0\xea\xc5\xbe\x19\xe4\xf5\xfa\x4c\xc6\x5c\xbd\x86\x47\x8d\xea\x11\x95\x9e\xc2\x41\x50\x66\xb3\xa3\xc3\xa3\xc3\x84\x0f\x8f\x8e\x8d\x30\x7d\x64\xb8\x9a\x42\xf6\x99\xe1\x2a\x18\xf8\x85\x4a\x09\xa5\x77\x98\x84\x79\x3c\xda\x98\x35\x22\x54\xac\xa7\x90\x2d\xc2\x63\x82\x2d\x91\x54\xa6\xec\x4e\xd0\x05\xa9\xf1\xb4\x29\x1e\x4e\x1b\x45\xba\xe9\xb6\xb1\x73\x15\xa9\x91\x36\xc5\x83\xe7\x6c\x0c\x8e\xd0\x41\x97\xf5\x09\xf9\x00\x0b\x24\xf4\x39\x91\x2b\xff\x3e\x40\x6d\xb7\xfb\xad\xf9\x86\x8a\xa3\x41\x9d\xe5\x8d\x11\x92\x98\x32\x41\x91\x20\x82\x9f\x1f\x22\xee\x05\xa7\x6b\x90\x2f\xe3\xa4\x6a\xb4\x41\xd5\x4f\x34\x60\xfc\x80\x50\xaa\x32\xa2\x24\x01\x26\x8f\xbb\x87\x21\x0d\x52\xd0\xd6\xf6\x5a\x50\x7d\x29\x68\xda\x5e\x7d\xc8\xee\x52\x5c\xe7\xdd\xcf\xdc\x79\x7f\x6d\xa7\x63\xb7\x3f\x14\x9d\x22\xce\x81\x14\x3b\x18\x05\xf1\xcf\x7b\x3c\xe3\x54\x0a\xc6\xcd\xb5\xa0\xad\xc1\x86\x5f\x0c\xea\x3b\x29\xe8\x5d\x1b\x64\x60\x7d\x7b\x9b\x5e\x83\xfe\x67\x4c\xe5\x83\xb5\x8a\x08\x1d\x32\xd8\x17\xe9\xc3\x5f\x2b\xf1\x94\x6e\xa9\xff\x35\x52\x1b\x85\xa4\x1e\x19\x8c\xdd\x2c\x49\x55\x99\x52\x89\xe6\xbe\xdc\x4e\x7d\x44\xaf\xed\xa4\x12\x35\x9a\x12\x1b\x0d\xb3\x1f\x3f\x1d\x1d\x46\x85\xb5\x49\x46\xd2\x27\xd6\x0f\xe5\xa5\x50\x2b\xa2\x68\x28\x04\x32\xf8\x80\xa6\xf8\xa0\x50\x8b\xea\x31\x2b\x04\x5f\x46\x1f\x05\x29\x4a\x84\xc3\x8f\x51\xa0\xb0\x12\x84\x4e\xd6\x29\x24\xc3\xf0\xa6\x3b\xdf\xa1\xa6\xbd\x8d\x3b\xb3\xdf\xc8\x7d\xf6\x1e\xf6\xff\x12\x1c\x61\xf6\x53\xfc\xbc\x68\x73\xdd\x67\xed\xce\x06\x92\x5b\x8c\x73\xed\x88\xe8\x33\xb5\x3b\x0e\x75\x37\x29\xf6\xff\xd7\xa8\xd8\x77\x6e\x07\xe2\xe5\x51\xd1\xa5\xfc\x2a\x7e\x63\x0f\xff\x11\x5a\x61\xb3\x85\xb7\xf3\xed\x26\xe3\x4e\xd9\x3d\x5d\x5b\x0e\x12\x79\x77\xe6\x82\xc0\x2f\x0f\x58\xef\xc9\xc6\x21\xc6\x27\xa9\x80\xf1\x82\x51\xf5\xb6\xa8\x18\x72\x73\xc7\xe4\xdb\x77\xef\xe1\x8d\xb5\xc9\x57\x97\x73\x6f\xde\x45\xbb\x5d\x19\x21\xa7\x70\x30\xbe\x2b\xfd\xe6\xf4\x04\x0e\x89\x8e\x17\x43\x9f\xae\xb5\x9b\x3e\x76\x80\xff\x19\x00\xfd\x80\x50\x03\x22\x0c\x00\x00")
 
 func assetsDnsConfigmapYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -130,12 +139,12 @@ func assetsDnsConfigmapYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/dns/configmap.yaml", size: 390, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x60, 0x35, 0x3d, 0x20, 0x71, 0xe4, 0x2d, 0x28, 0xd6, 0xac, 0xd6, 0xef, 0x59, 0x0, 0x1, 0x68, 0xee, 0x6, 0x5b, 0xde, 0x33, 0x4d, 0xdf, 0xf1, 0x31, 0xbd, 0x16, 0xd1, 0xfc, 0xec, 0x7e, 0x62}}
+	info := bindataFileInfo{name: "assets/dns/configmap.yaml", size: 3106, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x96, 0xf5, 0xc0, 0xa, 0xeb, 0xd5, 0xb4, 0xe0, 0xe1, 0x30, 0xbf, 0x58, 0xda, 0xb2, 0x57, 0x59, 0xde, 0xde, 0x20, 0x6b, 0x87, 0x3, 0x9c, 0x7a, 0x93, 0x95, 0xf8, 0x27, 0x98, 0x28, 0xeb, 0x51}}
 	return a, nil
 }
 
-var _assetsDnsDaemonsetYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x57\x5f\x6f\xe3\xb8\x11\x7f\xf7\xa7\x98\x93\x83\xcd\x2e\x1a\x25\x4e\x0f\x7b\xbd\x6a\x37\xd7\x4b\x13\xa7\x1b\xf4\x9c\x18\xb1\xf7\xfa\x10\x04\x0b\x9a\x1a\x59\x6c\x28\x92\x47\x8e\xe4\x35\xb2\xf9\xee\x05\xa5\xc8\xa6\x64\x37\x8b\x02\xcd\x43\x6c\x73\x86\x3f\xce\xbf\xdf\x70\xf8\x28\x54\x9a\xc0\x25\xc3\x42\xab\x19\xd2\x80\x19\xf1\x3b\x5a\x27\xb4\x4a\x80\x19\xe3\x4e\xaa\xd3\xc1\x10\x14\x2b\xf0\xa8\xfe\xef\x0c\xe3\x08\x4c\xa5\x20\xd9\x02\xa5\x03\x66\x11\x1c\x12\x30\x02\x5b\x2a\x12\x05\x0e\x9c\x41\x9e\x0c\x00\x08\x0b\x23\x19\xa1\xff\x0e\xd0\xae\xd6\xdf\xd1\x56\x82\xe3\x39\xe7\xba\x54\x74\xc3\x0a\x4c\x20\x55\xee\x45\x6a\xac\xd0\x56\xd0\xfa\x42\x32\xe7\x1a\xa1\x5b\x3b\xc2\x22\x56\x3a\xc5\x98\x5b\x41\x82\x33\xf9\xa2\xcd\xb5\x22\x26\x14\x5a\xd7\xa2\xc7\xb5\xa5\x21\x22\xc0\x10\x44\xc1\x96\x08\xc2\xf5\xad\x6d\x35\x6a\xf9\xb4\x94\x72\xaa\xa5\xe0\xeb\x04\xae\xb3\x1b\x4d\x53\x8b\x0e\x15\x6d\xb4\xb8\x2e\x0a\xe6\x43\x76\x0f\x11\xd7\x16\x53\xe5\x22\x78\xd8\x88\x99\x5d\xba\x5a\x16\x73\xad\xb2\xe8\x08\xa2\x13\x24\x7e\xf2\xa2\x79\x72\xa1\x2d\x66\x42\x62\xb8\xa5\xd2\xb2\x2c\x70\xe2\x03\xb1\xf1\x60\xeb\x83\x87\x11\xcb\xb8\x51\xda\x48\x01\x0a\xaf\x3f\x65\x94\x27\x10\x9e\x10\x68\x58\x64\xe9\xad\x92\xeb\x04\xc8\x96\xdb\xad\x46\xdb\xee\x39\x9b\xf8\x4d\xb5\xa5\x04\xde\xff\xf8\xfe\xc7\x00\x65\x37\x92\x3e\x3f\x9a\x34\xd7\x32\x81\xcf\x97\xd3\xff\x1d\x29\x26\x6e\xf6\xa2\xcd\x2f\x5e\x41\xfb\xeb\xe9\x1e\xb4\x02\xc9\x0a\xbe\xdf\xb6\x10\x4d\x8a\x0a\x15\x3a\x37\xb5\x7a\x81\x49\xa0\x9e\x13\x99\x7f\x20\x85\x4b\x00\xa6\x09\x6b\x8e\x4c\x52\xde\x95\xd4\xa6\xfc\x3c\xfa\x79\xd4\x59\x76\x3c\x47\x6f\xce\xa7\xf9\x7c\x1a\x08\x84\x12\x24\x98\xbc\x44\xc9\xd6\x33\xe4\x5a\xa5\x2e\x81\x9f\xc2\xad\xbe\x00\x75\x49\x1b\xe1\xfb\x40\xe6\x4a\xce\xd1\xb9\x79\x6e\xd1\xe5\x5a\xa6\x09\x9c\x06\xd2\x8c\x09\x59\x5a\x0c\xa4\xdb\xbd\x16\x9d\x2e\x2d\x47\x17\xba\x25\x45\x21\xc8\x75\x1d\x2d\xb0\xd0\x76\x9d\xc0\xfb\xd3\x3f\x4f\x44\xa7\x74\xfe\x28\xd1\xf5\xb5\xb9\x29\x13\x38\x1d\x8d\x8a\xbd\x18\x7f\x19\x6d\x20\x02\xfe\x35\x8c\xf5\x06\xc9\x0a\xed\xff\x9d\x8d\x0e\x79\x59\x77\x0a\xad\x08\xbf\x76\xd2\x68\xac\xa8\x84\xc4\x25\xa6\x3d\x02\xbc\xce\xb7\x5c\x3b\x72\xb1\xe7\xe8\x2b\x64\xab\x95\x02\x67\x50\x55\x70\x73\x3e\x19\xcf\xc6\x77\xbf\x8f\xef\xea\xee\x78\xf1\xdb\xe7\xd9\x7c\x7c\xf7\xe5\xf2\x76\x72\x7e\x7d\xb3\xaf\x4b\xb6\xdb\x51\x55\xbb\x66\x78\xa4\xeb\x8b\xf1\x2c\x30\x62\x08\x17\xbe\xf7\x80\xb6\xd0\x34\x61\x87\x86\x59\x46\x98\x82\x14\x8e\x40\x67\x6d\x5b\x75\x9d\x5d\x37\xb7\xf3\x71\x02\x57\xda\x82\xd2\xab\x23\x40\xe5\x4a\x8b\x40\x39\x3a\xac\xcd\xb2\x28\x19\x89\x0a\x9b\xf6\xfe\x01\x32\x6d\x01\x19\xcf\xbb\x82\xa3\x0e\x26\x53\xc0\xa4\x60\x0e\x56\x82\x72\x8f\xd5\xf7\xd7\x95\x59\x26\xbe\xc2\x4a\x48\x09\x4c\x3a\x0d\x0b\x04\x96\xa6\x98\x1e\x07\x38\x15\x93\x25\x26\x10\xd5\xb9\x8e\x2d\x2e\x85\x23\xbb\x3e\xd6\x06\x95\xcb\x45\x46\x71\x4f\xe0\x2a\x1e\xed\x34\xe2\x20\x74\x27\x0b\xa1\x4e\x16\xcc\xe5\xc1\x5a\xcc\x83\x1f\xdf\x42\x27\x7e\xd8\x55\x87\x3a\x47\x71\xa9\xc1\x08\x83\x9e\x62\x83\x90\xac\x96\x19\x38\x7c\xf4\x2e\x1d\xbc\xfd\xb7\x5e\x38\x88\xcd\xbb\x0f\x80\x5f\x05\xc1\xe8\x10\xe6\xe3\xbb\x49\xa8\x7e\x3b\x1d\xdf\xcc\x3e\x5d\x5f\xcd\xbf\x4c\xce\xef\xfe\x39\xbe\x3b\x8b\xb6\x8e\x2d\x51\x61\x9d\xba\x2e\x3f\xa2\x60\xfb\xa7\xdb\xd9\x7c\xf6\xe5\xea\xfa\xb7\xf1\x59\xb4\x2d\xba\x50\x63\x3e\x9e\x4c\x77\x14\x8e\xa9\x30\x51\x68\xc6\xf5\xd5\xec\xec\xf0\x08\x0e\xeb\xfb\x00\x62\x0b\x31\xdb\xd4\x09\x7c\xfc\xf8\x11\xa2\x83\xa7\xb6\xda\x9e\x3b\x3b\x87\x30\x61\x8f\x08\xac\xbe\xc7\xb5\x65\x76\x0d\x9e\x17\xdb\x9c\x6b\x99\x36\x7c\xa9\xd7\x0f\x1d\x30\x22\x2b\x16\x25\xa1\x0b\xd3\xcc\x0d\xc4\x19\xc4\xf1\x56\x1a\x6b\x25\xd7\xfe\xe0\xad\x93\xcf\x91\xff\xbd\x71\xa9\x6b\xc9\x2a\xf7\xe7\x7a\x16\x7f\x80\x54\x77\xda\x4f\x8a\x5c\xfa\x2a\x8e\xcf\xc1\x55\xfc\x8b\x30\xae\x23\xf6\xc5\xec\x2a\x0e\x42\x79\xf8\xd6\xef\xfb\x5f\x1f\x9e\xa3\x1d\x28\xef\xf1\x15\x12\xcf\xdb\xf8\xc0\xf5\x14\x32\xab\x0b\xe0\xb2\x74\x84\xd6\x37\x34\x10\x19\x98\x5e\x17\x6a\xfe\x84\x71\x67\x6f\x0f\xde\xa6\x62\x09\xbf\x46\x07\x4f\xdb\x7e\xf0\x1c\xc1\x9f\x5c\xae\x2d\xd5\x26\x54\xfc\xf9\xf8\xe0\xa9\x4b\x97\xe7\xe8\xdd\xbb\x41\x1f\x2e\x83\xfb\x7b\x88\x0e\xfe\x16\x41\x8c\x7f\xc0\x08\xde\xbc\xf1\xfb\x87\xc2\x34\xe6\x43\xac\x10\x46\xf0\xf0\xf0\xc1\xe7\x42\xf5\x76\x43\x1b\x8d\xfb\x97\x33\xa3\x87\xb3\xe8\xe0\xa9\xdd\xdc\xd3\xce\x44\x37\xa6\x5a\x61\xd7\x9c\x21\x7c\x36\x29\x23\x0c\xda\x1f\xd4\x39\x14\x19\xac\x10\x96\x48\x9e\xcc\x22\x0d\x22\xe7\x7a\x00\xff\xc2\xa6\x1b\x28\x4d\x50\xee\x80\xad\x72\x54\xde\x0f\x5b\xdf\x09\x2f\xc3\xcc\x06\x4d\x97\xe4\x6f\x0b\x6d\x81\x19\x01\xa5\x62\x15\x13\x92\x2d\x84\x14\xb4\xee\x1d\x33\x23\x26\x11\x50\x91\x15\xe8\x81\x4a\x99\x7a\x86\x3a\xf2\x25\x10\x1c\x28\xb2\xba\x84\xdb\x13\x84\x83\x14\x25\x12\xa6\x83\x7d\x49\x78\x1a\xb6\xe1\xfc\x7e\xe8\x87\xf0\xf7\x52\xc8\x14\x18\x28\x5c\x05\xfc\x68\x4a\x29\xf4\xd9\xf3\x48\x97\x16\x78\xe9\x48\x17\x1b\xa3\x33\x21\x09\x2d\xa6\xde\xed\x1e\xf6\xd2\xa2\x81\xb8\x82\x68\x08\x07\x4f\xfd\x06\xd3\x50\xa8\x43\xa9\x5f\x5e\x21\x55\x63\xeb\xb9\x31\xa8\x52\x68\x3b\xd0\xd6\x08\x4f\x9c\xdd\xeb\x04\x76\x38\xf5\x43\x18\x99\x3d\x9c\x6a\xf4\x85\xf1\xea\x75\x29\xd6\xca\x4d\x51\x3e\x3c\xef\xdd\x00\x80\x3c\xd7\x50\xd7\xeb\x73\xb3\xa9\xfd\xd8\xa5\x0e\xfc\x97\x50\xfc\xb2\xe3\x7b\xff\x90\xba\xcc\xf7\x2c\xed\xc4\x68\x7e\x7b\x79\x9b\xec\x61\x00\x23\x5d\xf8\x87\x88\x5c\x03\x69\x60\x95\x16\x29\x30\xb5\x06\xa1\xb8\x56\x4e\x38\x42\x45\xb0\xc0\x9c\x55\x42\xdb\x1d\xd4\x3b\x34\xd2\x5f\xe3\xfb\x2a\xa2\xd0\xa9\xc8\x04\xa6\x50\x35\x6f\x31\x5f\x88\x0a\x31\xed\x95\x27\x00\x2f\x4c\xcf\xcd\x9d\x1a\xf8\xf6\xed\xa5\x03\xbf\xae\xb7\xeb\x75\xab\xeb\xc9\xe1\x59\x6b\xb1\xd0\x15\xa6\x5b\x5f\xeb\xaa\xe6\x16\x19\xe1\x49\xc3\x9e\x7a\xf4\xd9\xf6\x79\xe0\xda\xac\x81\xe7\xa5\xed\x92\xa4\xd7\x6f\x9c\x44\x34\xf0\xd3\x08\xde\xc0\x8a\x89\x6e\xcd\x97\xca\x5f\xc9\xbb\xad\xbd\x93\xbc\xbd\x03\xef\xab\x43\x6c\x3b\xc3\xa6\xca\xb5\x03\xe6\x25\x66\xac\x94\xed\xe9\xfe\x5e\x9e\xa1\x44\x4e\xda\x6e\x11\x16\x48\xec\xf8\xb1\x5c\xa0\x55\xe8\x6f\x39\xa1\x4f\xb4\x4b\x40\x0a\x55\x7e\x6d\x34\x5e\x54\x9b\x21\x73\x7b\xf6\x77\x9e\x73\xcd\xf2\x84\x99\xc0\xd8\x21\xf8\xa7\xef\x2b\x23\xb2\xef\x4f\x84\x45\xd7\xc1\x18\x1e\x71\x9d\x40\xfb\xd0\xec\x66\xb5\x79\xd8\xf4\x65\xaf\x8d\xbe\x7e\xad\x1e\x7c\x07\x3b\x30\x7b\x26\x61\x3f\x24\xad\x0d\x26\x70\xb5\x05\x21\x2d\xfd\xa8\x23\xb4\xda\x18\x3a\x6c\x17\x11\x98\x94\xe0\x5f\x79\xe4\xc0\x69\xa0\x9c\x11\x5c\xde\xcc\xbc\xd3\x4c\xae\xd8\xda\xb5\x77\x2d\x68\x55\xeb\xfa\xb4\xb8\xcd\x33\x43\x1b\x8f\xa2\x6d\x02\x63\xdf\xe2\xdd\xe0\x3f\x01\x00\x00\xff\xff\x02\xd7\x8f\xdd\xce\x10\x00\x00")
+var _assetsDnsDaemonsetYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x58\xfb\x53\x1b\x39\x12\xfe\xdd\x7f\xc5\xb7\x63\x2a\x8f\xba\x4c\x80\x4b\x65\x2f\x37\x49\xf6\x96\x03\xb3\xa1\x6e\x01\x17\x76\xf6\x7e\xa0\xa8\x94\xac\x69\x63\x1d\x1a\x49\x2b\x69\x06\x5c\x84\xff\xfd\x4a\x33\x9e\x97\xed\x38\xf7\xc4\x2e\xca\x56\xb7\xbe\x7e\xa8\xfb\x53\x8f\xef\x84\x4a\x13\x9c\x30\xca\xb4\x9a\x90\x1f\x30\x23\x7e\x23\xeb\x84\x56\x09\x98\x31\x6e\xbf\x38\x1c\x0c\xa1\x58\x46\xaf\xca\xff\xce\x30\x4e\x60\x2a\x85\x64\x33\x92\x0e\xcc\x12\x1c\x79\x30\x0f\x9b\x2b\x2f\x32\x1a\x38\x43\x3c\x19\x00\x9e\x32\x23\x99\xa7\xf0\x19\xa8\x57\xc3\xcb\x91\x2d\x04\xa7\x23\xce\x75\xae\xfc\x05\xcb\x28\x41\xaa\xdc\x4a\x6a\xac\xd0\x56\xf8\xe5\xb1\x64\xce\x55\x42\xb7\x74\x9e\xb2\x58\xe9\x94\x62\x6e\x85\x17\x9c\xc9\x95\xf6\x10\x9e\x6c\x26\x14\xf3\x42\xab\x5f\x2c\xe3\x34\x26\x2b\x74\x3a\x21\xae\x55\xea\x20\xdc\x9a\x7f\x98\x5b\x9d\xc1\x2f\x08\x92\x65\x94\xe6\xfc\x0e\x69\x6e\xcb\xed\x2b\x48\xae\x95\x67\x42\x91\x75\xb5\xc3\x31\xd4\x9a\x93\xc0\x10\x22\x63\xb7\xb4\x69\xa0\xd1\x28\xe5\xe3\x5c\xca\xb1\x96\x82\x2f\x13\x9c\xcd\x2f\xb4\x1f\x5b\x72\xa4\x7c\xa3\xc5\x75\x96\xb1\x70\x0a\xd7\x88\xb8\xb6\x94\x2a\x17\xe1\xa6\x11\x33\x7b\xeb\x4a\x59\xcc\xb5\x9a\x47\xaf\x10\xed\x93\xe7\xfb\x2b\xcd\xfd\x63\x6d\x69\x2e\x24\x75\xb7\x14\x5a\xe6\x19\x9d\x87\xdc\x36\x11\xb4\x31\x04\x18\x71\x1b\x57\x4a\x8d\x14\xc8\x82\xfe\x98\xf9\x45\x82\xae\x85\x8e\x86\x25\x96\x5e\x2a\xb9\x4c\xe0\x6d\xde\x6e\x35\xda\xf6\xed\x34\xf9\x1b\x6b\xeb\x13\xbc\x7d\xf3\xf6\x4d\x23\xc5\x96\x4c\x02\xc6\x6a\xaf\xb9\x96\x09\x3e\x9f\x8c\xff\x7d\xa4\xd8\x73\xb3\x15\x6d\x7a\xbc\x03\xed\xcf\x87\x5b\xd0\x32\xf2\x56\x70\xf7\x5d\x34\x29\x0a\x52\xe4\xdc\xd8\xea\xd9\xaa\xc2\xab\xf7\xc2\x7b\xf3\x0b\xf9\xee\x12\x60\xaa\xb4\x2e\x88\x49\xbf\xe8\x4b\x4a\x57\xde\x1d\xbc\x3b\xe8\x2d\x3b\xbe\xa0\x70\x58\x9f\xa6\xd3\xd6\x26\x20\x94\xf0\x82\xc9\x13\x92\x6c\xb9\x2a\xf0\x04\x3f\x76\xb7\x86\x02\xd4\xb9\x6f\x84\x6f\x3b\x32\x97\x73\x4e\xce\x4d\x17\x96\xdc\x42\xcb\x34\xc1\x61\x47\x3a\x67\x42\xe6\x96\x3a\xd2\x76\xef\xb0\x3c\x7b\xd1\x04\x8c\xdc\x91\xab\xe3\x81\x56\x65\x00\xdd\x1e\x13\xf3\xb2\xc3\x42\x75\x9e\x5c\x4c\xaa\x4e\xe9\xa0\x19\x4b\x29\xf3\xe4\x4a\xa5\x00\xbd\x84\x91\xf9\xad\x50\x8d\x4e\xdf\xde\xbf\x9a\xe0\xb0\x6b\xd9\x17\x54\xf9\x3d\x7c\x77\xf8\x1f\xe7\xf7\xb0\x9b\x5f\xd3\x25\x97\x04\x6f\x76\xa4\xfe\xcd\x7f\x91\xfa\x76\x6f\x48\xbd\xd3\xb9\xe5\x54\x91\x6d\x4a\x73\x96\x4b\x1f\x52\xc7\x3c\x32\xb6\xc4\x8c\xa0\x0b\xb2\x56\xa4\x29\xa9\x96\xdd\x52\xe5\x4a\xd2\x6d\x90\x1a\x9c\x6e\xea\xa4\xc8\x44\xb7\x7b\xc3\x2b\xa3\x4c\xdb\x65\x82\xb7\x87\x7f\x3c\x17\x1d\x89\xa5\xdf\x73\x72\xeb\xda\xdc\xe4\x21\x47\x07\xd9\x56\x8c\x3f\x1d\x9c\x8b\x4d\x12\xad\x98\x3c\x38\x24\x0b\xb2\xff\x73\x4a\x75\xc4\xf3\xf2\x06\xd1\xca\xd3\x43\xaf\x54\x8c\x15\x85\x90\x74\x4b\xe9\x1a\x8b\xed\x26\xcd\x85\x76\xde\xc5\x81\x68\x77\x30\x66\xa9\xd4\xc8\x87\x20\x55\xe0\xe2\xe8\x7c\x34\x19\x5d\xfd\x36\xba\x2a\x6f\xcd\xe3\x5f\x3f\x4f\xa6\xa3\xab\x2f\x27\x97\xe7\x47\x67\x17\xdb\x6e\xcf\x7a\x3b\xa9\x62\xd3\x8d\x80\x74\x76\x3c\x9a\x34\x82\x50\x1f\xc7\xe1\x02\x81\xb6\xa8\x2e\x67\x47\x86\x59\xe6\x29\x85\x14\xce\x43\xcf\xeb\xeb\xb6\x4b\x6a\x43\x5c\x5c\x4e\x47\x09\x4e\xb5\x85\xd2\xf7\xaf\x40\xca\xe5\x96\x42\x3f\x3a\x2a\xdd\xb2\x24\x99\x17\x05\x95\x87\xe6\xde\x63\xae\x2d\x88\xf1\x45\x5f\xf0\xaa\x87\xc9\x14\x98\x14\xcc\xe1\x5e\xf8\x45\xc0\x5a\x8f\xd7\xe5\xf3\xb9\x78\xc0\xbd\x90\x12\x4c\x3a\x1d\x6a\x97\xa5\x29\xa5\xaf\x3b\x38\x05\x93\x39\x25\x88\x4a\xd2\x88\x2d\xdd\x0a\xe7\xed\xf2\xb5\x36\xa4\xdc\x42\xcc\x7d\xbc\x26\x70\x05\x8f\x36\x6e\xd3\x66\x21\xc6\xfe\x4c\xa8\xfd\x19\x73\x2d\xf1\xc6\x88\x79\xe7\xcb\xd7\xe6\x33\x30\xfc\x61\x53\x3d\x0c\x2c\x1e\x71\xae\x61\x84\xa1\xd0\xac\x83\x8e\xcc\x5b\x66\xf0\xfc\x2e\x84\xb4\xf7\xe2\x1f\x7a\xe6\x10\x9b\x97\xef\x41\x0f\xc2\xe3\xe0\x39\xa6\xa3\xab\xf3\xae\xfa\xe5\x78\x74\x31\xf9\x74\x76\x3a\xfd\x72\x7e\x74\xf5\xb7\xd1\xd5\xc7\xa8\x0d\xec\x96\x14\x95\x47\xd7\xef\x8f\x36\x3a\xe0\xd3\xe5\x64\x3a\xf9\x72\x7a\xf6\xeb\xe8\x63\xd4\x16\x5d\x57\x63\x3a\x3a\x1f\x6f\x28\xbc\xf6\x99\x89\xba\x6e\x9c\x9d\x4e\x3e\x3e\x7f\x85\xe7\x25\xb1\x23\xb6\x88\x59\x53\x27\xf8\xf0\xe1\x03\xa2\xbd\xc7\xba\xda\x9e\x7a\x3b\x87\x38\x67\x77\x04\x56\xce\x77\xda\x32\xbb\x44\xe8\x8b\xf6\xcc\xb5\x4c\x51\x1a\x2d\xd7\x9f\x3b\x30\xef\xad\x98\xe5\x9e\x5c\xf7\x98\xb9\x41\x3c\x47\x1c\xb7\xd2\x58\x2b\xb9\x0c\x86\xdb\x20\x9f\xa2\xf0\xbd\x09\xa9\xef\xc9\xfd\x22\xd8\x0d\x5d\xfc\x1e\xa9\xee\x08\x80\x94\xb8\x0c\x55\x1c\x1f\xc1\x15\xfc\x8b\x30\xdd\xe2\x47\x59\xcc\xae\xe0\x10\x2a\xc0\xd7\x71\x5f\xff\x7c\xf3\x14\x6d\x40\x85\x66\x39\x25\xcf\x17\x75\x7e\x70\x36\xae\x58\x96\xcb\xdc\x79\xb2\x81\xd0\x20\xe6\x30\x6b\x2c\xb4\x62\x2c\xe3\x3e\xbe\xd8\x7b\x91\x8a\x5b\xfc\x1c\xed\x3d\xb6\x7c\xf0\x14\xe1\x0f\x6e\xa1\xad\x2f\x5d\x28\xf8\xd3\xeb\xbd\xc7\x7e\xbb\x3c\x45\x2f\x5f\x76\xe3\x0d\x2f\x31\xc7\xf5\x35\xa2\xbd\xbf\x44\x88\xe9\x77\x1c\xe0\xd9\xb3\xb0\x7f\x28\x4c\xe5\x3e\x62\x45\x38\xc0\xcd\xcd\xfb\xd0\x7f\xed\x7d\x5a\xff\xad\xb2\x71\xbd\xb2\x19\xdd\x7c\x8c\xf6\x1e\xeb\xcd\x6b\xda\x73\xd1\x5b\x48\xb5\xa2\xbe\x3b\x43\x7c\x36\xe1\x1e\xef\xd0\x1f\xca\x33\x14\x73\xdc\x13\x6e\xc9\xa3\x60\x52\xa4\x9d\xcc\xf5\xcf\x61\x88\xbf\x53\xc5\x06\x4a\x7b\xe4\x1b\x60\xf7\x0b\x52\x21\x0e\x5b\x8e\xd9\xab\x89\xb4\x41\xd3\xb9\x0f\x03\xb8\xb6\x60\x46\x20\x57\xac\x60\x42\xb2\x99\x90\xc2\xf7\x87\x80\x21\x26\x9e\x49\x02\x29\x6f\x05\x39\x70\x9d\xcb\x34\x74\xa8\xf3\xa1\x04\x3a\x06\x57\x73\x4b\x6d\x41\x38\xa4\x24\xc9\x53\x3a\xd8\x76\x08\x8f\xc3\x3a\x9d\xdf\x4f\xfd\x10\x7f\xcd\x85\x4c\xc1\xa0\xe8\xbe\xd3\x1f\x55\x29\x75\x63\x0e\x7d\xa4\x73\x0b\x9e\x3b\xaf\xb3\xc6\xe9\xb9\x90\x9e\x2c\xa5\xd0\xf9\x7a\x8d\xdd\x5a\x32\x88\x0b\x44\x43\xec\x3d\xae\x13\xcc\x53\xb4\xd1\x52\x3f\xed\x68\xaa\xf0\x1e\xe2\xc8\x18\x52\x29\x6a\x06\x6a\x9d\xd0\xb6\xa1\x89\xb5\x4d\xfd\x9e\xfa\xa1\x9b\x99\x2d\x3d\x55\xf5\xa0\x30\x21\xff\x65\x29\x86\x1e\xbd\x2e\x3f\x3d\xdd\x3c\x6d\xdd\x00\x10\x5f\xe8\x00\x2e\xcc\x13\x2a\x55\x7c\xab\x75\xf0\x8d\x54\xfc\xb4\x11\x7b\x0d\x5e\xff\x95\x65\xbe\x65\x69\xcd\x9f\x21\xa6\x97\x27\x97\xc9\x96\x0e\x60\x5e\x67\xe1\x01\x55\x2e\xe1\x35\x58\xa1\x45\x0a\xa6\x96\x10\x8a\x6b\xe5\x84\xf3\xa4\x3c\x66\xb4\x60\x85\xd0\x76\x03\xf5\x8a\x8c\x64\xbc\x07\xd8\x54\x44\xa6\x53\x31\x17\x94\xa2\xa8\x9e\xd1\x43\x21\x2a\xa2\x74\xad\x3c\x01\x9e\x99\xb5\x30\x37\x6a\xe0\xeb\xd7\x15\x03\xef\xd6\xdb\xf0\xaf\xd1\x0d\x1d\x19\xba\xd6\x52\xa6\x0b\x4a\xdb\x58\x03\xeb\x83\x5b\x62\x9e\xf6\xab\xee\x29\x47\x9f\x96\xe7\xc1\xb5\x59\x82\x2f\x72\xab\x06\x3b\xf8\xc6\x49\x22\x83\x1f\x0f\xf0\x0c\xf7\x4c\xf4\x6b\x3e\x57\xe1\x4a\x5e\x95\xcd\xe0\x1b\x87\xf7\x7f\x1e\x9d\x77\x8e\xc3\xf5\x34\x9c\x2a\x57\x8f\xaa\x27\x95\xf9\x95\x20\xdc\xf0\x13\x92\xc4\xbd\xb6\x2d\xc2\x8c\x3c\x7b\x7d\x97\xcf\xc8\x2a\x0a\xf7\xa5\xd0\xfb\xda\x25\x90\x42\xe5\x0f\x41\x0e\xac\x54\xab\x71\xb5\xb5\xfd\x9d\xa7\xfb\x6a\xf9\x9c\x99\xd6\x14\x86\x08\x3f\xae\xec\x18\xb6\x03\xd3\x79\xca\xfa\x01\xc6\xb8\xa3\x65\x82\xfa\x77\x87\x01\x36\x1f\xc3\xd6\x65\xbb\x86\xe8\xb0\x36\x0e\x0f\x6f\x83\x0d\x98\xb6\x03\xba\x32\xbf\x34\x94\xe0\xb4\x05\xf1\x5a\x86\xa1\x49\x68\xd5\x38\x3a\xac\x17\x09\x4c\x4a\x84\x9f\x10\xbc\x83\xd3\xd5\x33\x53\xf9\x40\xea\xc0\xe4\x3d\x5b\xba\xfa\xd6\x0e\x0f\xb1\x41\x37\x1c\x4b\x6d\x2f\x86\x36\x01\x45\xdb\x04\xa3\x07\xe1\xbc\x1b\xfc\x73\x00\xed\x51\x5d\x32\x30\x13\x00\x00")
 
 func assetsDnsDaemonsetYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -150,12 +159,112 @@ func assetsDnsDaemonsetYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/dns/daemonset.yaml", size: 4302, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x66, 0x9, 0x9a, 0x9e, 0x45, 0x7e, 0x37, 0xa8, 0x5d, 0xa2, 0x8e, 0xca, 0xf1, 0xcd, 0xb5, 0xd1, 0xd6, 0x2b, 0xe0, 0xa, 0x7d, 0x73, 0x6, 0xbc, 0xc8, 0x46, 0xe1, 0x50, 0x5, 0x93, 0xad, 0xd1}}
+	info := bindataFileInfo{name: "assets/dns/daemonset.yaml", size: 4912, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x44, 0x1b, 0x12, 0x33, 0xa3, 0xd6, 0xfc, 0xcc, 0x22, 0xaf, 0x59, 0x5a, 0xb1, 0x3d, 0x24, 0x5a, 0x75, 0x5f, 0x4a, 0xd6, 0x73, 0x55, 0x4b, 0x4, 0x65, 0xe4, 0x2d, 0x17, 0xa9, 0x18, 0xae, 0xab}}
 	return a, nil
 }
 
-var _assetsDnsNamespaceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3c\x8d\x3b\x8e\xc3\x30\x0c\x44\x7b\x9d\x62\xe0\xad\xbd\x9f\x56\x87\xd8\x32\x3d\x1d\x4d\x62\xc2\x12\x25\x98\xb2\xce\x1f\x38\x08\xdc\xce\x1b\xbc\xb7\xa9\xa5\x88\x7f\x29\xf4\x26\x77\x06\x69\x7a\xe3\xee\x5a\x2d\x62\xfc\x85\xc2\x2e\x49\xba\xc4\x00\x98\x14\x46\xd4\x46\xf3\x55\x1f\x7d\x4e\xe6\x01\xc8\xb2\x30\xfb\xc9\x81\x2f\x38\x3b\x86\xe4\x83\xe8\x15\x32\xaa\x26\x24\x36\x5a\x52\x7b\xa2\x1a\xb6\x63\x21\x24\x15\xf5\x33\x81\xbe\x4a\xff\x1c\xfc\xc4\x97\x1c\xd2\xd4\xdf\xce\x6b\xfa\xd6\xfa\xb3\x1f\x36\x67\x0e\xe6\x88\xe9\x77\x0a\xaf\x00\x00\x00\xff\xff\x90\x81\x4e\xed\xbd\x00\x00\x00")
+var _assetsDnsDeploymentYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x92\x41\x6f\xdb\x30\x0c\x85\xef\xfe\x15\x04\x82\xdd\xda\xb5\x1d\xb0\x8b\x6f\xc5\xba\xc3\x30\x60\x87\x75\xdd\x9d\xb1\x5e\x6c\x21\x12\x25\x90\x74\x5a\xef\xd7\x0f\x4a\xb2\x24\x4b\x21\xc3\x30\xe8\xf7\x9e\xf9\xd1\xdc\x46\x09\x3d\x3d\xa1\xa6\xb2\x64\x88\x77\x5c\xe3\x6f\xa8\xc5\x22\x3d\x71\xad\x76\xb7\x7b\xe8\x56\x24\x9c\x71\xb3\xbf\x5b\xe5\x01\xc4\x12\x28\xf1\x1a\xc9\x88\x15\x64\x70\x62\x27\x9d\xc5\x63\x46\x67\x15\x43\xdf\x11\xad\x48\x51\x53\x1c\xd8\x28\xda\x95\x88\x36\x5a\x32\xf9\x04\x1a\xd2\x6c\x0e\x25\x8b\x7f\xd0\x11\x99\x2b\x3b\xc6\xa5\x05\x10\xf9\x52\xd1\xd3\xcf\x92\x52\x94\xf1\xa5\x06\xf6\xa6\x21\xd2\xcb\xca\x41\x4a\x94\xf9\xed\x45\x78\xc7\x31\xf1\x3a\xa1\xa7\x87\x73\xfd\x79\xd6\x11\x3d\x7d\xfa\xfc\xa1\x23\x72\xe4\x9a\x4e\xbe\xd5\xbe\x0b\x85\x39\x95\xcd\xfe\xb9\x96\x40\x8d\xa1\x75\xed\xbc\x85\x9c\x9b\x0d\x62\x14\x18\xb9\xc8\x15\x73\xfb\xd0\x3f\xee\x76\xaa\xc6\xa2\xd1\x97\x2f\x89\xcd\x7e\x70\x46\x4f\xb6\x98\x23\xdf\x1e\x79\x6f\x07\x8d\x1e\x07\x4e\x47\x03\x6f\x36\x51\xa2\x1f\xb9\xdb\x55\x4b\x78\x14\x8f\x8f\xef\x5e\xb4\xc9\x5a\x55\x70\x38\x0f\x98\x07\x2d\x66\x24\x25\xc0\x6e\x68\x3d\x3b\x85\x42\x52\x9c\x12\x78\x87\x93\xee\xbf\x8c\x59\x6c\x98\x10\xe6\x84\x40\xaf\x13\xa4\xc1\x2b\xf6\x7f\x34\x17\x3d\x9b\xc8\x27\x96\x43\xf4\x85\xbf\x2a\x36\x50\x45\x78\x9a\x35\xca\xf8\x7c\x88\x8a\x32\x7e\x1b\xa5\x9c\xca\x5f\xdf\x30\xcc\xde\xd6\xe9\xc2\x7a\x4b\xaf\x88\xe3\xe4\x3d\x3d\xdc\xdf\x5f\xd4\x0f\xcc\x47\xde\x5f\xd0\x7c\x69\x6a\x67\x75\xd8\xba\x67\x24\x0c\x5e\xf4\xfd\x5a\x5d\xe9\xbd\xd4\x92\xca\xb8\x7c\xc7\xd2\xd3\x76\x5e\x43\x05\x0e\xfb\x18\xcb\xdd\x54\xcc\x85\x33\xba\xbf\x03\x00\xf0\x49\xc9\x26\x03\x03\x00\x00")
+
+func assetsDnsDeploymentYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsDeploymentYaml,
+		"assets/dns/deployment.yaml",
+	)
+}
+
+func assetsDnsDeploymentYaml() (*asset, error) {
+	bytes, err := assetsDnsDeploymentYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/deployment.yaml", size: 771, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3e, 0x9a, 0xdf, 0xc1, 0xbd, 0xb4, 0x31, 0xcf, 0x75, 0x47, 0x1e, 0x99, 0x4e, 0x85, 0x5f, 0x31, 0xa, 0xe3, 0x6e, 0x3b, 0x47, 0x80, 0xf9, 0xd4, 0xbe, 0xe4, 0x8d, 0x19, 0x7c, 0x19, 0xf1, 0x81}}
+	return a, nil
+}
+
+var _assetsDnsMetricsRoleBindingYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcf\xb1\x4e\xc4\x30\x0c\xc6\xf1\x3d\x4f\xe1\x17\x68\x11\xdb\x29\x1b\x2c\xec\x87\xc4\xee\x4b\x5c\x6a\xda\xd8\x91\xed\x74\xe0\xe9\x51\x05\x12\x13\x48\xb7\x7a\xf0\xf7\xfb\x6f\x2c\x35\xc3\x55\x77\x7a\x66\xa9\x2c\xef\x09\x3b\xbf\x91\x39\xab\x64\xb0\x1b\x96\x19\x47\xac\x6a\xfc\x89\xc1\x2a\xf3\x76\xf1\x99\xf5\xe1\x78\x4c\x8d\x02\x2b\x06\xe6\x04\x20\xd8\x28\x43\x37\x6d\x14\x2b\x0d\x9f\xb6\x8b\xff\x9c\xbd\x63\xa1\x0c\xda\x49\x7c\xe5\x25\xa6\x2a\x9e\x7c\xdc\x3e\xa8\x84\xe7\x34\xc1\x37\xe1\x95\xec\xe0\x42\x4f\xa5\xe8\x90\xb8\xf3\x65\x53\xe1\x50\x3b\xf9\xa6\x3b\x5d\x69\x39\x51\xbf\x6d\x09\x00\x3b\xbf\x98\x8e\xfe\x4f\xd4\x5f\xa3\x5f\x03\x00\x0b\xd0\xc3\x6c\x24\x01\x00\x00")
+
+func assetsDnsMetricsRoleBindingYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsMetricsRoleBindingYaml,
+		"assets/dns/metrics-role-binding.yaml",
+	)
+}
+
+func assetsDnsMetricsRoleBindingYaml() (*asset, error) {
+	bytes, err := assetsDnsMetricsRoleBindingYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/metrics-role-binding.yaml", size: 292, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4b, 0xaa, 0xd2, 0x39, 0x6c, 0x69, 0x8e, 0xc9, 0x54, 0x13, 0xa2, 0xda, 0x56, 0x61, 0x60, 0xe0, 0xeb, 0xfe, 0x92, 0xc1, 0xc0, 0x80, 0xef, 0x76, 0xb1, 0xc1, 0xd8, 0x6, 0xd9, 0x47, 0xb0, 0xe8}}
+	return a, nil
+}
+
+var _assetsDnsMetricsRoleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x44\x8e\xb1\x6a\x2c\x31\x0c\x45\x7b\x7f\x85\xd8\x57\xcf\x3c\xd2\x2d\xfe\x81\xf4\x29\xd2\x6b\x6d\x65\x2d\xd6\x23\x19\x49\x9e\x85\x7c\x7d\xd8\xc9\x40\x2a\xe9\x1e\x2e\x87\xfb\x0f\x3e\xb4\x13\x44\xc3\x00\xec\x5d\x9f\x0e\xa5\x4f\x0f\x32\xd8\x54\x38\xd4\x58\xee\x10\x0a\x95\xbd\xe8\x4e\x06\xd1\x08\xaa\x38\x6c\x14\xc6\xc5\x81\xa4\x0e\x65\x09\x5f\xd3\x83\xa5\xe6\x43\x98\x70\xf0\x27\x99\xb3\x4a\x06\xbb\x61\x59\x71\x46\x53\xe3\x6f\x0c\x56\x59\x1f\x57\x5f\x59\xff\xef\x6f\x69\xa3\xc0\x8a\x81\x39\x01\x08\x6e\x94\x61\x98\x6e\x14\x8d\xa6\x2f\x8f\xab\x9f\xd8\x07\x16\xca\xa0\x83\xc4\x1b\x7f\xc5\x52\xc5\x93\xcd\x4e\x9e\xd3\x02\x38\xf8\xdd\x74\x0e\x7f\x59\x16\xb8\x5c\x12\x80\x91\xeb\xb4\x42\x27\x73\xb2\x9d\x0b\xbd\x7c\xcb\xdf\xe6\x23\x0d\xad\x2f\xbc\x93\xdd\xce\xf2\x9d\xe2\xb8\x9d\xfd\xf7\x79\x62\x94\x96\x7e\x06\x00\xfb\x57\x69\x76\x2d\x01\x00\x00")
+
+func assetsDnsMetricsRoleYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsMetricsRoleYaml,
+		"assets/dns/metrics-role.yaml",
+	)
+}
+
+func assetsDnsMetricsRoleYaml() (*asset, error) {
+	bytes, err := assetsDnsMetricsRoleYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/metrics-role.yaml", size: 301, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1, 0x2f, 0xa6, 0x18, 0x3, 0x3c, 0x4f, 0xd9, 0x87, 0x4a, 0xca, 0xce, 0x5c, 0x3f, 0x42, 0xcb, 0xd1, 0xb1, 0xfc, 0x2b, 0xbf, 0xb4, 0x58, 0x2d, 0xa7, 0xc8, 0x8a, 0x23, 0x6c, 0x1a, 0x4, 0xbd}}
+	return a, nil
+}
+
+var _assetsDnsMetricsServiceMonitorYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x8c\x41\x4a\x04\x41\x0c\x45\xf7\x75\x8a\x0f\xbd\xb5\x47\xc5\x5d\xdd\x41\x37\x03\xee\x63\x75\x74\x82\x55\x49\x91\xc4\x3e\xbf\x4c\x8f\x20\xe8\x26\x90\xe4\xbd\xf7\x29\xba\x55\x9c\xd9\x77\x69\xfc\x6c\x2a\x69\x5e\x68\xca\x2b\x7b\x88\x69\xc5\xb8\xdd\x44\x3f\x4e\xcd\x9c\x2d\x4e\xcd\xc6\xfd\xfe\x58\x16\x28\x0d\xbe\x3b\x66\x4c\x6a\x0c\xd2\x0d\x9d\xde\xb8\x07\xc8\x19\xc1\x09\x4a\xf8\x97\xa6\x0c\x2e\x31\xb9\xd5\x02\x2c\x08\xee\xdc\xd2\x1c\x12\x7f\x21\xfc\xe6\xce\x3f\xd4\xd5\x01\x06\x65\xbb\xbc\x5c\x7f\xb7\x7d\x85\x4d\xd6\xb8\xc8\x7b\xae\x9b\x06\x96\xff\x25\xd6\x6d\x9a\x68\x1e\xc6\x8a\x69\x9e\x15\x83\xd3\xa5\xc5\xd1\x10\x4d\xf6\x9d\x7a\xc5\xd3\x43\x94\xef\x01\x00\xdd\x68\x4c\xdd\x0a\x01\x00\x00")
+
+func assetsDnsMetricsServiceMonitorYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsMetricsServiceMonitorYaml,
+		"assets/dns/metrics-service-monitor.yaml",
+	)
+}
+
+func assetsDnsMetricsServiceMonitorYaml() (*asset, error) {
+	bytes, err := assetsDnsMetricsServiceMonitorYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/metrics-service-monitor.yaml", size: 266, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x98, 0xaa, 0x3b, 0x6d, 0x16, 0x87, 0xc1, 0xed, 0xd, 0x58, 0x99, 0xb1, 0x15, 0xde, 0xb8, 0xc8, 0x84, 0x9f, 0x61, 0x82, 0x3f, 0x7a, 0x27, 0x44, 0x71, 0x4b, 0x16, 0x22, 0x93, 0x8b, 0x2a, 0x75}}
+	return a, nil
+}
+
+var _assetsDnsMetricsServiceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x8e\x31\x4f\x43\x31\x0c\x84\xf7\xfc\x8a\x93\xba\x96\xa1\x42\x0c\x64\x65\xea\x82\x9e\x04\x62\x0f\xc9\x01\x11\x79\x76\x64\xbb\xfd\xfd\xa8\x8f\x76\xe9\x62\xc9\xf6\xa7\xbb\xef\xb7\x4b\xcb\x78\xa3\x9d\x7b\x65\x2a\xb3\x7f\xd0\xbc\xab\x64\x9c\x0f\x69\x07\x29\x2b\xf7\xdb\xf4\x59\x2a\x51\xa4\x61\x94\x4f\x0e\x47\x31\xc2\x19\x28\x01\x3b\x49\xf4\x95\xc9\x27\x6b\x4e\xc0\x0e\x2b\xc3\x7a\xbd\x42\xd5\xca\x64\xc3\x97\xe9\x8a\xf8\x21\x28\x6d\x6a\x97\xf0\x3d\x5c\x21\x8a\x3a\x4e\x1e\x34\x1c\x17\x74\x87\x90\x8d\x2d\xe1\x76\x3e\x2e\x19\xaf\x2a\xdc\x82\x9d\x83\x35\xd4\x2e\xe0\x5d\x3b\x30\xd5\xc2\x2f\x02\x0f\x9b\x72\xbe\x69\x24\xe0\xff\x99\xf1\x7c\x78\x7a\xdc\xd6\x28\xf6\xcd\x58\xd4\xe2\x0e\x33\x0d\xad\x3a\x32\xde\x5f\x96\xf4\x37\x00\xeb\x20\xb5\xb3\x1f\x01\x00\x00")
+
+func assetsDnsMetricsServiceYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsMetricsServiceYaml,
+		"assets/dns/metrics-service.yaml",
+	)
+}
+
+func assetsDnsMetricsServiceYaml() (*asset, error) {
+	bytes, err := assetsDnsMetricsServiceYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/metrics-service.yaml", size: 287, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1d, 0x4c, 0xb8, 0xcc, 0xca, 0x73, 0x43, 0xba, 0xe4, 0x78, 0x38, 0x68, 0x47, 0x87, 0x4f, 0xd0, 0xab, 0xd5, 0xcc, 0x8f, 0xc4, 0x13, 0xcc, 0xd1, 0x71, 0xa6, 0xc8, 0xeb, 0xcb, 0x86, 0x50, 0xd7}}
+	return a, nil
+}
+
+var _assetsDnsNamespaceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8d\xb1\x4e\x2c\x31\x0c\x45\xfb\x7c\xc5\xd5\xbc\x7a\x1e\xd0\xe6\x23\x28\xe9\xbd\x13\xc3\x5a\x9b\xd8\x51\xec\x0c\xbf\x8f\x22\x8d\xd8\x86\xd6\xd7\xe7\x9c\x87\x68\xc9\x78\xa7\xc6\xde\xe9\xe0\x44\x5d\x3e\x78\xb8\x98\x66\x9c\x6f\xa9\x71\x50\xa1\xa0\x9c\x00\xa5\xc6\x19\xd6\x59\xfd\x2e\x9f\xb1\x17\xf5\x04\x54\xba\x71\xf5\xb5\x03\xff\xe0\x1c\x38\xa9\x4e\x46\x18\xe8\x34\x29\x28\xdc\x59\x8b\xe8\x17\x4c\xf1\x98\x37\x06\x95\x26\xbe\x12\x88\x3b\xc5\xf5\xe0\x6b\xfe\x95\x83\xba\x2c\x3b\x9e\xa7\xff\x62\x2f\x63\xea\x5e\xf9\xe4\x9a\xb1\xbd\x6e\x57\x93\x6a\xb5\x6f\x1c\x75\x7a\xf0\x40\x33\x95\xb0\xb1\x7a\x61\xf0\x63\x50\x67\x14\x75\x34\x8e\x21\xc7\x1f\xd2\x8b\xdc\x9f\x64\xc6\x16\x63\xf2\x96\x7e\x06\x00\x53\x41\x02\xa8\x1e\x01\x00\x00")
 
 func assetsDnsNamespaceYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -170,12 +279,92 @@ func assetsDnsNamespaceYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/dns/namespace.yaml", size: 189, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc7, 0xcc, 0xc6, 0xb2, 0x81, 0xa0, 0x7d, 0xc5, 0x5b, 0x8f, 0xf3, 0xd1, 0xf1, 0xee, 0x65, 0x3d, 0x0, 0x7c, 0x93, 0x89, 0x1c, 0x38, 0xd0, 0x9, 0x86, 0x1a, 0xd6, 0xef, 0x5, 0xee, 0x70, 0xe8}}
+	info := bindataFileInfo{name: "assets/dns/namespace.yaml", size: 286, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd5, 0x1b, 0x77, 0x24, 0xe1, 0x7b, 0x24, 0xa6, 0xf3, 0xf0, 0xf8, 0xa6, 0x72, 0x45, 0x3d, 0x7c, 0x5d, 0x6e, 0x7b, 0x5c, 0xec, 0xc6, 0x21, 0x5, 0x5f, 0xbf, 0xec, 0xe, 0x4b, 0xf3, 0xd, 0x49}}
+	return a, nil
+}
+
+var _assetsDnsNodeLocalCacheConfigmapYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x91\x4f\x8f\xd3\x30\x10\xc5\xef\xf9\x14\x4f\xda\x2b\x64\x0b\xa5\xa8\xcd\x0d\x75\x2f\x2b\x01\x5a\x89\x3f\x57\x34\xd8\x93\x8d\x85\x63\x47\x33\x4e\x7b\x28\xf9\xee\xc8\x49\xe5\x16\xd1\x13\x5a\x25\x52\xec\xe7\xe7\x37\xbf\x99\xfc\x72\xc1\x36\xd8\xc7\xd0\xba\xe7\x4f\x34\x54\x34\xb8\xef\x2c\xea\x62\x68\x70\x78\x53\xdd\x21\x50\xcf\xa0\x60\xe7\x85\x0e\x64\x18\x24\x0c\xe5\x04\x4a\x90\x31\x24\xd7\x73\x65\x29\x51\x53\x01\x77\xd8\x47\xe1\xd6\x79\x86\x53\x10\x12\xf7\x83\xa7\xc4\x48\x1d\xa5\x2c\x09\x07\xcb\xc2\xf6\xea\x72\x0d\x7c\xed\x18\x86\x4c\xc7\xf8\xe9\x82\x55\xa4\x8e\xe7\x30\xe3\x47\x4d\x2c\x78\x7c\x42\x6c\xb3\x0a\x1b\x14\xca\x72\x70\x99\x43\x71\x64\xef\xf3\x37\x1f\xf9\x68\xc8\x83\xac\x15\x56\x7d\x05\x8d\x70\x69\x4e\x69\xa3\x1c\x49\x72\x6c\x9c\xf1\x1e\x3e\x7f\x41\xea\x24\x8e\xcf\x1d\xa8\xa4\xc5\x16\x2e\x29\xe2\x31\xd4\x15\x4a\x1b\x0d\x7e\x57\x00\x70\x3a\xd5\xfb\x05\xe6\x21\xf6\xe4\xc2\x34\x35\x9b\x35\x5c\x78\x9d\xeb\xd5\x24\x03\xcd\xfb\xe1\x7d\x59\x9f\xe6\x7b\xf9\x65\x91\x28\x5a\xb6\xb9\xc5\x9c\xf7\x31\xf3\x7e\x58\x70\xa7\xe9\xaa\xc2\xe3\xd3\x34\x15\x77\xc7\xe4\x53\xf7\xaf\xbf\xd9\xae\xb6\xab\xe2\x1a\x24\xf6\x9c\x3a\x1e\xf5\x86\x73\xf7\x76\xb3\x2e\xce\xf3\x30\x50\x67\xe3\xb7\x41\x93\x30\xf5\xb9\xe2\x15\xf0\xd9\x67\xf8\x47\x32\x43\x51\x2f\x4c\xcb\xaf\xfa\xdb\xaf\xa3\x31\xac\x8a\xdd\x6e\xfb\x0e\xeb\x0b\x59\x7e\x2c\x07\x47\x7e\x39\xda\xdc\xc8\x13\xf6\x91\x6c\x75\x11\xeb\x17\x1c\xe0\xff\x8c\xe6\x9e\x93\xb9\x17\xd6\xe8\x0f\xb5\x89\xa1\x2d\x96\xa5\xf3\xf5\xea\x36\xfa\x9f\x01\x00\xd5\x71\x8f\x1b\x4d\x03\x00\x00")
+
+func assetsDnsNodeLocalCacheConfigmapYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsNodeLocalCacheConfigmapYaml,
+		"assets/dns/node-local-cache-configmap.yaml",
+	)
+}
+
+func assetsDnsNodeLocalCacheConfigmapYaml() (*asset, error) {
+	bytes, err := assetsDnsNodeLocalCacheConfigmapYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/node-local-cache-configmap.yaml", size: 845, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe5, 0x16, 0xf8, 0xc5, 0xd7, 0xfc, 0x37, 0xc8, 0x48, 0xea, 0x6b, 0x82, 0x34, 0x3b, 0x7d, 0xbe, 0xd5, 0x4b, 0xae, 0x42, 0x26, 0xf, 0x76, 0xef, 0xba, 0xfb, 0xd1, 0x98, 0x1, 0x28, 0x71, 0x5b}}
+	return a, nil
+}
+
+var _assetsDnsNodeLocalCacheDaemonsetYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xdc\x57\x5d\x6f\x1b\xbb\x11\x7d\xf7\xaf\x38\x91\xdd\xa0\x05\xbc\x91\xed\xc0\x41\xba\x41\x03\x18\xb2\x71\x6b\x34\xb1\x05\x4b\xee\x4b\x5b\x5c\xd0\xe4\x48\xcb\x9a\x4b\xf2\x92\x5c\x7d\xc0\xf6\x7f\x2f\xb8\x5f\xda\x95\x94\xa4\x29\xdc\x97\x0b\x09\x82\x76\x66\x78\x86\x9c\x19\xce\x9c\x7d\x94\x5a\xa4\xb8\x64\x94\x1b\x3d\xa1\x70\xc0\xac\xfc\x3b\x39\x2f\x8d\x4e\xc1\xac\xf5\xc3\xc5\xe9\xc1\x21\x34\xcb\xe9\xb8\xfc\xf5\x96\x71\x02\xd3\x02\x8a\x3d\x90\xf2\x60\x8e\xe0\x29\x80\x05\xb8\x42\x07\x99\xd3\x81\xb7\xc4\xd3\x03\xa0\xb0\x82\x05\x9a\x04\xc7\x02\xcd\xd7\x51\x02\x84\xb5\xa5\x14\x77\x46\x29\xa9\xe7\xf7\xa5\x41\x29\x77\x5d\x49\x65\x0a\xe4\x6c\x75\xaf\xd9\x82\x49\xc5\x1e\x14\xa5\x38\x3d\xf9\xc3\x01\x10\x28\xb7\xaa\xb5\x6a\x9c\xc5\x8f\x27\xb7\x90\x9c\x2e\x38\x37\x85\x0e\x37\x2c\xa7\x14\x42\xfb\x5a\x6b\x9d\x34\x4e\x86\xf5\x48\x31\xef\x2b\xa5\x5f\xfb\x40\x79\xa2\x8d\xa0\x84\x3b\x19\x24\x67\xaa\xb6\xce\x8c\x0f\x37\x14\x96\xc6\x3d\xa6\x08\xae\xa0\x5a\x2e\xb5\x0c\x23\xa3\x03\x93\x9a\x9c\x6f\x3c\x1f\xc6\x18\x14\x36\x91\x3a\x90\x9b\x95\x21\x12\xc2\x23\x64\x04\x65\x38\x53\x60\x42\x38\xf2\xbe\x8c\x5c\x94\x72\x55\xf8\x40\x0e\xd7\x63\x98\x59\x69\xb7\xd9\xe8\x61\x73\x10\x04\x03\x06\x51\xe4\xf9\x1a\x1b\x64\x6f\x10\x32\x16\xca\x45\x9c\xf1\xf2\x57\xe3\x41\x56\xc8\xf9\x71\xf4\xd1\x22\xd1\x8a\x72\x1b\x3c\x2e\x6f\x26\x08\x8e\xcd\x66\x92\x47\xd4\x90\x51\x8e\x99\x33\x39\xb8\xd1\x9a\x78\x90\x46\x47\x3d\x7f\x94\x7a\xde\xf5\xb0\xb3\x25\xeb\xcc\x6a\x0d\x61\xc8\x43\x9b\x00\x47\x42\x3a\xe2\x01\x32\x80\x2d\xd9\xba\x02\x6d\xb7\xf6\xae\x5e\x9f\x94\xc5\x93\x6e\x47\xa9\xd6\xc6\x23\xcb\x9c\xcd\x09\xd2\x6f\x17\x53\x63\x51\xea\xc7\x85\x52\x63\xa3\x24\x5f\xa7\xb8\x9e\xdd\x98\x30\x76\xe4\x49\x87\xd6\xca\x13\x2f\xca\x1c\x1b\x1d\x68\x15\x9a\xf4\xd4\xe9\x5f\x48\x45\x73\x12\xbd\x7c\x46\xdf\xa4\x17\xf8\x72\x3b\xba\xf8\xf2\xeb\xc5\xe5\xe5\xdd\xd5\x64\x12\x43\x88\xd1\x97\xfb\xc9\xf4\xea\xee\xd7\xeb\xf1\xbe\x22\x6f\x56\x73\x93\xe7\x4c\x8b\x8d\xa7\x04\xc3\x07\xa9\x87\x3e\xeb\x48\x12\xde\x79\x78\x6e\xff\x03\x87\x6f\xb6\x8d\x63\x15\x07\x24\x54\x1c\x74\x44\x33\xe3\x10\x8c\x51\x90\x1a\xd2\x42\xda\x10\x2f\x84\x87\x57\x44\xf6\x13\x84\xe9\xd8\x02\x72\x86\x37\xcd\xbe\x90\x2c\x30\x38\x7a\x8a\x8b\x5f\x06\xf8\x3c\x14\xb4\x18\xea\x42\x29\x9c\x7d\x7e\x7b\xfa\x29\x66\x58\xf7\xd6\x02\xc4\x33\x83\x41\xcc\x60\x79\x2d\xaa\xf2\x2d\x93\x09\x4d\x24\x3c\x6a\xb4\x63\x2c\x33\xc9\xb3\x08\x51\x27\xaf\x2d\x0a\xeb\xcc\x42\x0a\x1a\xe0\xf3\xdb\xb3\x6d\xf4\x95\x0c\x38\xed\x09\x67\xb2\xf3\x28\x8c\xa6\xee\xc9\xa5\x85\x92\xfa\x11\x3e\x33\xcb\x72\x43\xe5\x7e\x84\xf6\xdb\x67\xc1\xf3\x73\x6b\xcc\x84\xe8\xdb\xc6\xae\x53\xdd\xa3\x7d\xd0\x14\xfa\xd6\x85\xed\x58\xc5\xd0\x37\x97\x57\xea\x18\xcb\x5e\xa5\xbc\x0c\xa2\x68\x53\x2a\x2f\x83\xdd\x74\xd8\xf2\xf6\xc3\x91\x55\xf1\xfe\x0e\x8e\x9e\x6a\xc0\x97\xe1\xfb\xb3\x01\x04\x2d\x7a\xfe\x7b\x8b\xa3\x7b\xeb\x4c\x30\x31\xf5\x85\xb0\x08\x7c\x37\xe1\x95\x99\x2b\x14\x45\xab\xc1\xf8\xee\xea\xee\xf6\x7e\x7a\x7d\xf3\x0b\x12\x81\x8d\x37\x24\x16\x47\x4f\x25\xda\x0b\x92\x44\x58\xe3\x02\xce\xdf\x0f\xf0\xcf\x2d\xb4\xee\x67\x70\x7b\x3f\x1d\xdf\x4f\x5f\x13\xc9\x7f\x1b\xc9\x37\x48\x7b\xce\x18\x6f\xaa\xcf\x48\x29\x9e\x11\x7f\x84\x90\x3e\x5e\x82\xbf\x4c\x46\x67\x27\x1f\x3f\xec\xd8\xb6\x77\x24\x59\x22\x09\x70\x6c\x89\x64\x84\xa3\xa7\x18\xa5\x17\x24\xff\xc6\xcd\xed\xf4\xee\x62\xf4\x37\x9c\x75\x0a\xe9\xf9\x79\xef\xba\x8b\x3d\xeb\xb6\x1c\x96\x75\xfb\x1d\x41\xef\xd1\x91\x37\x85\xe3\xd4\x8e\x8f\xf8\x75\xf4\x5b\x41\x3e\xf4\x64\x00\xb7\x45\x9c\x7b\x79\x2d\xe4\x3b\x93\xa7\xe9\xac\x9b\xcb\x9a\x74\x6b\xe8\xb5\x1a\x6b\xd3\xe6\xf0\x0f\x0c\xb8\x71\x24\xb4\x1f\xe0\x5f\xad\x9a\xb9\xb9\x2f\x75\x09\x37\x7a\x36\x38\xc6\x60\x48\x81\x0f\x6b\xcb\xe1\xc8\x38\x9a\x49\x45\xdd\x25\x0b\xa3\x8a\x9c\xbe\xc6\x49\xdd\x39\x73\x73\x9c\x08\x23\xe7\x49\x65\xd4\x6a\x81\x3c\xda\x8f\x59\xc8\x52\x74\x3d\x74\x2c\x1c\x31\x71\xab\xd5\x7a\xab\xcb\xc7\xc2\xea\xf9\x69\x43\x39\x36\x2e\xa4\x38\x7f\xdf\xea\x50\xef\xa1\x8f\x5b\xd6\x28\x37\x2a\xc5\xfd\xe5\xf8\x67\x71\x92\xc0\xed\x5e\xac\xe9\xe8\x3b\x58\x7f\x3e\xdb\x83\x96\x53\x70\x92\xfb\x1f\xa2\x29\xb9\x20\x4d\xde\x8f\x9d\x79\x68\x19\x55\xfc\x66\x21\xd8\x5f\xa8\x37\x1b\x63\x9d\x44\xc2\xb3\x5b\x26\x35\x59\xe8\xb3\x98\xde\x4a\x5b\x25\x23\x23\xa6\x42\x77\x8c\x55\x21\x4f\xf1\xf1\xe4\xe3\x49\x4f\xec\x79\x46\x31\xc5\x7f\x9d\x4e\x37\xbb\xad\x88\x95\x64\xea\x92\x14\x5b\x4f\x88\x1b\x2d\x7c\x8a\x0f\xdd\xa5\x96\x9c\x34\xa2\xd5\x9d\x76\x75\x71\xaf\xa6\x08\xad\xf2\xbc\xa3\xf3\x05\xe7\xe4\xfd\x34\x73\xe4\x33\xa3\x44\xda\x1b\x40\x33\x26\x55\xe1\xa8\xa3\x3d\xff\x9f\x2f\xea\xd9\x79\x73\x51\xeb\x6a\xa5\xdc\xb8\x75\x8a\xf3\xaf\xcd\x8c\x3b\x44\x20\xe6\x84\x59\xea\x0e\x57\x74\x94\x9b\x05\x55\x74\x71\x9b\xed\x35\x84\x31\xf6\x2d\x0f\x33\xfb\x26\xe1\x5c\x66\xa4\x4b\x04\x6b\x04\x7c\x30\xd6\x1f\x97\x63\x81\x56\x2c\xb7\x8a\xf0\x40\x9c\x15\x9e\x36\xdc\xac\x45\x5a\x32\xdf\x74\x53\x71\xdc\x92\xbf\xdf\x0a\x72\x32\x6e\xca\x6c\x13\x56\x47\xac\x9e\xfb\xfb\x38\x2b\x9b\x33\xa9\xb7\x79\xdf\xee\xa1\x5f\xbd\x43\xfd\x1e\xa8\x5f\x13\xa6\x3f\xfe\x09\x4f\x1d\xf1\x6b\xb0\x90\xff\x9a\x4a\xfc\x3f\xc9\xc4\x6b\xd2\x89\xd7\x20\x14\x3f\x47\x29\x10\x19\xaf\xa2\xbd\x14\xe1\xf2\x07\xd4\x22\x06\x1a\xe9\xa7\x6d\x5a\xb0\x97\x2b\xec\x15\xed\x08\x1a\xfa\x2a\x48\xf5\xe9\x6b\xc7\x6b\x6f\x41\x49\xbd\xbb\x3d\xf3\xa5\xf3\x3f\x38\x66\xdb\xf2\xc3\xf4\xea\xee\x2b\xae\x6f\xa6\x5d\x26\x5e\x9d\x3d\xce\xd4\x9d\x50\x96\xaf\x21\x78\xff\xe1\xe4\x04\x6f\x7b\x8a\x25\x93\x01\x47\x6f\x0e\xbe\x71\x8c\x9f\x6d\xaf\x3f\xec\xae\x42\xfb\xa6\x47\x5c\xd2\x8c\x15\xaa\x69\x0f\x31\x40\x13\x52\xc4\x83\x71\x1b\xe0\x07\x0a\xec\xdd\x63\xf1\x40\x4e\x53\x20\xff\x4e\x9a\xa1\xf1\x69\x0c\x6b\xb1\xaa\x8d\x2a\x02\xe2\xd3\xad\x7e\xb6\x9f\x9e\x54\xd2\xaf\xcc\x6e\x5c\xc4\x0a\x8b\x2d\xf0\x3b\xed\x0d\x90\x81\xf2\xde\x79\x13\x3c\xd2\x3a\x45\x43\x9b\xf6\xcc\xdb\x2d\x55\x30\x8a\x1c\x8b\x6f\xf1\x2d\xce\x61\x23\x24\x30\xa5\x10\x39\x4a\xf0\xdd\x57\xfb\xfa\xa5\x4e\x7a\xd8\xaa\x95\xc2\xe8\xd2\x34\x06\xab\xe9\xeb\x09\x8c\x8d\xc8\xc6\xa5\xb8\x5a\x49\x1f\xfc\xc1\x7f\x06\x00\x39\x7e\xfe\x5b\x33\x12\x00\x00")
+
+func assetsDnsNodeLocalCacheDaemonsetYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsNodeLocalCacheDaemonsetYaml,
+		"assets/dns/node-local-cache-daemonset.yaml",
+	)
+}
+
+func assetsDnsNodeLocalCacheDaemonsetYaml() (*asset, error) {
+	bytes, err := assetsDnsNodeLocalCacheDaemonsetYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/node-local-cache-daemonset.yaml", size: 4659, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc4, 0xb0, 0xaf, 0x99, 0xf7, 0xe7, 0xb9, 0xdd, 0xe8, 0x91, 0xe2, 0xdd, 0x37, 0x88, 0x34, 0x83, 0xcb, 0xab, 0x46, 0xc, 0x75, 0x14, 0x26, 0x9, 0x43, 0xf8, 0xe6, 0x4, 0x56, 0xe4, 0x61, 0xe2}}
+	return a, nil
+}
+
+var _assetsDnsPoddisruptionbudgetYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x64\x8d\xbd\x6a\x03\x31\x10\x84\x7b\x3d\xc5\xc0\xb5\x81\x70\xad\xba\x84\x3c\x40\x9a\xa4\xdf\x93\xd6\xf6\x62\x69\x25\xb4\x7b\x87\xfd\xf6\xe6\x5c\xb8\x38\x37\x03\xc3\xfc\x7c\x57\xd1\x1c\xf1\xdb\xf2\x8f\xd8\x58\xbb\x4b\xd3\xef\x35\x9f\xd9\x03\x75\xf9\xe7\x61\xd2\x34\xa2\xb7\x22\xe9\xfe\xb9\xcd\x0b\x3b\xcd\x61\x82\x52\xe5\x8f\xa7\x5a\xa7\xc4\x20\xcd\x28\xb4\x70\x31\xd0\x60\x18\x3b\xc8\x31\x56\x75\xa9\x1c\xac\x73\x8a\x01\x98\x60\x5c\x38\x79\x1b\x10\x3b\x96\xf6\xb8\xd2\xed\x4f\x69\x23\x29\xb4\x14\x46\x1b\xa8\xa2\x5f\x2f\xff\x36\xc2\x69\xb4\x0a\xbf\x30\xb2\x1a\x76\x4c\xc0\xe1\x24\x62\x0e\x8f\x01\x00\xe3\x7a\x16\xe0\xe5\x00\x00\x00")
+
+func assetsDnsPoddisruptionbudgetYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsPoddisruptionbudgetYaml,
+		"assets/dns/poddisruptionbudget.yaml",
+	)
+}
+
+func assetsDnsPoddisruptionbudgetYaml() (*asset, error) {
+	bytes, err := assetsDnsPoddisruptionbudgetYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/poddisruptionbudget.yaml", size: 229, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x72, 0xe2, 0xbf, 0x85, 0xa, 0x6, 0x1d, 0x27, 0x70, 0x0, 0x67, 0x73, 0x27, 0x47, 0x88, 0xe3, 0xc3, 0xe9, 0xa7, 0x70, 0x10, 0x40, 0x64, 0xe5, 0xae, 0x26, 0x5f, 0xb0, 0xb5, 0x74, 0xdd, 0xdd}}
 	return a, nil
 }
 
-var _assetsDnsServiceAccountYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2c\xc9\xb1\x09\xc4\x30\x0c\x05\xd0\xde\x53\x68\x81\x2b\xae\x55\x77\x33\x1c\xa4\x17\xf2\x0f\x11\xc1\xb2\xb1\x14\xcf\x1f\x02\xe9\x1e\xbc\xd3\xbc\x32\xfd\x31\x97\x29\x7e\xaa\xfd\xf2\x2c\x32\x6c\xc3\x0c\xeb\xce\xb4\xbe\xa5\x21\xa5\x4a\x0a\x17\x22\x97\x06\xa6\xea\xf1\x3a\x86\x28\x98\xfa\x80\xc7\x61\x7b\x7e\x9e\xba\x03\x00\x00\xff\xff\x8e\x2c\xf1\x2e\x55\x00\x00\x00")
+var _assetsDnsPrometheusRuleYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\xb1\x4e\x04\x31\x0c\x44\xfb\x7c\xc5\x48\x57\xef\x22\x3a\x94\xaf\x40\x14\xf4\x66\x77\xb8\x8b\x2e\x89\x23\xdb\xe1\xfb\xd1\xa6\xa0\xa2\x7b\xb6\x46\xf3\xe6\x59\xfa\x99\xf1\x6e\xda\x18\x0f\x4e\xff\x98\x95\x49\x46\xf9\xa4\x79\xd1\x9e\xd1\xb4\x97\x50\x2b\xfd\xbe\x1f\x6a\x54\xdf\x0f\x6d\x2f\x3f\xaf\xe9\x86\x2e\x8d\x90\x7e\x2e\xf0\x21\x07\x21\x46\x38\x03\x12\xb0\xd9\xa3\x34\xa6\xc6\x90\x53\x42\x72\x02\xaa\x7c\xb1\xfa\x45\xc0\xf8\x73\x66\x3c\xdf\x7c\xfd\x4c\x2b\x33\xa4\xd2\x62\xb3\x59\xe9\xc9\x07\x8f\x2b\x7f\xc3\xba\xff\x13\x00\x77\xd3\x39\x56\xed\xb6\xb6\x64\xe8\x60\xf7\x47\xf9\x8e\xed\xec\xbe\xdb\xac\xf4\xf4\x3b\x00\x24\xfa\x8b\x73\xeb\x00\x00\x00")
+
+func assetsDnsPrometheusRuleYamlBytes() ([]byte, error) {
+	return bindataRead(
+		_assetsDnsPrometheusRuleYaml,
+		"assets/dns/prometheus-rule.yaml",
+	)
+}
+
+func assetsDnsPrometheusRuleYaml() (*asset, error) {
+	bytes, err := assetsDnsPrometheusRuleYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "assets/dns/prometheus-rule.yaml", size: 235, mode: os.FileMode(420), modTime: time.Unix(1, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1a, 0x2f, 0xd9, 0xa4, 0x1e, 0x8e, 0xcc, 0xbe, 0x13, 0xc8, 0xb, 0xaa, 0xe2, 0x66, 0x8f, 0xf3, 0x56, 0xe1, 0x74, 0x26, 0x85, 0xd7, 0x89, 0xa1, 0x2c, 0x2b, 0xaf, 0x40, 0x39, 0x52, 0x51, 0x58}}
+	return a, nil
+}
+
+var _assetsDnsServiceAccountYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x55\x00\xaa\xff\x6b\x69\x6e\x64\x3a\x20\x53\x65\x72\x76\x69\x63\x65\x41\x63\x63\x6f\x75\x6e\x74\x0a\x61\x70\x69\x56\x65\x72\x73\x69\x6f\x6e\x3a\x20\x76\x31\x0a\x6d\x65\x74\x61\x64\x61\x74\x61\x3a\x0a\x20\x20\x6e\x61\x6d\x65\x3a\x20\x64\x6e\x73\x0a\x20\x20\x6e\x61\x6d\x65\x73\x70\x61\x63\x65\x3a\x20\x6f\x70\x65\x6e\x73\x68\x69\x66\x74\x2d\x64\x6e\x73\x0a\x03\x00\x8e\x2c\xf1\x2e\x55\x00\x00\x00")
 
 func assetsDnsServiceAccountYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -195,7 +384,7 @@ func assetsDnsServiceAccountYaml() (*asset, error) {
 	return a, nil
 }
 
-var _assetsDnsServiceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\x3d\x4b\x04\x41\x0c\xc6\xf1\x7e\x3e\xc5\x03\xd7\x7a\x82\x88\xcd\xb4\xda\xd8\x2d\xf8\xd2\xe7\x66\xc3\x11\xcc\xbc\x90\x64\x4f\xfc\xf6\xe2\x08\x2b\xda\xd8\x0c\x4c\xf2\xe7\x47\xde\xa4\xad\x19\x4f\x6c\x17\x29\x9c\x68\xc8\x2b\x9b\x4b\x6f\x19\x97\x9b\x74\x40\xa3\xca\x57\xf3\xf5\x41\x85\x41\x6d\x85\xd2\x89\xd5\x41\xc6\x70\x0e\x50\xc0\xb6\x16\x52\x39\xf9\xe0\x92\x13\x70\x40\xd1\xcd\x83\xed\x71\xc1\xbb\xa8\xe2\xc4\xa0\x2d\x7a\xa5\x90\x42\xaa\x1f\xa8\xd4\xe8\xcc\xeb\xf5\x8c\x9d\x95\x4b\x74\x83\xf8\x5f\x11\x18\xdd\xc2\xbf\xd0\xe3\x3c\x23\x63\x6d\x9e\x80\xef\x45\xc6\xdd\xed\xfc\x04\xd9\x99\x63\x99\xa3\x3d\xb0\x1e\xbd\x74\xcd\x78\x79\x58\x7e\x03\xc7\x28\xe3\x5f\xe4\x27\xda\xa1\xe7\xfb\x25\x7d\x06\x00\x00\xff\xff\x23\x09\xe5\xe7\x32\x01\x00\x00")
+var _assetsDnsServiceYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\x3d\x4b\x04\x41\x0c\xc6\xf1\x7e\x3e\xc5\x03\xd7\x7a\x82\x88\xcd\xb4\xda\xd8\x2d\xf8\xd2\xe7\x66\x1f\x8e\xc1\xec\xcc\x90\x64\x57\xfc\xf6\xe2\x0a\xbe\x35\x36\x81\x90\x3f\x3f\xf2\x52\xdb\x9c\xf1\x40\xdb\x6a\x61\x92\x51\x9f\x69\x5e\x7b\xcb\xd8\xae\xd2\x01\x4d\x16\x5e\xec\xd3\x87\x14\x42\xda\x0c\x95\x13\xd5\x21\x46\x38\x03\x12\xb0\xb5\x45\x5d\x98\x7c\xb0\xe4\x04\x1c\x50\x74\xf5\xa0\xdd\x4f\x78\xad\xaa\x38\x11\xb2\x46\x5f\x24\x6a\x11\xd5\x37\x2c\xd2\xe4\xcc\xf9\x72\x8f\x9d\xca\x12\xdd\x50\xfd\xaf\x08\x8c\x6e\xe1\x1f\xe8\x71\x7f\x23\x63\x6e\x9e\x80\xcf\x43\xc6\xcd\xf5\xbe\x84\xd8\x99\x31\x75\x8b\x1f\x81\xf5\xe8\xa5\x6b\xc6\xd3\xdd\xf4\x1b\x38\x46\x19\xff\x22\xdf\xd1\x17\xf4\x78\x3b\xa5\xf7\x01\x00\x23\x09\xe5\xe7\x32\x01\x00\x00")
 
 func assetsDnsServiceYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -314,8 +503,26 @@ var _bindata = map[string]func() (*asset, error){
 
 	"assets/dns/daemonset.yaml": assetsDnsDaemonsetYaml,
 
+	"assets/dns/deployment.yaml": assetsDnsDeploymentYaml,
+
+	"assets/dns/metrics-role-binding.yaml": assetsDnsMetricsRoleBindingYaml,
+
+	"assets/dns/metrics-role.yaml": assetsDnsMetricsRoleYaml,
+
+	"assets/dns/metrics-service-monitor.yaml": assetsDnsMetricsServiceMonitorYaml,
+
+	"assets/dns/metrics-service.yaml": assetsDnsMetricsServiceYaml,
+
 	"assets/dns/namespace.yaml": assetsDnsNamespaceYaml,
 
+	"assets/dns/node-local-cache-configmap.yaml": assetsDnsNodeLocalCacheConfigmapYaml,
+
+	"assets/dns/node-local-cache-daemonset.yaml": assetsDnsNodeLocalCacheDaemonsetYaml,
+
+	"assets/dns/poddisruptionbudget.yaml": assetsDnsPoddisruptionbudgetYaml,
+
+	"assets/dns/prometheus-rule.yaml": assetsDnsPrometheusRuleYaml,
+
 	"assets/dns/service-account.yaml": assetsDnsServiceAccountYaml,
 
 	"assets/dns/service.yaml": assetsDnsServiceYaml,
@@ -325,11 +532,13 @@ var _bindata = map[string]func() (*asset, error){
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -364,13 +573,22 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"assets": {nil, map[string]*bintree{
 		"dns": {nil, map[string]*bintree{
-			"cluster-role-binding.yaml": {assetsDnsClusterRoleBindingYaml, map[string]*bintree{}},
-			"cluster-role.yaml":         {assetsDnsClusterRoleYaml, map[string]*bintree{}},
-			"configmap.yaml":            {assetsDnsConfigmapYaml, map[string]*bintree{}},
-			"daemonset.yaml":            {assetsDnsDaemonsetYaml, map[string]*bintree{}},
-			"namespace.yaml":            {assetsDnsNamespaceYaml, map[string]*bintree{}},
-			"service-account.yaml":      {assetsDnsServiceAccountYaml, map[string]*bintree{}},
-			"service.yaml":              {assetsDnsServiceYaml, map[string]*bintree{}},
+			"cluster-role-binding.yaml":       {assetsDnsClusterRoleBindingYaml, map[string]*bintree{}},
+			"cluster-role.yaml":               {assetsDnsClusterRoleYaml, map[string]*bintree{}},
+			"configmap.yaml":                  {assetsDnsConfigmapYaml, map[string]*bintree{}},
+			"daemonset.yaml":                  {assetsDnsDaemonsetYaml, map[string]*bintree{}},
+			"deployment.yaml":                 {assetsDnsDeploymentYaml, map[string]*bintree{}},
+			"metrics-role-binding.yaml":       {assetsDnsMetricsRoleBindingYaml, map[string]*bintree{}},
+			"metrics-role.yaml":               {assetsDnsMetricsRoleYaml, map[string]*bintree{}},
+			"metrics-service-monitor.yaml":    {assetsDnsMetricsServiceMonitorYaml, map[string]*bintree{}},
+			"metrics-service.yaml":            {assetsDnsMetricsServiceYaml, map[string]*bintree{}},
+			"namespace.yaml":                  {assetsDnsNamespaceYaml, map[string]*bintree{}},
+			"node-local-cache-configmap.yaml": {assetsDnsNodeLocalCacheConfigmapYaml, map[string]*bintree{}},
+			"node-local-cache-daemonset.yaml": {assetsDnsNodeLocalCacheDaemonsetYaml, map[string]*bintree{}},
+			"poddisruptionbudget.yaml":        {assetsDnsPoddisruptionbudgetYaml, map[string]*bintree{}},
+			"prometheus-rule.yaml":            {assetsDnsPrometheusRuleYaml, map[string]*bintree{}},
+			"service-account.yaml":            {assetsDnsServiceAccountYaml, map[string]*bintree{}},
+			"service.yaml":                    {assetsDnsServiceYaml, map[string]*bintree{}},
 		}},
 	}},
 }}
@@ -6,19 +6,30 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 const (
-	DNSNamespaceAsset          = "assets/dns/namespace.yaml"
-	DNSServiceAccountAsset     = "assets/dns/service-account.yaml"
-	DNSClusterRoleAsset        = "assets/dns/cluster-role.yaml"
-	DNSClusterRoleBindingAsset = "assets/dns/cluster-role-binding.yaml"
-	DNSConfigMapAsset          = "assets/dns/configmap.yaml"
-	DNSDaemonSetAsset          = "assets/dns/daemonset.yaml"
-	DNSServiceAsset            = "assets/dns/service.yaml"
+	DNSNamespaceAsset            = "assets/dns/namespace.yaml"
+	DNSServiceAccountAsset       = "assets/dns/service-account.yaml"
+	DNSClusterRoleAsset          = "assets/dns/cluster-role.yaml"
+	DNSClusterRoleBindingAsset   = "assets/dns/cluster-role-binding.yaml"
+	DNSConfigMapAsset            = "assets/dns/configmap.yaml"
+	DNSDaemonSetAsset            = "assets/dns/daemonset.yaml"
+	DNSServiceAsset              = "assets/dns/service.yaml"
+	DNSDeploymentAsset           = "assets/dns/deployment.yaml"
+	DNSPodDisruptionBudgetAsset  = "assets/dns/poddisruptionbudget.yaml"
+	NodeLocalCacheConfigMapAsset = "assets/dns/node-local-cache-configmap.yaml"
+	NodeLocalCacheDaemonSetAsset = "assets/dns/node-local-cache-daemonset.yaml"
+	MetricsServiceAsset          = "assets/dns/metrics-service.yaml"
+	MetricsServiceMonitorAsset   = "assets/dns/metrics-service-monitor.yaml"
+	MetricsRoleAsset             = "assets/dns/metrics-role.yaml"
+	MetricsRoleBindingAsset      = "assets/dns/metrics-role-binding.yaml"
+	PrometheusRuleAsset          = "assets/dns/prometheus-rule.yaml"
 
 	// OwningDNSLabel should be applied to any objects "owned by" a
 	// dns to aid in selection (especially in cases where an ownerref
@@ -86,6 +97,78 @@ func DNSService() *corev1.Service {
 	return s
 }
 
+func DNSDeployment() *appsv1.Deployment {
+	d, err := NewDeployment(MustAssetReader(DNSDeploymentAsset))
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func DNSPodDisruptionBudget() *policyv1beta1.PodDisruptionBudget {
+	pdb, err := NewPodDisruptionBudget(MustAssetReader(DNSPodDisruptionBudgetAsset))
+	if err != nil {
+		panic(err)
+	}
+	return pdb
+}
+
+func NodeLocalCacheConfigMap() *corev1.ConfigMap {
+	cm, err := NewConfigMap(MustAssetReader(NodeLocalCacheConfigMapAsset))
+	if err != nil {
+		panic(err)
+	}
+	return cm
+}
+
+func NodeLocalCacheDaemonSet() *appsv1.DaemonSet {
+	ds, err := NewDaemonSet(MustAssetReader(NodeLocalCacheDaemonSetAsset))
+	if err != nil {
+		panic(err)
+	}
+	return ds
+}
+
+func MetricsService() *corev1.Service {
+	s, err := NewService(MustAssetReader(MetricsServiceAsset))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func MetricsServiceMonitor() *unstructured.Unstructured {
+	sm, err := NewUnstructured(MustAssetReader(MetricsServiceMonitorAsset))
+	if err != nil {
+		panic(err)
+	}
+	return sm
+}
+
+func PrometheusRule() *unstructured.Unstructured {
+	pr, err := NewUnstructured(MustAssetReader(PrometheusRuleAsset))
+	if err != nil {
+		panic(err)
+	}
+	return pr
+}
+
+func MetricsRole() *rbacv1.Role {
+	r, err := NewRole(MustAssetReader(MetricsRoleAsset))
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func MetricsRoleBinding() *rbacv1.RoleBinding {
+	rb, err := NewRoleBinding(MustAssetReader(MetricsRoleBindingAsset))
+	if err != nil {
+		panic(err)
+	}
+	return rb
+}
+
 func NewServiceAccount(manifest io.Reader) (*corev1.ServiceAccount, error) {
 	sa := corev1.ServiceAccount{}
 	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&sa); err != nil {
@@ -110,6 +193,32 @@ func NewClusterRoleBinding(manifest io.Reader) (*rbacv1.ClusterRoleBinding, erro
 	return &crb, nil
 }
 
+func NewRole(manifest io.Reader) (*rbacv1.Role, error) {
+	r := rbacv1.Role{}
+	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func NewRoleBinding(manifest io.Reader) (*rbacv1.RoleBinding, error) {
+	rb := rbacv1.RoleBinding{}
+	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&rb); err != nil {
+		return nil, err
+	}
+	return &rb, nil
+}
+
+// NewUnstructured decodes a manifest of a type that the operator does not
+// have Go types for.
+func NewUnstructured(manifest io.Reader) (*unstructured.Unstructured, error) {
+	u := unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&u.Object); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
 func NewConfigMap(manifest io.Reader) (*corev1.ConfigMap, error) {
 	cm := corev1.ConfigMap{}
 	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&cm); err != nil {
@@ -126,6 +235,22 @@ func NewDaemonSet(manifest io.Reader) (*appsv1.DaemonSet, error) {
 	return &ds, nil
 }
 
+func NewDeployment(manifest io.Reader) (*appsv1.Deployment, error) {
+	d := appsv1.Deployment{}
+	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func NewPodDisruptionBudget(manifest io.Reader) (*policyv1beta1.PodDisruptionBudget, error) {
+	pdb := policyv1beta1.PodDisruptionBudget{}
+	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&pdb); err != nil {
+		return nil, err
+	}
+	return &pdb, nil
+}
+
 func NewService(manifest io.Reader) (*corev1.Service, error) {
 	s := corev1.Service{}
 	if err := yaml.NewYAMLOrJSONDecoder(manifest, 100).Decode(&s); err != nil {
@@ -12,4 +12,13 @@ func TestManifests(t *testing.T) {
 	DNSDaemonSet()
 	DNSConfigMap()
 	DNSService()
+	DNSDeployment()
+	DNSPodDisruptionBudget()
+	NodeLocalCacheConfigMap()
+	NodeLocalCacheDaemonSet()
+	MetricsService()
+	MetricsServiceMonitor()
+	PrometheusRule()
+	MetricsRole()
+	MetricsRoleBinding()
 }
@@ -6,6 +6,8 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 
@@ -33,6 +35,9 @@ func init() {
 	if err := configv1.Install(scheme); err != nil {
 		panic(err)
 	}
+	if err := apiextensionsv1beta1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
 }
 
 func GetScheme() *runtime.Scheme {
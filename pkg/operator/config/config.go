@@ -1,14 +1,237 @@
 package config
 
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	"github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultMetricsBindAddress is the address on which metrics and the
+	// health endpoints are served when the configuration does not specify
+	// one.
+	DefaultMetricsBindAddress = ":60000"
+
+	// DefaultCoreDNSVersion is the version of CoreDNS in the CoreDNS image
+	// when the configuration does not specify one.
+	DefaultCoreDNSVersion = "1.6.2"
+
+	// DefaultLeaderElectionNamespace and DefaultLeaderElectionID locate
+	// the leader election lock when the configuration does not.
+	DefaultLeaderElectionNamespace = "openshift-dns-operator"
+	DefaultLeaderElectionID        = "dns-operator-lock"
+
+	// DefaultOperatorNamespace is the namespace of the operator when the
+	// configuration does not specify one.
+	DefaultOperatorNamespace = "openshift-dns-operator"
+
+	// DefaultOperandNamespace is the namespace of the dns resources when
+	// the configuration does not specify one.
+	DefaultOperandNamespace = "openshift-dns"
+
+	// DefaultResyncPeriod is how often each DNS is reconciled when nothing
+	// changes.
+	DefaultResyncPeriod = 5 * time.Minute
+)
+
+// pluginNameRegexp matches the name of a CoreDNS plugin as it appears in a
+// Corefile.
+var pluginNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
 // Config is configuration for the operator and should include things like
 // operated images, release version, etc.
 type Config struct {
 	// OperatorReleaseVersion is the current version of the operator.
-	OperatorReleaseVersion string
+	OperatorReleaseVersion string `json:"releaseVersion,omitempty"`
 
 	// CoreDNSImage is the CoreDNS image to manage.
-	CoreDNSImage string
+	CoreDNSImage string `json:"coreDNSImage,omitempty"`
+
+	// CoreDNSVersion is the version of CoreDNS in CoreDNSImage, which must
+	// be 1.3.0 or later.  The Corefile and the alerts are rendered for this
+	// version, and options of a DNS that it does not support are rejected
+	// rather than rendered into a Corefile that CoreDNS cannot load.
+	// Defaults to "1.6.2".
+	CoreDNSVersion string `json:"coreDNSVersion,omitempty"`
+
+	// CoreDNSPlugins are the plugins that CoreDNSImage is built with in
+	// addition to those of its CoreDNS release, such as external plugins.
+	// Options of a DNS that need a plugin that the image lacks are
+	// rejected.
+	CoreDNSPlugins []string `json:"coreDNSPlugins,omitempty"`
 
 	// OpenshiftCLIImage is the openshift client image to manage.
-	OpenshiftCLIImage string
+	OpenshiftCLIImage string `json:"openshiftCLIImage,omitempty"`
+
+	// LogLevel is the minimum level of logged messages: one of "debug",
+	// "info", "warning" or "error".  Defaults to "info".
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// LogFormat is the format of logged messages: "text" or "json".
+	// Defaults to "text".
+	LogFormat string `json:"logFormat,omitempty"`
+
+	// MetricsBindAddress is the address on which metrics are served.
+	// Defaults to ":60000".
+	MetricsBindAddress string `json:"metricsBindAddress,omitempty"`
+
+	// HealthBindAddress is the address on which the health endpoints are
+	// served.  Defaults to MetricsBindAddress, in which case metrics and
+	// the health endpoints share a listener.
+	HealthBindAddress string `json:"healthBindAddress,omitempty"`
+
+	// LeaderElection enables leader election, so that only one of several
+	// replicas of the operator reconciles at a time.
+	LeaderElection bool `json:"leaderElection,omitempty"`
+
+	// LeaderElectionNamespace is the namespace of the leader election
+	// lock.  Defaults to "openshift-dns-operator".
+	LeaderElectionNamespace string `json:"leaderElectionNamespace,omitempty"`
+
+	// LeaderElectionID is the name of the leader election lock.  Defaults
+	// to "dns-operator-lock".
+	LeaderElectionID string `json:"leaderElectionID,omitempty"`
+
+	// OperatorNamespace is the namespace in which the operator runs, which
+	// is reported as a related object of the clusteroperator.  Defaults to
+	// "openshift-dns-operator".
+	OperatorNamespace string `json:"operatorNamespace,omitempty"`
+
+	// OperandNamespace is the namespace of the dns resources: the CoreDNS
+	// workloads, their configmaps and services, and their RBAC.  Defaults
+	// to "openshift-dns".
+	OperandNamespace string `json:"operandNamespace,omitempty"`
+
+	// ResyncPeriod is how often each DNS is reconciled when nothing
+	// changes, which corrects drift that no watch reports.  Defaults to
+	// 5 minutes.
+	ResyncPeriod metav1.Duration `json:"resyncPeriod,omitempty"`
+}
+
+// Load reads configuration from the YAML file at path.  Fields that the
+// file does not set are left empty, and unknown fields are an error so
+// that typos are not silently ignored.
+func Load(path string) (Config, error) {
+	var config Config
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to read config file: %v", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// SetDefaults fills in the fields that are empty with their defaults.
+func (c *Config) SetDefaults() {
+	if len(c.CoreDNSVersion) == 0 {
+		c.CoreDNSVersion = DefaultCoreDNSVersion
+	}
+	if len(c.LogLevel) == 0 {
+		c.LogLevel = "info"
+	}
+	if len(c.LogFormat) == 0 {
+		c.LogFormat = "text"
+	}
+	if len(c.MetricsBindAddress) == 0 {
+		c.MetricsBindAddress = DefaultMetricsBindAddress
+	}
+	if len(c.HealthBindAddress) == 0 {
+		c.HealthBindAddress = c.MetricsBindAddress
+	}
+	if len(c.LeaderElectionNamespace) == 0 {
+		c.LeaderElectionNamespace = DefaultLeaderElectionNamespace
+	}
+	if len(c.LeaderElectionID) == 0 {
+		c.LeaderElectionID = DefaultLeaderElectionID
+	}
+	if len(c.OperatorNamespace) == 0 {
+		c.OperatorNamespace = DefaultOperatorNamespace
+	}
+	if len(c.OperandNamespace) == 0 {
+		c.OperandNamespace = DefaultOperandNamespace
+	}
+	if c.ResyncPeriod.Duration == 0 {
+		c.ResyncPeriod.Duration = DefaultResyncPeriod
+	}
+}
+
+// Validate returns an error describing every invalid field of the
+// configuration, or nil if the configuration is valid.  Fields are named
+// as they are in the config file.
+func (c *Config) Validate() error {
+	var errs []error
+	if len(c.CoreDNSImage) == 0 {
+		errs = append(errs, fmt.Errorf("coreDNSImage: must be specified"))
+	}
+	if _, err := version.Parse(c.CoreDNSVersion); err != nil {
+		errs = append(errs, fmt.Errorf("coreDNSVersion: %v", err))
+	}
+	for _, plugin := range c.CoreDNSPlugins {
+		if !pluginNameRegexp.MatchString(plugin) {
+			errs = append(errs, fmt.Errorf("coreDNSPlugins: invalid plugin name %q: must consist of lower case letters, digits and underscores", plugin))
+		}
+	}
+	if len(c.OpenshiftCLIImage) == 0 {
+		errs = append(errs, fmt.Errorf("openshiftCLIImage: must be specified"))
+	}
+	if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("logLevel: unsupported value %q; must be one of \"debug\", \"info\", \"warning\" or \"error\"", c.LogLevel))
+	}
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		errs = append(errs, fmt.Errorf("logFormat: unsupported value %q; must be \"text\" or \"json\"", c.LogFormat))
+	}
+	if err := validateBindAddress(c.MetricsBindAddress); err != nil {
+		errs = append(errs, fmt.Errorf("metricsBindAddress: %v", err))
+	}
+	if err := validateBindAddress(c.HealthBindAddress); err != nil {
+		errs = append(errs, fmt.Errorf("healthBindAddress: %v", err))
+	}
+	if msgs := validation.IsDNS1123Label(c.LeaderElectionNamespace); len(msgs) != 0 {
+		errs = append(errs, fmt.Errorf("leaderElectionNamespace: invalid value %q: %s", c.LeaderElectionNamespace, strings.Join(msgs, ", ")))
+	}
+	if msgs := validation.IsDNS1123Subdomain(c.LeaderElectionID); len(msgs) != 0 {
+		errs = append(errs, fmt.Errorf("leaderElectionID: invalid value %q: %s", c.LeaderElectionID, strings.Join(msgs, ", ")))
+	}
+	if msgs := validation.IsDNS1123Label(c.OperatorNamespace); len(msgs) != 0 {
+		errs = append(errs, fmt.Errorf("operatorNamespace: invalid value %q: %s", c.OperatorNamespace, strings.Join(msgs, ", ")))
+	}
+	if msgs := validation.IsDNS1123Label(c.OperandNamespace); len(msgs) != 0 {
+		errs = append(errs, fmt.Errorf("operandNamespace: invalid value %q: %s", c.OperandNamespace, strings.Join(msgs, ", ")))
+	}
+	if c.ResyncPeriod.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("resyncPeriod: must be positive, got %v", c.ResyncPeriod.Duration))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateBindAddress returns an error if address is not a host and port
+// to listen on.  The host may be empty to listen on all addresses.
+func validateBindAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %v", address, err)
+	}
+	if len(host) != 0 && net.ParseIP(host) == nil && len(validation.IsDNS1123Subdomain(host)) != 0 {
+		return fmt.Errorf("invalid address %q: invalid host %q", address, host)
+	}
+	if n, err := strconv.Atoi(port); err != nil || len(validation.IsValidPortNum(n)) != 0 {
+		return fmt.Errorf("invalid address %q: invalid port %q", address, port)
+	}
+	return nil
 }
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		description string
+		data        string
+		expect      Config
+		expectErr   string
+	}{
+		{
+			description: "all fields",
+			data: `
+releaseVersion: 4.1.0
+coreDNSImage: coredns:latest
+coreDNSVersion: 1.8.0
+coreDNSPlugins:
+- ratelimit
+openshiftCLIImage: cli:latest
+logLevel: debug
+logFormat: json
+metricsBindAddress: 127.0.0.1:8080
+healthBindAddress: :8081
+leaderElection: true
+leaderElectionNamespace: dns-operator
+leaderElectionID: lock
+operatorNamespace: dns-operator
+operandNamespace: dns
+resyncPeriod: 2m30s
+`,
+			expect: Config{
+				OperatorReleaseVersion:  "4.1.0",
+				CoreDNSImage:            "coredns:latest",
+				CoreDNSVersion:          "1.8.0",
+				CoreDNSPlugins:          []string{"ratelimit"},
+				OpenshiftCLIImage:       "cli:latest",
+				LogLevel:                "debug",
+				LogFormat:               "json",
+				MetricsBindAddress:      "127.0.0.1:8080",
+				HealthBindAddress:       ":8081",
+				LeaderElection:          true,
+				LeaderElectionNamespace: "dns-operator",
+				LeaderElectionID:        "lock",
+				OperatorNamespace:       "dns-operator",
+				OperandNamespace:        "dns",
+				ResyncPeriod:            metav1.Duration{Duration: 150 * time.Second},
+			},
+		},
+		{
+			description: "unknown field",
+			data:        "coreDNSImage: coredns:latest\nlogLevl: debug\n",
+			expectErr:   `unknown field "logLevl"`,
+		},
+		{
+			description: "invalid duration",
+			data:        "resyncPeriod: often\n",
+			expectErr:   "failed to parse config file",
+		},
+	}
+	for i, tc := range testCases {
+		path := filepath.Join(dir, strings.Replace(tc.description, " ", "-", -1)+".yaml")
+		if err := ioutil.WriteFile(path, []byte(tc.data), 0644); err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		config, err := Load(path)
+		switch {
+		case len(tc.expectErr) != 0 && err == nil:
+			t.Errorf("%s: expected error containing %q, got none", tc.description, tc.expectErr)
+		case len(tc.expectErr) != 0 && !strings.Contains(err.Error(), tc.expectErr):
+			t.Errorf("%s: expected error containing %q, got %v", tc.description, tc.expectErr, err)
+		case len(tc.expectErr) == 0 && err != nil:
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+		case len(tc.expectErr) == 0 && !reflect.DeepEqual(config, tc.expect):
+			t.Errorf("%s: expected %#v, got %#v", tc.description, tc.expect, config)
+		}
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("expected an error loading a missing file")
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	config := Config{MetricsBindAddress: ":8080"}
+	config.SetDefaults()
+	expect := Config{
+		CoreDNSVersion:          DefaultCoreDNSVersion,
+		LogLevel:                "info",
+		LogFormat:               "text",
+		MetricsBindAddress:      ":8080",
+		HealthBindAddress:       ":8080",
+		LeaderElectionNamespace: DefaultLeaderElectionNamespace,
+		LeaderElectionID:        DefaultLeaderElectionID,
+		OperatorNamespace:       DefaultOperatorNamespace,
+		OperandNamespace:        DefaultOperandNamespace,
+		ResyncPeriod:            metav1.Duration{Duration: DefaultResyncPeriod},
+	}
+	if !reflect.DeepEqual(config, expect) {
+		t.Errorf("expected %#v, got %#v", expect, config)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Config {
+		config := Config{
+			CoreDNSImage:      "coredns:latest",
+			OpenshiftCLIImage: "cli:latest",
+		}
+		config.SetDefaults()
+		return config
+	}
+
+	testCases := []struct {
+		description string
+		mutate      func(*Config)
+		expectErrs  []string
+	}{
+		{
+			description: "defaults",
+			mutate:      func(*Config) {},
+		},
+		{
+			description: "host and port",
+			mutate: func(c *Config) {
+				c.MetricsBindAddress = "127.0.0.1:8080"
+				c.HealthBindAddress = "localhost:8081"
+			},
+		},
+		{
+			description: "missing images",
+			mutate: func(c *Config) {
+				c.CoreDNSImage = ""
+				c.OpenshiftCLIImage = ""
+			},
+			expectErrs: []string{"coreDNSImage: must be specified", "openshiftCLIImage: must be specified"},
+		},
+		{
+			description: "invalid coredns version",
+			mutate: func(c *Config) {
+				c.CoreDNSVersion = "latest"
+			},
+			expectErrs: []string{`coreDNSVersion: invalid version "latest"`},
+		},
+		{
+			description: "coredns plugins",
+			mutate: func(c *Config) {
+				c.CoreDNSPlugins = []string{"ratelimit", "Rate-Limit"}
+			},
+			expectErrs: []string{`coreDNSPlugins: invalid plugin name "Rate-Limit"`},
+		},
+		{
+			description: "invalid logging",
+			mutate: func(c *Config) {
+				c.LogLevel = "loud"
+				c.LogFormat = "xml"
+			},
+			expectErrs: []string{`logLevel: unsupported value "loud"`, `logFormat: unsupported value "xml"`},
+		},
+		{
+			description: "invalid addresses",
+			mutate: func(c *Config) {
+				c.MetricsBindAddress = "8080"
+				c.HealthBindAddress = ":99999"
+			},
+			expectErrs: []string{`metricsBindAddress: invalid address "8080"`, `healthBindAddress: invalid address ":99999": invalid port`},
+		},
+		{
+			description: "invalid leader election lock",
+			mutate: func(c *Config) {
+				c.LeaderElectionNamespace = "DNS_Operator"
+				c.LeaderElectionID = "-lock"
+			},
+			expectErrs: []string{`leaderElectionNamespace: invalid value "DNS_Operator"`, `leaderElectionID: invalid value "-lock"`},
+		},
+		{
+			description: "invalid namespaces",
+			mutate: func(c *Config) {
+				c.OperatorNamespace = "dns-operator.example"
+				c.OperandNamespace = "dns.example"
+			},
+			expectErrs: []string{`operatorNamespace: invalid value "dns-operator.example"`, `operandNamespace: invalid value "dns.example"`},
+		},
+		{
+			description: "negative resync period",
+			mutate: func(c *Config) {
+				c.ResyncPeriod = metav1.Duration{Duration: -time.Minute}
+			},
+			expectErrs: []string{"resyncPeriod: must be positive"},
+		},
+	}
+	for _, tc := range testCases {
+		config := valid()
+		tc.mutate(&config)
+		err := config.Validate()
+		if len(tc.expectErrs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.description, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected errors %q, got none", tc.description, tc.expectErrs)
+			continue
+		}
+		for _, expect := range tc.expectErrs {
+			if !strings.Contains(err.Error(), expect) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.description, expect, err)
+			}
+		}
+	}
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -11,13 +16,17 @@ import (
 	"github.com/openshift/cluster-dns-operator/pkg/manifests"
 	operatorclient "github.com/openshift/cluster-dns-operator/pkg/operator/client"
 	"github.com/openshift/cluster-dns-operator/pkg/util/slice"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
 
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/sirupsen/logrus"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 
 	"github.com/apparentlymart/go-cidr/cidr"
 
@@ -26,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -49,31 +59,72 @@ const (
 //
 // The controller will be pre-configured to watch for DNS resources.
 func New(mgr manager.Manager, config Config) (controller.Controller, error) {
+	if len(config.OperandNamespace) == 0 {
+		return nil, fmt.Errorf("operand namespace must be specified")
+	}
+	if err := validateCoreDNSVersion(config.CoreDNSVersion); err != nil {
+		return nil, err
+	}
+	if !config.coreDNSImage().supports("ready") {
+		logrus.Warningf("CoreDNS %s lacks the ready plugin, so the readiness of CoreDNS pods is reported by the health plugin, which does not wait for the kubernetes plugin to sync", config.CoreDNSVersion)
+	}
+
 	kubeClient, err := operatorclient.NewClient(config.KubeConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kube client: %v", err)
 	}
 
+	// The manager's cache is restricted to the operand namespace, so
+	// cluster-scoped and all-namespace watches need their own cache.
+	clusterCache, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster cache: %v", err)
+	}
+	if err := mgr.Add(clusterCache); err != nil {
+		return nil, fmt.Errorf("failed to add cluster cache to manager: %v", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config.KubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %v", err)
+	}
+
 	reconciler := &reconciler{
-		Config: config,
-		client: kubeClient,
+		Config:       config,
+		client:       kubeClient,
+		discovery:    discoveryClient,
+		clusterCache: clusterCache,
+		recorder:     mgr.GetEventRecorderFor("dns-operator"),
+		log:          logrus.NewEntry(logrus.StandardLogger()),
 	}
 	c, err := controller.New("operator-controller", mgr, controller.Options{Reconciler: reconciler})
 	if err != nil {
 		return nil, err
 	}
+	reconciler.controller = c
 	if err := c.Watch(&source.Kind{Type: &operatorv1.DNS{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return nil, err
 	}
 	if err := c.Watch(&source.Kind{Type: &appsv1.DaemonSet{}}, &handler.EnqueueRequestForOwner{OwnerType: &operatorv1.DNS{}}); err != nil {
 		return nil, err
 	}
+	if err := c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{OwnerType: &operatorv1.DNS{}}); err != nil {
+		return nil, err
+	}
+	if err := c.Watch(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, &handler.EnqueueRequestForOwner{OwnerType: &operatorv1.DNS{}}); err != nil {
+		return nil, err
+	}
 	if err := c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{OwnerType: &operatorv1.DNS{}}); err != nil {
 		return nil, err
 	}
 	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestForOwner{OwnerType: &operatorv1.DNS{}}); err != nil {
 		return nil, err
 	}
+	// Blocklists reference ConfigMaps in the operand namespace, which the
+	// default dns is reconciled for when they change.
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, enqueueDefaultDNS(), blocklistConfigMapPredicate()); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
@@ -83,6 +134,31 @@ type Config struct {
 	CoreDNSImage           string
 	OpenshiftCLIImage      string
 	OperatorReleaseVersion string
+
+	// CoreDNSVersion is the version of CoreDNS in CoreDNSImage, which
+	// determines the Corefile options that may be rendered.
+	CoreDNSVersion version.Version
+
+	// CoreDNSPlugins are the plugins that CoreDNSImage is built with in
+	// addition to those of its CoreDNS release.
+	CoreDNSPlugins []string
+
+	// OperandNamespace is the namespace of the dns resources.  It is
+	// required.
+	OperandNamespace string
+
+	// OperatorNamespace is the namespace of the operator, which is
+	// reported as a related object of the clusteroperator if it is set.
+	OperatorNamespace string
+
+	// ResyncPeriod is how often each DNS is reconciled when nothing
+	// changes, which corrects drift that no watch reports and bounds the
+	// age of the last reconcile for readiness.
+	ResyncPeriod time.Duration
+
+	// ReconcileFinished, if it is set, is called with the time at which
+	// each reconcile finishes.
+	ReconcileFinished func(time.Time)
 }
 
 // reconciler handles the actual dns reconciliation logic in response to
@@ -96,6 +172,62 @@ type reconciler struct {
 	// Since this controller is running in single threaded mode,
 	// we do not need to synchronize when changing rest scheme/mapper fields.
 	client kclient.Client
+
+	// controller is the controller that runs this reconciler; watches that
+	// are only needed for some configurations are added to it lazily.
+	controller controller.Controller
+
+	// clusterCache caches cluster-scoped and all-namespace resources.
+	clusterCache cache.Cache
+
+	// clusterWatches records the kinds of resources that are being watched
+	// through clusterCache.
+	clusterWatches map[string]bool
+
+	// discovery is used to detect whether optional APIs such as
+	// ServiceMonitor are served.
+	discovery discovery.DiscoveryInterface
+
+	// monitoringKinds records the kinds of the monitoring API that were
+	// served at the last reconcile.
+	monitoringKinds map[string]bool
+
+	// endpointSlicesServed records whether the EndpointSlice API was
+	// served at the last reconcile.
+	endpointSlicesServed bool
+
+	// recorder records events on dnses and the resources that the operator
+	// manages for them.
+	recorder record.EventRecorder
+
+	// scaffoldingErr is the error ensuring the namespace and RBAC objects
+	// at the last reconcile, if any.  It is reported in the operator
+	// status.
+	scaffoldingErr error
+
+	// scaffoldingDrift is the last drift of the namespace and RBAC objects
+	// that the operator corrected, if any.  It is reported in the operator
+	// status.
+	scaffoldingDrift *scaffoldingDrift
+
+	// log is the logger of the current reconcile, with fields that
+	// correlate its messages.
+	log *logrus.Entry
+
+	// reconcileCount is the number of reconciles that have started, and
+	// failedReconciles is the number of consecutive reconciles that have
+	// failed.
+	reconcileCount   uint64
+	failedReconciles int
+}
+
+// scaffoldingDrift describes a drift of a scaffolding object from its
+// manifest that the operator corrected.
+type scaffoldingDrift struct {
+	// described describes the object that drifted.
+	described string
+	// corrected is the time at which the drift was corrected.
+	corrected time.Time
 }
 
 // Reconcile expects request to refer to a dns and will do all the work
@@ -104,13 +236,19 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 	errs := []error{}
 	result := reconcile.Result{}
 
-	logrus.Infof("reconciling request: %v", request)
+	r.startReconcileLog(request)
+	r.log.Info("reconciling request")
+	if r.ReconcileFinished != nil {
+		defer func() { r.ReconcileFinished(time.Now()) }()
+	}
 
 	if request.NamespacedName.Name != DefaultDNSController {
 		// Return a nil error value to avoid re-triggering the event.
-		logrus.Errorf("skipping unexpected dns %s", request.NamespacedName.Name)
+		r.log.Error("skipping unexpected dns")
 		return result, nil
 	}
+	result.RequeueAfter = r.ResyncPeriod
+
 	// Get the current dns state.
 	dns := &operatorv1.DNS{}
 	if err := r.client.Get(context.TODO(), request.NamespacedName, dns); err != nil {
@@ -118,7 +256,7 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 			// This means the dns was already deleted/finalized and there are
 			// stale queue entries (or something edge triggering from a related
 			// resource that got deleted async).
-			logrus.Infof("dns not found; reconciliation will be skipped for request: %v", request)
+			r.log.Info("dns not found; reconciliation will be skipped")
 		} else {
 			errs = append(errs, fmt.Errorf("failed to get dns %s: %v", request, err))
 		}
@@ -127,7 +265,17 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 
 	if dns != nil {
 		// Ensure we have all the necessary scaffolding on which to place dns instances.
-		if err := r.ensureDNSNamespace(); err != nil {
+		start := time.Now()
+		drifted, err := r.ensureDNSNamespace()
+		observeReconcile("namespace", start, err)
+		r.scaffoldingErr = err
+		// Corrected drift is reported by events and the drift metric, and
+		// the last correction is kept for the operator status.
+		for _, d := range drifted {
+			r.recordDriftCorrected(dns, d)
+			r.scaffoldingDrift = &scaffoldingDrift{described: d, corrected: start}
+		}
+		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to ensure dns namespace: %v", err))
 		}
 
@@ -149,6 +297,7 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 						errs = append(errs, fmt.Errorf("failed to remove finalizer from dns %s: %v", dns.Name, err))
 					}
 				}
+				forgetDNS(dns)
 			}
 		} else if err := r.enforceDNSFinalizer(dns); err != nil {
 			errs = append(errs, fmt.Errorf("failed to enforce finalizer for dns %s: %v", dns.Name, err))
@@ -167,9 +316,18 @@ func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		errs = append(errs, fmt.Errorf("failed to sync operator status: %v", err))
 	}
 
-	// Log in case of errors as the controller's logs get eaten.
+	if dns != nil && dns.DeletionTimestamp == nil && len(errs) == 0 {
+		lastSuccessfulReconcile.WithLabelValues(dns.Name).SetToCurrentTime()
+	}
+
+	// Log and record an event in case of errors as the controller's logs
+	// get eaten.
+	r.finishReconcileLog(len(errs) == 0)
 	if len(errs) > 0 {
-		logrus.Errorf("failed to reconcile request %s: %v", request, utilerrors.NewAggregate(errs))
+		r.log.WithError(utilerrors.NewAggregate(errs)).Error("failed to reconcile request")
+		if dns != nil {
+			r.recordEvent(dns, nil, corev1.EventTypeWarning, eventReasonReconcileFailed, "Failed to reconcile: %v", utilerrors.NewAggregate(errs))
+		}
 	}
 	return result, utilerrors.NewAggregate(errs)
 }
@@ -199,9 +357,11 @@ func (r *reconciler) ensureExternalNameForOpenshiftService() error {
 		}
 
 		if err := r.client.Create(context.TODO(), svc); err != nil {
+			r.recordCreateFailed(nil, svc, err)
 			return fmt.Errorf("failed to create external name service %s/%s: %v", svc.Namespace, svc.Name, err)
 		}
-		logrus.Infof("created external name service %s/%s", svc.Namespace, svc.Name)
+		r.logObject(svc).Info("created external name service")
+		r.recordCreated(nil, svc)
 	}
 	return nil
 }
@@ -218,10 +378,15 @@ func (r *reconciler) ensureOpenshiftExternalNameServiceDeleted() error {
 			Namespace: "default",
 		},
 	}
-	if err := r.client.Delete(context.TODO(), svc); err != nil && !errors.IsNotFound(err) {
+	if err := r.client.Delete(context.TODO(), svc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		r.recordDeleteFailed(nil, svc, err)
 		return fmt.Errorf("failed to delete external name service %s/%s: %v", svc.Namespace, svc.Name, err)
 	}
-	logrus.Infof("deleted external name service %s/%s", svc.Namespace, svc.Name)
+	r.logObject(svc).Info("deleted external name service")
+	r.recordDeleted(nil, svc)
 	return nil
 }
 
@@ -232,7 +397,7 @@ func (r *reconciler) enforceDNSFinalizer(dns *operatorv1.DNS) error {
 		if err := r.client.Update(context.TODO(), dns); err != nil {
 			return err
 		}
-		logrus.Infof("enforced finalizer for dns: %s", dns.Name)
+		r.log.Info("enforced finalizer for dns")
 	}
 	return nil
 }
@@ -245,56 +410,18 @@ func (r *reconciler) ensureDNSDeleted(dns *operatorv1.DNS) error {
 	if err := r.ensureDNSDaemonSetDeleted(dns); err != nil {
 		return fmt.Errorf("failed to delete daemonset for dns %s: %v", dns.Name, err)
 	}
-	return nil
-}
-
-// ensureDNSNamespace ensures all the necessary scaffolding exists for
-// dns generally, including a namespace and all RBAC setup.
-func (r *reconciler) ensureDNSNamespace() error {
-	ns := manifests.DNSNamespace()
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: ns.Name}, ns); err != nil {
-		if !errors.IsNotFound(err) {
-			return fmt.Errorf("failed to get dns namespace %q: %v", ns.Name, err)
-		}
-		if err := r.client.Create(context.TODO(), ns); err != nil {
-			return fmt.Errorf("failed to create dns namespace %s: %v", ns.Name, err)
-		}
-		logrus.Infof("created dns namespace: %s", ns.Name)
+	if err := r.ensureDNSDeploymentDeleted(dns); err != nil {
+		return fmt.Errorf("failed to delete deployment for dns %s: %v", dns.Name, err)
 	}
-
-	cr := manifests.DNSClusterRole()
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: cr.Name}, cr); err != nil {
-		if !errors.IsNotFound(err) {
-			return fmt.Errorf("failed to get dns cluster role %s: %v", cr.Name, err)
-		}
-		if err := r.client.Create(context.TODO(), cr); err != nil {
-			return fmt.Errorf("failed to create dns cluster role %s: %v", cr.Name, err)
-		}
-		logrus.Infof("created dns cluster role: %s", cr.Name)
+	if err := r.ensureNodeLocalCacheDeleted(dns); err != nil {
+		return fmt.Errorf("failed to delete node-local cache for dns %s: %v", dns.Name, err)
 	}
-
-	crb := manifests.DNSClusterRoleBinding()
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: crb.Name}, crb); err != nil {
-		if !errors.IsNotFound(err) {
-			return fmt.Errorf("failed to get dns cluster role binding %s: %v", crb.Name, err)
-		}
-		if err := r.client.Create(context.TODO(), crb); err != nil {
-			return fmt.Errorf("failed to create dns cluster role binding %s: %v", crb.Name, err)
-		}
-		logrus.Infof("created dns cluster role binding: %s", crb.Name)
+	if err := r.ensureDNSPodDisruptionBudgetDeleted(dns); err != nil {
+		return fmt.Errorf("failed to delete pod disruption budget for dns %s: %v", dns.Name, err)
 	}
-
-	sa := manifests.DNSServiceAccount()
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: sa.Namespace, Name: sa.Name}, sa); err != nil {
-		if !errors.IsNotFound(err) {
-			return fmt.Errorf("failed to get dns service account %s/%s: %v", sa.Namespace, sa.Name, err)
-		}
-		if err := r.client.Create(context.TODO(), sa); err != nil {
-			return fmt.Errorf("failed to create dns service account %s/%s: %v", sa.Namespace, sa.Name, err)
-		}
-		logrus.Infof("created dns service account: %s/%s", sa.Namespace, sa.Name)
+	if err := r.ensureDNSMetricsDeleted(dns); err != nil {
+		return fmt.Errorf("failed to delete metrics for dns %s: %v", dns.Name, err)
 	}
-
 	return nil
 }
 
@@ -307,53 +434,248 @@ func (r *reconciler) ensureDNS(dns *operatorv1.DNS) error {
 		return fmt.Errorf("failed to get cluster IP from network config: %v", err)
 	}
 
+	workloadKind := "daemonset"
+	if dns.Spec.Topology.Mode == operatorv1.DeploymentDNSTopologyMode {
+		workloadKind = "deployment"
+	}
+
+	specErr := validateDNS(dns, clusterDomain, r.coreDNSImage())
+	if specErr == nil {
+		denied, err := r.dnsPodsDenied(dns)
+		if err != nil {
+			return err
+		}
+		if len(denied) != 0 {
+			specErr = fmt.Errorf("pods mode %s requires service account %s/%s to be allowed to %s pods", operatorv1.VerifiedDNSPodsMode, r.OperandNamespace, manifests.DNSServiceAccount().Name, strings.Join(denied, " and "))
+		}
+	}
+	if specErr != nil {
+		errs := []error{fmt.Errorf("invalid spec: %v", specErr)}
+		if err := r.syncDNSStatus(dns, clusterIP, clusterDomain, nil, nil, specErr); err != nil {
+			errs = append(errs, fmt.Errorf("failed to sync status of dns %s: %v", dns.Name, err))
+		}
+		return utilerrors.NewAggregate(errs)
+	}
+
 	errs := []error{}
-	if daemonset, err := r.ensureDNSDaemonSet(dns, clusterIP, clusterDomain); err != nil {
-		errs = append(errs, fmt.Errorf("failed to ensure daemonset for dns %s: %v", dns.Name, err))
+	start := time.Now()
+	workloadRef, err := r.ensureDNSWorkload(dns, clusterIP, clusterDomain)
+	observeReconcile(workloadKind, start, err)
+	if err != nil {
+		errs = append(errs, err)
 	} else {
-		trueVar := true
-		daemonsetRef := metav1.OwnerReference{
-			APIVersion: "apps/v1",
-			Kind:       "DaemonSet",
-			Name:       daemonset.Name,
-			UID:        daemonset.UID,
-			Controller: &trueVar,
+		start = time.Now()
+		// Blocklists that are too large for the Corefile are an invalid
+		// spec; the configmap keeps the blocklists it last had.
+		var blocklistsErr error
+		blocklists, err := r.currentDNSBlocklists(dns, clusterDomain)
+		if _, ok := err.(*blocklistsTooLargeError); ok {
+			blocklistsErr = err
+		} else if err == nil {
+			_, err = r.ensureDNSConfigMap(dns, clusterDomain, blocklists, workloadRef)
 		}
-
-		if _, err := r.ensureDNSConfigMap(dns, clusterDomain, daemonsetRef); err != nil {
+		observeReconcile("configmap", start, err)
+		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to create configmap for dns %s: %v", dns.Name, err))
+			blocklists = nil
 		}
-		if _, err := r.ensureDNSService(dns, clusterIP, daemonsetRef); err != nil {
+		start = time.Now()
+		_, err = r.ensureDNSService(dns, clusterIP, workloadRef)
+		observeReconcile("service", start, err)
+		if err != nil {
 			errs = append(errs, fmt.Errorf("failed to create service for dns %s: %v", dns.Name, err))
 		}
+		start = time.Now()
+		err = r.ensureDNSPodDisruptionBudget(dns)
+		observeReconcile("poddisruptionbudget", start, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to ensure pod disruption budget for dns %s: %v", dns.Name, err))
+		}
+		start = time.Now()
+		err = r.ensureDNSMetrics(dns)
+		observeReconcile("monitoring", start, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to ensure metrics for dns %s: %v", dns.Name, err))
+		}
+		start = time.Now()
+		nodeLocalCache, err := r.ensureNodeLocalCache(dns, clusterIP, clusterDomain)
+		observeReconcile("nodelocalcache", start, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to ensure node-local cache for dns %s: %v", dns.Name, err))
+		}
 
-		if err := r.syncDNSStatus(dns, clusterIP, clusterDomain); err != nil {
-			errs = append(errs, fmt.Errorf("failed to sync status of dns %s/%s: %v", daemonset.Namespace, daemonset.Name, err))
+		if err := r.syncDNSStatus(dns, clusterIP, clusterDomain, nodeLocalCache, blocklists, blocklistsErr); err != nil {
+			errs = append(errs, fmt.Errorf("failed to sync status of dns %s: %v", dns.Name, err))
 		}
 	}
 
 	return utilerrors.NewAggregate(errs)
 }
 
-// syncDNSStatus updates the status for a given dns.
-func (r *reconciler) syncDNSStatus(dns *operatorv1.DNS, clusterIP, clusterDomain string) error {
+// validateDNS returns an error describing every invalid field of the dns's
+// spec, including options that the CoreDNS image does not support.
+func validateDNS(dns *operatorv1.DNS, clusterDomain string, image coreDNSImage) error {
+	var errs []error
+	for _, validate := range []func() error{
+		func() error { return validateDNSKubernetes(dns, image) },
+		func() error { return validateDNSServers(dns, clusterDomain) },
+		func() error { return validateDNSBlocklists(dns, clusterDomain) },
+		func() error { return validateDNSRewrites(dns) },
+		func() error { return validateDNSPolicy(dns, image) },
+	} {
+		if err := validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.Flatten(utilerrors.NewAggregate(errs))
+}
+
+// ensureDNSWorkload ensures that the workload of the dns's topology runs
+// CoreDNS and returns a reference to it.  The workload of the other topology
+// is only deleted once the desired workload has available pods, so that
+// switching topologies does not interrupt DNS.
+func (r *reconciler) ensureDNSWorkload(dns *operatorv1.DNS, clusterIP, clusterDomain string) (metav1.OwnerReference, error) {
+	trueVar := true
+	switch dns.Spec.Topology.Mode {
+	case "", operatorv1.DaemonSetDNSTopologyMode:
+		daemonset, err := r.ensureDNSDaemonSet(dns, clusterIP, clusterDomain)
+		if err != nil {
+			return metav1.OwnerReference{}, fmt.Errorf("failed to ensure daemonset for dns %s: %v", dns.Name, err)
+		}
+		if daemonset.Status.NumberAvailable > 0 {
+			if err := r.ensureDNSDeploymentDeleted(dns); err != nil {
+				return metav1.OwnerReference{}, fmt.Errorf("failed to delete deployment for dns %s: %v", dns.Name, err)
+			}
+		}
+		return metav1.OwnerReference{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+			Name:       daemonset.Name,
+			UID:        daemonset.UID,
+			Controller: &trueVar,
+		}, nil
+	case operatorv1.DeploymentDNSTopologyMode:
+		deployment, err := r.ensureDNSDeployment(dns, clusterIP, clusterDomain)
+		if err != nil {
+			return metav1.OwnerReference{}, fmt.Errorf("failed to ensure deployment for dns %s: %v", dns.Name, err)
+		}
+		if deployment.Status.AvailableReplicas > 0 {
+			if err := r.ensureDNSDaemonSetDeleted(dns); err != nil {
+				return metav1.OwnerReference{}, fmt.Errorf("failed to delete daemonset for dns %s: %v", dns.Name, err)
+			}
+		}
+		return metav1.OwnerReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       deployment.Name,
+			UID:        deployment.UID,
+			Controller: &trueVar,
+		}, nil
+	default:
+		return metav1.OwnerReference{}, fmt.Errorf("unsupported topology mode %q for dns %s", dns.Spec.Topology.Mode, dns.Name)
+	}
+}
+
+// syncDNSStatus updates the status for a given dns.  nodeLocalCache is the
+// node-local cache daemonset, or nil if it is disabled or could not be
+// ensured, blocklists are the blocklists rendered into the Corefile, or nil
+// if they could not be rendered, and specErr is the error validating the
+// dns's spec, if any.
+func (r *reconciler) syncDNSStatus(dns *operatorv1.DNS, clusterIP, clusterDomain string, nodeLocalCache *appsv1.DaemonSet, blocklists []blocklistParameters, specErr error) error {
 	current := &operatorv1.DNS{}
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: dns.Name}, current); err != nil {
 		return fmt.Errorf("failed to get dns %s: %v", dns.Name, err)
 	}
-	if current.Status.ClusterIP == clusterIP &&
-		current.Status.ClusterDomain == clusterDomain {
+	updated := current.DeepCopy()
+	updated.Status.ClusterIP = clusterIP
+	updated.Status.ClusterDomain = clusterDomain
+	updated.Status.Conditions = setDNSStatusCondition(updated.Status.Conditions, specValidCondition(specErr))
+	switch {
+	case nodeLocalCache != nil:
+		updated.Status.Conditions = setDNSStatusCondition(updated.Status.Conditions, nodeLocalCacheCondition(nodeLocalCache))
+	case !dns.Spec.NodeLocalCache.Enabled:
+		updated.Status.Conditions = removeDNSStatusCondition(updated.Status.Conditions, operatorv1.DNSNodeLocalCacheAvailable)
+		forgetDNSCondition(dns.Name, operatorv1.DNSNodeLocalCacheAvailable)
+	}
+	switch {
+	case blocklists != nil:
+		updated.Status.Blocklists = dnsBlocklistStatuses(blocklists)
+	case len(dns.Spec.Blocklists) == 0:
+		updated.Status.Blocklists = nil
+	}
+	recordDNSStatus(updated)
+	if dnsStatusesEqual(current.Status, updated.Status) {
 		return nil
 	}
-	current.Status.ClusterIP = clusterIP
-	current.Status.ClusterDomain = clusterDomain
 
-	if err := r.client.Status().Update(context.TODO(), current); err != nil {
-		return fmt.Errorf("failed to update status for dns %s: %v", current.Name, err)
+	if err := r.client.Status().Update(context.TODO(), updated); err != nil {
+		return fmt.Errorf("failed to update status for dns %s: %v", updated.Name, err)
 	}
 	return nil
 }
 
+// specValidCondition returns the SpecValid condition for the given error
+// validating a dns's spec.
+func specValidCondition(specErr error) operatorv1.OperatorCondition {
+	if specErr != nil {
+		return operatorv1.OperatorCondition{
+			Type:    operatorv1.DNSSpecValid,
+			Status:  operatorv1.ConditionFalse,
+			Reason:  "InvalidSpec",
+			Message: specErr.Error(),
+		}
+	}
+	return operatorv1.OperatorCondition{
+		Type:   operatorv1.DNSSpecValid,
+		Status: operatorv1.ConditionTrue,
+		Reason: "AsExpected",
+	}
+}
+
+// setDNSStatusCondition returns the result of setting the specified condition
+// in the given slice of dns conditions.
+func setDNSStatusCondition(conditions []operatorv1.OperatorCondition, condition operatorv1.OperatorCondition) []operatorv1.OperatorCondition {
+	condition.LastTransitionTime = metav1.Now()
+	for i, c := range conditions {
+		if c.Type != condition.Type {
+			continue
+		}
+		if c.Status == condition.Status &&
+			c.Reason == condition.Reason &&
+			c.Message == condition.Message {
+			return conditions
+		}
+		updated := append([]operatorv1.OperatorCondition{}, conditions...)
+		updated[i] = condition
+		return updated
+	}
+	return append(conditions, condition)
+}
+
+// removeDNSStatusCondition returns the given slice of dns conditions without
+// any condition of the specified type.
+func removeDNSStatusCondition(conditions []operatorv1.OperatorCondition, conditionType string) []operatorv1.OperatorCondition {
+	updated := []operatorv1.OperatorCondition{}
+	for _, c := range conditions {
+		if c.Type != conditionType {
+			updated = append(updated, c)
+		}
+	}
+	return updated
+}
+
+// dnsStatusesEqual compares two DNSStatus values.  Returns true if the
+// provided values should be considered equal for the purpose of determining
+// whether an update is necessary, false otherwise.
+func dnsStatusesEqual(a, b operatorv1.DNSStatus) bool {
+	cmpOpts := []cmp.Option{
+		cmpopts.IgnoreFields(operatorv1.OperatorCondition{}, "LastTransitionTime"),
+		cmpopts.EquateEmpty(),
+		cmpopts.SortSlices(func(a, b operatorv1.OperatorCondition) bool { return a.Type < b.Type }),
+	}
+	return cmp.Equal(a, b, cmpOpts...)
+}
+
 // getClusterIPFromNetworkConfig will return 10th IP from the service CIDR range
 // defined in the cluster network config.
 func (r *reconciler) getClusterIPFromNetworkConfig() (string, error) {
@@ -361,13 +683,18 @@ func (r *reconciler) getClusterIPFromNetworkConfig() (string, error) {
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: "cluster"}, networkConfig); err != nil {
 		return "", fmt.Errorf("failed to get network 'cluster': %v", err)
 	}
+	return clusterIPForServiceNetwork(networkConfig.Status.ServiceNetwork)
+}
 
-	if len(networkConfig.Status.ServiceNetwork) == 0 {
+// clusterIPForServiceNetwork returns the 10th IP of the first of the given
+// service networks, which is the cluster IP of the dns service.
+func clusterIPForServiceNetwork(serviceNetwork []string) (string, error) {
+	if len(serviceNetwork) == 0 {
 		return "", fmt.Errorf("no service networks found in cluster network config")
 	}
-	_, serviceCIDR, err := net.ParseCIDR(networkConfig.Status.ServiceNetwork[0])
+	_, serviceCIDR, err := net.ParseCIDR(serviceNetwork[0])
 	if err != nil {
-		return "", fmt.Errorf("invalid service cidr %s: %v", networkConfig.Status.ServiceNetwork[0], err)
+		return "", fmt.Errorf("invalid service cidr %s: %v", serviceNetwork[0], err)
 	}
 
 	dnsClusterIP, err := cidr.Host(serviceCIDR, 10)
@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/go-cmp/cmp"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// defaultServfailPercent, defaultForwardLatencyMilliseconds,
+	// defaultCacheHitPercent and defaultRolloutMinutes are the alert
+	// thresholds that are used when the dns does not specify them.
+	defaultServfailPercent            = 5
+	defaultForwardLatencyMilliseconds = 1000
+	defaultCacheHitPercent            = 10
+	defaultRolloutMinutes             = 30
+)
+
+// alertRule is an alerting rule of a PrometheusRule.
+type alertRule struct {
+	alert    string
+	expr     string
+	duration string
+	severity string
+	message  string
+}
+
+// ensureDNSPrometheusRule ensures that the prometheus rule with the alerts
+// for the dns exists and matches the desired one.
+func (r *reconciler) ensureDNSPrometheusRule(dns *operatorv1.DNS) error {
+	desired, err := desiredDNSPrometheusRule(r.OperandNamespace, dns, r.coreDNSImage())
+	if err != nil {
+		return fmt.Errorf("failed to build dns prometheus rule: %v", err)
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(prometheusRuleGVK)
+	if err := r.client.Get(context.TODO(), DNSPrometheusRuleName(r.OperandNamespace, dns), current); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get dns prometheus rule: %v", err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return fmt.Errorf("failed to create dns prometheus rule %s/%s: %v", desired.GetNamespace(), desired.GetName(), err)
+		}
+		r.logObject(desired).Info("created dns prometheus rule")
+		r.recordCreated(dns, desired)
+		return nil
+	}
+	if cmp.Equal(current.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Object["spec"] = desired.Object["spec"]
+	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(dns, updated, err)
+		return fmt.Errorf("failed to update dns prometheus rule %s/%s: %v", updated.GetNamespace(), updated.GetName(), err)
+	}
+	recordDriftCorrection("prometheusrule")
+	r.logObject(updated).Info("updated dns prometheus rule")
+	r.recordUpdated(dns, updated)
+	return nil
+}
+
+// desiredDNSPrometheusRule returns the desired prometheus rule for the dns,
+// with alerts on the metrics that the CoreDNS image exports.
+func desiredDNSPrometheusRule(namespace string, dns *operatorv1.DNS, image coreDNSImage) (*unstructured.Unstructured, error) {
+	pr := manifests.PrometheusRule()
+
+	name := DNSPrometheusRuleName(namespace, dns)
+	pr.SetNamespace(name.Namespace)
+	pr.SetName(name.Name)
+	pr.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+
+	labels := pr.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[manifests.OwningDNSLabel] = DNSDaemonSetLabel(dns)
+	pr.SetLabels(labels)
+
+	rules := []interface{}{}
+	for _, rule := range dnsAlertRules(namespace, dns, image) {
+		rules = append(rules, map[string]interface{}{
+			"alert": rule.alert,
+			"expr":  rule.expr,
+			"for":   rule.duration,
+			"labels": map[string]interface{}{
+				"severity": rule.severity,
+			},
+			"annotations": map[string]interface{}{
+				"message": rule.message,
+			},
+		})
+	}
+	groups, _, err := unstructured.NestedSlice(pr.Object, "spec", "groups")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule groups of %s: %v", manifests.PrometheusRuleAsset, err)
+	}
+	if len(groups) != 1 {
+		return nil, fmt.Errorf("expected a single rule group in %s, got %d", manifests.PrometheusRuleAsset, len(groups))
+	}
+	group, ok := groups[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid rule group in %s", manifests.PrometheusRuleAsset)
+	}
+	group["rules"] = rules
+	if err := unstructured.SetNestedSlice(pr.Object, groups, "spec", "groups"); err != nil {
+		return nil, fmt.Errorf("failed to set rule groups: %v", err)
+	}
+	return pr, nil
+}
+
+// dnsAlertRules returns the alerting rules for the dns, using the thresholds
+// from the dns spec or their defaults and the metric names of the CoreDNS
+// image.
+func dnsAlertRules(namespace string, dns *operatorv1.DNS, image coreDNSImage) []alertRule {
+	alerts := dns.Spec.Alerts
+	servfailPercent := int32(defaultServfailPercent)
+	if alerts.ServfailPercent > 0 {
+		servfailPercent = alerts.ServfailPercent
+	}
+	forwardLatencyMilliseconds := int32(defaultForwardLatencyMilliseconds)
+	if alerts.ForwardLatencyMilliseconds > 0 {
+		forwardLatencyMilliseconds = alerts.ForwardLatencyMilliseconds
+	}
+	cacheHitPercent := int32(defaultCacheHitPercent)
+	if alerts.CacheHitPercent > 0 {
+		cacheHitPercent = alerts.CacheHitPercent
+	}
+	rolloutMinutes := int32(defaultRolloutMinutes)
+	if alerts.RolloutMinutes > 0 {
+		rolloutMinutes = alerts.RolloutMinutes
+	}
+
+	// Prometheus sets the job label of scraped CoreDNS metrics to the name
+	// of the metrics service.
+	job := fmt.Sprintf("job=%q", DNSMetricsServiceName(namespace, dns).Name)
+	forwardLatencySeconds := strconv.FormatFloat(float64(forwardLatencyMilliseconds)/1000, 'f', -1, 64)
+
+	responses, panics := "coredns_dns_response_rcode_count_total", "coredns_panic_count_total"
+	if image.hasRenamedMetrics() {
+		responses, panics = "coredns_dns_responses_total", "coredns_panics_total"
+	}
+	forwardDuration := "coredns_forward_request_duration_seconds_bucket"
+	if image.usesProxy() {
+		forwardDuration = "coredns_proxy_request_duration_seconds_bucket"
+	}
+
+	rules := []alertRule{
+		{
+			alert: "DNSHighServfailRate",
+			expr: fmt.Sprintf(`sum(rate(%[3]s{%[1]s,rcode="SERVFAIL"}[5m])) / sum(rate(%[3]s{%[1]s}[5m])) * 100 > %[2]d`,
+				job, servfailPercent, responses),
+			duration: "10m",
+			severity: "warning",
+			message:  fmt.Sprintf("More than %d%% of the responses of dns %s are SERVFAIL.", servfailPercent, dns.Name),
+		},
+		{
+			alert: "DNSForwardLatencyHigh",
+			expr: fmt.Sprintf(`histogram_quantile(0.99, sum by (le) (rate(%[3]s{%[1]s}[5m]))) > %[2]s`,
+				job, forwardLatencySeconds, forwardDuration),
+			duration: "10m",
+			severity: "warning",
+			message:  fmt.Sprintf("The 99th percentile latency of queries that dns %s forwards to upstream resolvers is above %dms.", dns.Name, forwardLatencyMilliseconds),
+		},
+		{
+			alert:    "DNSPanics",
+			expr:     fmt.Sprintf(`increase(%s{%s}[10m]) > 0`, panics, job),
+			duration: "0m",
+			severity: "warning",
+			message:  fmt.Sprintf("CoreDNS of dns %s has panicked.", dns.Name),
+		},
+		{
+			alert: "DNSCacheHitRatioLow",
+			expr: fmt.Sprintf(`sum(rate(coredns_cache_hits_total{%[1]s}[5m])) / (sum(rate(coredns_cache_hits_total{%[1]s}[5m])) + sum(rate(coredns_cache_misses_total{%[1]s}[5m]))) * 100 < %[2]d`,
+				job, cacheHitPercent),
+			duration: "15m",
+			severity: "warning",
+			message:  fmt.Sprintf("Fewer than %d%% of the cache lookups of dns %s are hits.", cacheHitPercent, dns.Name),
+		},
+	}
+
+	// Rollout progress is exported by kube-state-metrics for the workload
+	// of the configured topology.
+	rollout := alertRule{
+		alert:    "DNSRolloutIncomplete",
+		duration: fmt.Sprintf("%dm", rolloutMinutes),
+		severity: "warning",
+	}
+	if dns.Spec.Topology.Mode == operatorv1.DeploymentDNSTopologyMode {
+		name := DNSDeploymentName(namespace, dns)
+		selector := fmt.Sprintf("namespace=%q,deployment=%q", name.Namespace, name.Name)
+		rollout.expr = fmt.Sprintf(`kube_deployment_spec_replicas{%[1]s} - kube_deployment_status_replicas_updated{%[1]s} > 0 or kube_deployment_spec_replicas{%[1]s} - kube_deployment_status_replicas_available{%[1]s} > 0`, selector)
+		rollout.message = fmt.Sprintf("Deployment %s of dns %s has not finished rolling out.", name, dns.Name)
+	} else {
+		name := DNSDaemonSetName(namespace, dns)
+		selector := fmt.Sprintf("namespace=%q,daemonset=%q", name.Namespace, name.Name)
+		rollout.expr = fmt.Sprintf(`kube_daemonset_status_desired_number_scheduled{%[1]s} - kube_daemonset_updated_number_scheduled{%[1]s} > 0 or kube_daemonset_status_desired_number_scheduled{%[1]s} - kube_daemonset_status_number_available{%[1]s} > 0`, selector)
+		rollout.message = fmt.Sprintf("DaemonSet %s of dns %s has not finished rolling out.", name, dns.Name)
+	}
+	return append(rules, rollout)
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// commonMetrics are the metrics that the Corefile's plugins and
+// kube-state-metrics export under the same names for every CoreDNS release
+// that the operator manages.  Histograms are listed by their base name.
+var commonMetrics = []string{
+	// CoreDNS core and the prometheus plugin.
+	"coredns_build_info",
+	"coredns_dns_request_duration_seconds",
+	"coredns_dns_request_size_bytes",
+	"coredns_dns_response_size_bytes",
+	// The cache plugin.
+	"coredns_cache_size",
+	"coredns_cache_hits_total",
+	"coredns_cache_misses_total",
+	"coredns_cache_prefetch_total",
+	"coredns_cache_drops_total",
+	// kube-state-metrics.
+	"kube_daemonset_status_desired_number_scheduled",
+	"kube_daemonset_status_number_available",
+	"kube_daemonset_status_number_ready",
+	"kube_daemonset_updated_number_scheduled",
+	"kube_deployment_spec_replicas",
+	"kube_deployment_status_replicas_available",
+	"kube_deployment_status_replicas_updated",
+}
+
+// exportedMetrics returns the metrics that alerts may reference for the
+// given CoreDNS image: the common metrics, the counters under the names of
+// its release, and the metrics of the plugin that it forwards with.
+func exportedMetrics(image coreDNSImage) map[string]bool {
+	metrics := map[string]bool{}
+	for _, name := range commonMetrics {
+		metrics[name] = true
+	}
+	var names []string
+	if image.hasRenamedMetrics() {
+		names = []string{"coredns_panics_total", "coredns_dns_requests_total", "coredns_dns_responses_total"}
+	} else {
+		names = []string{"coredns_panic_count_total", "coredns_dns_request_count_total", "coredns_dns_response_rcode_count_total"}
+	}
+	switch {
+	case image.usesProxy():
+		names = append(names, "coredns_proxy_request_duration_seconds")
+	case image.hasRenamedMetrics():
+		names = append(names, "coredns_forward_requests_total", "coredns_forward_request_duration_seconds", "coredns_forward_responses_total")
+	default:
+		names = append(names, "coredns_forward_request_count_total", "coredns_forward_request_duration_seconds", "coredns_forward_response_rcode_count_total")
+	}
+	for _, name := range names {
+		metrics[name] = true
+	}
+	return metrics
+}
+
+var metricNameRegexp = regexp.MustCompile(`\b(coredns|kube)_[a-z_]+\b`)
+
+// referencedMetrics returns the metric names in a rule expression, with
+// histogram suffixes of the given metrics removed.
+func referencedMetrics(expr string, metrics map[string]bool) []string {
+	names := []string{}
+	for _, name := range metricNameRegexp.FindAllString(expr, -1) {
+		for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+			if base := strings.TrimSuffix(name, suffix); base != name && metrics[base] {
+				name = base
+				break
+			}
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestDNSAlertRulesReferenceExportedMetrics(t *testing.T) {
+	for _, v := range []string{"1.3.1", "1.5.0", "1.7.1"} {
+		image := coreDNSImage{version: version.MustParse(v)}
+		metrics := exportedMetrics(image)
+		for _, mode := range []operatorv1.DNSTopologyMode{operatorv1.DaemonSetDNSTopologyMode, operatorv1.DeploymentDNSTopologyMode} {
+			dns := &operatorv1.DNS{
+				ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+				Spec: operatorv1.DNSSpec{
+					Topology: operatorv1.DNSTopology{Mode: mode},
+				},
+			}
+			alerts := map[string]bool{}
+			for _, rule := range dnsAlertRules("openshift-dns", dns, image) {
+				alerts[rule.alert] = true
+				names := referencedMetrics(rule.expr, metrics)
+				if len(names) == 0 {
+					t.Errorf("%s %s: %s references no metrics: %s", v, mode, rule.alert, rule.expr)
+				}
+				for _, name := range names {
+					if !metrics[name] {
+						t.Errorf("%s %s: %s references unknown metric %q: %s", v, mode, rule.alert, name, rule.expr)
+					}
+				}
+			}
+			for _, alert := range []string{"DNSHighServfailRate", "DNSForwardLatencyHigh", "DNSPanics", "DNSCacheHitRatioLow", "DNSRolloutIncomplete"} {
+				if !alerts[alert] {
+					t.Errorf("%s %s: expected alert %s", v, mode, alert)
+				}
+			}
+		}
+	}
+}
+
+func TestDNSAlertRulesThresholds(t *testing.T) {
+	testCases := []struct {
+		description string
+		alerts      operatorv1.DNSAlerts
+		expect      map[string]string
+	}{
+		{
+			description: "defaults",
+			expect: map[string]string{
+				"DNSHighServfailRate":   "> 5",
+				"DNSForwardLatencyHigh": "> 1",
+				"DNSCacheHitRatioLow":   "< 10",
+				"DNSRolloutIncomplete":  "30m",
+			},
+		},
+		{
+			description: "custom thresholds",
+			alerts: operatorv1.DNSAlerts{
+				ServfailPercent:            2,
+				ForwardLatencyMilliseconds: 250,
+				CacheHitPercent:            25,
+				RolloutMinutes:             45,
+			},
+			expect: map[string]string{
+				"DNSHighServfailRate":   "> 2",
+				"DNSForwardLatencyHigh": "> 0.25",
+				"DNSCacheHitRatioLow":   "< 25",
+				"DNSRolloutIncomplete":  "45m",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+			Spec:       operatorv1.DNSSpec{Alerts: tc.alerts},
+		}
+		for _, rule := range dnsAlertRules("openshift-dns", dns, defaultCoreDNSImage) {
+			expect, ok := tc.expect[rule.alert]
+			if !ok {
+				continue
+			}
+			if rule.alert == "DNSRolloutIncomplete" {
+				if rule.duration != expect {
+					t.Errorf("%s: expected %s to be for %s, got %s", tc.description, rule.alert, expect, rule.duration)
+				}
+				continue
+			}
+			if !strings.HasSuffix(rule.expr, " "+expect) {
+				t.Errorf("%s: expected %s expression to end with %q, got %s", tc.description, rule.alert, expect, rule.expr)
+			}
+		}
+	}
+}
+
+func TestDesiredDNSPrometheusRule(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+	}
+	pr, err := desiredDNSPrometheusRule("openshift-dns", dns, defaultCoreDNSImage)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.GroupVersionKind() != prometheusRuleGVK {
+		t.Errorf("expected kind %s, got %s", prometheusRuleGVK, pr.GroupVersionKind())
+	}
+	if expected := DNSPrometheusRuleName("openshift-dns", dns); pr.GetNamespace() != expected.Namespace || pr.GetName() != expected.Name {
+		t.Errorf("expected name %s, got %s/%s", expected, pr.GetNamespace(), pr.GetName())
+	}
+	groups, _, err := unstructured.NestedSlice(pr.Object, "spec", "groups")
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("expected a single rule group, got %v (err: %v)", groups, err)
+	}
+	rules, _, err := unstructured.NestedSlice(groups[0].(map[string]interface{}), "rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := len(dnsAlertRules("openshift-dns", dns, defaultCoreDNSImage)); len(rules) != expected {
+		t.Errorf("expected %d rules, got %d", expected, len(rules))
+	}
+	for _, rule := range rules {
+		if expr, _, _ := unstructured.NestedString(rule.(map[string]interface{}), "expr"); len(expr) == 0 {
+			t.Errorf("expected rule to have an expression: %v", rule)
+		}
+	}
+}
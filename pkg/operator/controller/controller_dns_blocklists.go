@@ -0,0 +1,304 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// defaultBlocklistConfigMapKey is the key of a blocklist's ConfigMap that
+// lists its domains if the blocklist does not specify one.
+const defaultBlocklistConfigMapKey = "domains"
+
+const (
+	// maxBlocklistDomains is the number of distinct domains that the
+	// blocklists of a dns may list in total.
+	maxBlocklistDomains = 10000
+
+	// maxBlocklistBytes is the size to which the blocklists of a dns may
+	// grow the Corefile.  Every server answers the blocklists, so they are
+	// rendered once for each server, and the Corefile must fit in a
+	// ConfigMap, which holds at most 1 MiB.  The rest of the Corefile
+	// takes the remaining space.
+	maxBlocklistBytes = 896 * 1024
+)
+
+// blocklistsTooLargeError is the error for blocklists that exceed
+// maxBlocklistDomains or maxBlocklistBytes.  It is reported as an invalid
+// spec rather than a failure to reconcile.
+type blocklistsTooLargeError struct {
+	message string
+}
+
+func (e *blocklistsTooLargeError) Error() string {
+	return e.message
+}
+
+// blocklistParameters are the values of a blocklist that are substituted
+// into the Corefile template.  Exactly one of NXDomain, IPv4 and IPv6 is
+// set.
+type blocklistParameters struct {
+	Name     string
+	Domains  []string
+	NXDomain bool
+	IPv4     string
+	IPv6     string
+}
+
+// validateDNSBlocklists returns an error describing every invalid blocklist
+// of the dns.  The domains of ConfigMaps are validated when they are read.
+func validateDNSBlocklists(dns *operatorv1.DNS, clusterDomain string) error {
+	var errs []error
+	names := map[string]bool{}
+	for i, blocklist := range dns.Spec.Blocklists {
+		field := fmt.Sprintf("spec.blocklists[%d]", i)
+		if len(blocklist.Name) == 0 {
+			errs = append(errs, fmt.Errorf("%s.name: must be specified", field))
+		} else if names[blocklist.Name] {
+			errs = append(errs, fmt.Errorf("%s.name: duplicate name %q", field, blocklist.Name))
+		}
+		names[blocklist.Name] = true
+
+		if len(blocklist.Domains) == 0 && blocklist.ConfigMap == nil {
+			errs = append(errs, fmt.Errorf("%s: one of domains and configMap must be specified", field))
+		}
+		for _, domain := range blocklist.Domains {
+			if err := validateBlockedDomain(domain, clusterDomain); err != nil {
+				errs = append(errs, fmt.Errorf("%s.domains: %v", field, err))
+			}
+		}
+		if cm := blocklist.ConfigMap; cm != nil {
+			if msgs := validation.IsDNS1123Subdomain(cm.Name); len(msgs) != 0 {
+				errs = append(errs, fmt.Errorf("%s.configMap.name: invalid name %q: %s", field, cm.Name, strings.Join(msgs, ", ")))
+			}
+			if len(cm.Key) != 0 {
+				if msgs := validation.IsConfigMapKey(cm.Key); len(msgs) != 0 {
+					errs = append(errs, fmt.Errorf("%s.configMap.key: invalid key %q: %s", field, cm.Key, strings.Join(msgs, ", ")))
+				}
+			}
+		}
+
+		switch blocklist.Action {
+		case "", operatorv1.NXDomainDNSBlocklistAction:
+			if len(blocklist.SinkholeIP) != 0 {
+				errs = append(errs, fmt.Errorf("%s.sinkholeIP: requires action %q", field, operatorv1.SinkholeDNSBlocklistAction))
+			}
+		case operatorv1.SinkholeDNSBlocklistAction:
+			if len(blocklist.SinkholeIP) == 0 {
+				errs = append(errs, fmt.Errorf("%s.sinkholeIP: must be specified for action %q", field, operatorv1.SinkholeDNSBlocklistAction))
+			} else if net.ParseIP(blocklist.SinkholeIP) == nil {
+				errs = append(errs, fmt.Errorf("%s.sinkholeIP: invalid IP address %q", field, blocklist.SinkholeIP))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s.action: unsupported value %q; must be %q or %q", field, blocklist.Action, operatorv1.NXDomainDNSBlocklistAction, operatorv1.SinkholeDNSBlocklistAction))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateBlockedDomain returns an error if the domain is not a valid
+// domain or is in the cluster domain, whose names must not be blocked.
+func validateBlockedDomain(domain, clusterDomain string) error {
+	if msgs := validation.IsDNS1123Subdomain(domain); len(msgs) != 0 {
+		return fmt.Errorf("invalid domain %q: %s", domain, strings.Join(msgs, ", "))
+	}
+	if domain == clusterDomain || strings.HasSuffix(domain, "."+clusterDomain) {
+		return fmt.Errorf("domain %q must not be in the cluster domain %s", domain, clusterDomain)
+	}
+	return nil
+}
+
+// blocklistConfigMapNames returns the names of the ConfigMaps that the
+// blocklists of the dns reference.
+func blocklistConfigMapNames(dns *operatorv1.DNS) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, blocklist := range dns.Spec.Blocklists {
+		if blocklist.ConfigMap != nil && !seen[blocklist.ConfigMap.Name] {
+			names = append(names, blocklist.ConfigMap.Name)
+			seen[blocklist.ConfigMap.Name] = true
+		}
+	}
+	return names
+}
+
+// currentBlocklistConfigMaps returns the ConfigMaps that the blocklists of
+// the dns reference, by name.  ConfigMaps that do not exist are left out.
+func (r *reconciler) currentBlocklistConfigMaps(dns *operatorv1.DNS) (map[string]*corev1.ConfigMap, error) {
+	configMaps := map[string]*corev1.ConfigMap{}
+	for _, name := range blocklistConfigMapNames(dns) {
+		cm := &corev1.ConfigMap{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: r.OperandNamespace, Name: name}, cm); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get blocklist configmap %s: %v", name, err)
+		}
+		configMaps[name] = cm
+	}
+	return configMaps, nil
+}
+
+// currentDNSBlocklists returns the blocklists of the dns with the domains
+// of their current ConfigMaps.
+func (r *reconciler) currentDNSBlocklists(dns *operatorv1.DNS, clusterDomain string) ([]blocklistParameters, error) {
+	configMaps, err := r.currentBlocklistConfigMaps(dns)
+	if err != nil {
+		return nil, err
+	}
+	return dnsBlocklistParameters(dns, clusterDomain, configMaps)
+}
+
+// dnsBlocklistParameters returns the blocklists of the dns with the domains
+// of their ConfigMaps added.  It returns an error if a ConfigMap does not
+// exist, lacks the blocklist's key, or lists an invalid domain, and a
+// blocklistsTooLargeError if the blocklists exceed the size that the
+// Corefile can hold.
+func dnsBlocklistParameters(dns *operatorv1.DNS, clusterDomain string, configMaps map[string]*corev1.ConfigMap) ([]blocklistParameters, error) {
+	var blocklists []blocklistParameters
+	domains, size := 0, 0
+	for _, blocklist := range dns.Spec.Blocklists {
+		params := blocklistParameters{Name: blocklist.Name}
+		seen := map[string]bool{}
+		add := func(domain string) {
+			domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+			if !seen[domain] {
+				params.Domains = append(params.Domains, domain)
+				seen[domain] = true
+			}
+		}
+		for _, domain := range blocklist.Domains {
+			add(domain)
+		}
+		if ref := blocklist.ConfigMap; ref != nil {
+			domains, err := blocklistConfigMapDomains(ref, clusterDomain, configMaps)
+			if err != nil {
+				return nil, fmt.Errorf("blocklist %s: %v", blocklist.Name, err)
+			}
+			for _, domain := range domains {
+				add(domain)
+			}
+		}
+
+		switch ip := net.ParseIP(blocklist.SinkholeIP); {
+		case blocklist.Action != operatorv1.SinkholeDNSBlocklistAction:
+			params.NXDomain = true
+		case ip == nil:
+			return nil, fmt.Errorf("blocklist %s: invalid sinkhole IP %q", blocklist.Name, blocklist.SinkholeIP)
+		case ip.To4() != nil:
+			params.IPv4 = ip.String()
+		default:
+			params.IPv6 = ip.String()
+		}
+		// A sinkhole blocklist is rendered into two templates.
+		renderings := 1
+		if !params.NXDomain {
+			renderings = 2
+		}
+		for _, domain := range params.Domains {
+			size += renderings * (len(domain) + 1)
+		}
+		domains += len(params.Domains)
+		blocklists = append(blocklists, params)
+	}
+	if domains > maxBlocklistDomains {
+		return nil, &blocklistsTooLargeError{fmt.Sprintf("blocklists list %d domains; at most %d are supported", domains, maxBlocklistDomains)}
+	}
+	// Every server is rendered once for all clients and once for each
+	// rate limit rule.
+	if servers := (len(dns.Spec.Servers) + 1) * (len(dns.Spec.RateLimit.Rules) + 1); servers*size > maxBlocklistBytes {
+		return nil, &blocklistsTooLargeError{fmt.Sprintf("blocklists take %d bytes in each of %d servers of the Corefile; at most %d bytes fit in its ConfigMap", size, servers, maxBlocklistBytes)}
+	}
+	return blocklists, nil
+}
+
+// blocklistConfigMapDomains returns the domains that the referenced
+// ConfigMap lists, one per line.  Blank lines and comments are skipped.
+func blocklistConfigMapDomains(ref *operatorv1.DNSBlocklistConfigMap, clusterDomain string, configMaps map[string]*corev1.ConfigMap) ([]string, error) {
+	cm, ok := configMaps[ref.Name]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s not found", ref.Name)
+	}
+	key := ref.Key
+	if len(key) == 0 {
+		key = defaultBlocklistConfigMapKey
+	}
+	data, ok := cm.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s has no key %q", ref.Name, key)
+	}
+	var domains []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domain := strings.TrimSuffix(strings.ToLower(line), ".")
+		if err := validateBlockedDomain(domain, clusterDomain); err != nil {
+			return nil, fmt.Errorf("configmap %s key %q line %d: %v", ref.Name, key, n, err)
+		}
+		domains = append(domains, domain)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read configmap %s key %q: %v", ref.Name, key, err)
+	}
+	return domains, nil
+}
+
+// dnsBlocklistStatuses returns the status of each of the given blocklists.
+func dnsBlocklistStatuses(blocklists []blocklistParameters) []operatorv1.DNSBlocklistStatus {
+	var statuses []operatorv1.DNSBlocklistStatus
+	for _, blocklist := range blocklists {
+		statuses = append(statuses, operatorv1.DNSBlocklistStatus{
+			Name:    blocklist.Name,
+			Entries: int32(len(blocklist.Domains)),
+		})
+	}
+	return statuses
+}
+
+// blocklistConfigMapPredicate filters out events for ConfigMaps that are
+// owned by a dns, which are watched through their owner, and updates that
+// do not change the data of a ConfigMap.  The ConfigMaps that remain are the
+// candidates for blocklists in the operand namespace.
+func blocklistConfigMapPredicate() predicate.Predicate {
+	ownedByDNS := func(cm *corev1.ConfigMap) bool {
+		for _, ref := range cm.OwnerReferences {
+			if ref.Kind == "DNS" {
+				return true
+			}
+		}
+		return false
+	}
+	filter := func(obj interface{}) bool {
+		cm, ok := obj.(*corev1.ConfigMap)
+		return ok && !ownedByDNS(cm)
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return filter(e.Object) },
+		DeleteFunc: func(e event.DeleteEvent) bool { return filter(e.Object) },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, ok := e.ObjectOld.(*corev1.ConfigMap)
+			if !ok || !filter(e.ObjectNew) {
+				return false
+			}
+			return !reflect.DeepEqual(old.Data, e.ObjectNew.(*corev1.ConfigMap).Data)
+		},
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,334 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestDesiredDNSConfigMapBlocklists(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+		Spec: operatorv1.DNSSpec{
+			Blocklists: []operatorv1.DNSBlocklist{
+				{
+					Name:      "malware",
+					Domains:   []string{"malware.example", "Phishing.test."},
+					ConfigMap: &operatorv1.DNSBlocklistConfigMap{Name: "threat-feed"},
+				},
+				{
+					Name:       "ads",
+					Domains:    []string{"ads.example"},
+					Action:     operatorv1.SinkholeDNSBlocklistAction,
+					SinkholeIP: "0.0.0.0",
+				},
+				{
+					Name:       "tracking",
+					Domains:    []string{"tracker.example"},
+					Action:     operatorv1.SinkholeDNSBlocklistAction,
+					SinkholeIP: "::",
+				},
+				{
+					Name:      "empty",
+					ConfigMap: &operatorv1.DNSBlocklistConfigMap{Name: "empty-feed", Key: "list"},
+				},
+			},
+		},
+	}
+	configMaps := map[string]*corev1.ConfigMap{
+		"threat-feed": {Data: map[string]string{"domains": "# updated daily\nbotnet.example\n\nmalware.example\n"}},
+		"empty-feed":  {Data: map[string]string{"list": "# nothing yet\n"}},
+	}
+	expected := `
+    errors
+    # blocklist malware
+    template ANY ANY malware.example phishing.test botnet.example {
+        rcode NXDOMAIN
+    }
+    # blocklist ads
+    template IN A ads.example {
+        answer "{{ .Name }} 60 IN A 0.0.0.0"
+    }
+    template ANY ANY ads.example {
+        rcode NOERROR
+    }
+    # blocklist tracking
+    template IN AAAA tracker.example {
+        answer "{{ .Name }} 60 IN AAAA ::"
+    }
+    template ANY ANY tracker.example {
+        rcode NOERROR
+    }
+    health {
+`
+	blocklists, err := dnsBlocklistParameters(dns, "cluster.local", configMaps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cm, err := desiredDNSConfigMap("openshift-dns", dns, defaultCoreDNSImage, "cluster.local", nil, blocklists, metav1.OwnerReference{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if corefile := cm.Data["Corefile"]; !strings.HasPrefix(corefile, ".:5353 {"+expected) {
+		t.Errorf("expected Corefile to start with:\n%s\ngot:\n%s", expected, corefile)
+	}
+
+	statuses := dnsBlocklistStatuses(blocklists)
+	expectedStatuses := []operatorv1.DNSBlocklistStatus{
+		{Name: "malware", Entries: 3},
+		{Name: "ads", Entries: 1},
+		{Name: "tracking", Entries: 1},
+		{Name: "empty", Entries: 0},
+	}
+	if len(statuses) != len(expectedStatuses) {
+		t.Fatalf("expected statuses %v, got %v", expectedStatuses, statuses)
+	}
+	for i := range statuses {
+		if statuses[i] != expectedStatuses[i] {
+			t.Errorf("expected status %v, got %v", expectedStatuses[i], statuses[i])
+		}
+	}
+}
+
+func TestDNSBlocklistParametersConfigMapErrors(t *testing.T) {
+	ref := &operatorv1.DNSBlocklistConfigMap{Name: "threat-feed"}
+	testCases := []struct {
+		description string
+		configMaps  map[string]*corev1.ConfigMap
+		expectErr   string
+	}{
+		{
+			description: "missing configmap",
+			expectErr:   "blocklist malware: configmap threat-feed not found",
+		},
+		{
+			description: "missing key",
+			configMaps: map[string]*corev1.ConfigMap{
+				"threat-feed": {Data: map[string]string{"list": "botnet.example"}},
+			},
+			expectErr: `blocklist malware: configmap threat-feed has no key "domains"`,
+		},
+		{
+			description: "invalid domain",
+			configMaps: map[string]*corev1.ConfigMap{
+				"threat-feed": {Data: map[string]string{"domains": "botnet.example\n0.0.0.0 bad_domain.example\n"}},
+			},
+			expectErr: `blocklist malware: configmap threat-feed key "domains" line 2: invalid domain`,
+		},
+		{
+			description: "cluster domain",
+			configMaps: map[string]*corev1.ConfigMap{
+				"threat-feed": {Data: map[string]string{"domains": "kubernetes.default.svc.cluster.local"}},
+			},
+			expectErr: `line 1: domain "kubernetes.default.svc.cluster.local" must not be in the cluster domain cluster.local`,
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{
+			Blocklists: []operatorv1.DNSBlocklist{{Name: "malware", ConfigMap: ref}},
+		}}
+		_, err := dnsBlocklistParameters(dns, "cluster.local", tc.configMaps)
+		if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.description, tc.expectErr, err)
+		}
+	}
+}
+
+func TestValidateDNSBlocklists(t *testing.T) {
+	testCases := []struct {
+		description string
+		blocklists  []operatorv1.DNSBlocklist
+		expectErrs  []string
+	}{
+		{
+			description: "valid",
+			blocklists: []operatorv1.DNSBlocklist{
+				{Name: "malware", Domains: []string{"malware.example"}},
+				{
+					Name:       "ads",
+					ConfigMap:  &operatorv1.DNSBlocklistConfigMap{Name: "ad-servers", Key: "hosts.txt"},
+					Action:     operatorv1.SinkholeDNSBlocklistAction,
+					SinkholeIP: "fd00::1",
+				},
+			},
+		},
+		{
+			description: "empty",
+			blocklists:  []operatorv1.DNSBlocklist{{}},
+			expectErrs: []string{
+				"spec.blocklists[0].name: must be specified",
+				"spec.blocklists[0]: one of domains and configMap must be specified",
+			},
+		},
+		{
+			description: "invalid fields",
+			blocklists: []operatorv1.DNSBlocklist{
+				{Name: "malware", Domains: []string{"malware.example"}},
+				{
+					Name:       "malware",
+					Domains:    []string{"Bad_Domain", "svc.cluster.local"},
+					ConfigMap:  &operatorv1.DNSBlocklistConfigMap{Name: "Threat_Feed", Key: "a/b"},
+					SinkholeIP: "0.0.0.0",
+				},
+			},
+			expectErrs: []string{
+				`spec.blocklists[1].name: duplicate name "malware"`,
+				`spec.blocklists[1].domains: invalid domain "Bad_Domain"`,
+				`spec.blocklists[1].domains: domain "svc.cluster.local" must not be in the cluster domain cluster.local`,
+				`spec.blocklists[1].configMap.name: invalid name "Threat_Feed"`,
+				`spec.blocklists[1].configMap.key: invalid key "a/b"`,
+				`spec.blocklists[1].sinkholeIP: requires action "Sinkhole"`,
+			},
+		},
+		{
+			description: "invalid sinkholes",
+			blocklists: []operatorv1.DNSBlocklist{
+				{Name: "a", Domains: []string{"a.example"}, Action: operatorv1.SinkholeDNSBlocklistAction},
+				{Name: "b", Domains: []string{"b.example"}, Action: operatorv1.SinkholeDNSBlocklistAction, SinkholeIP: "sinkhole.example"},
+				{Name: "c", Domains: []string{"c.example"}, Action: "Refuse"},
+			},
+			expectErrs: []string{
+				`spec.blocklists[0].sinkholeIP: must be specified for action "Sinkhole"`,
+				`spec.blocklists[1].sinkholeIP: invalid IP address "sinkhole.example"`,
+				`spec.blocklists[2].action: unsupported value "Refuse"`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{Blocklists: tc.blocklists}}
+		err := validateDNSBlocklists(dns, "cluster.local")
+		if len(tc.expectErrs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.description, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected errors %q, got none", tc.description, tc.expectErrs)
+			continue
+		}
+		for _, expect := range tc.expectErrs {
+			if !strings.Contains(err.Error(), expect) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.description, expect, err)
+			}
+		}
+	}
+}
+
+func TestBlocklistConfigMapPredicate(t *testing.T) {
+	pred := blocklistConfigMapPredicate()
+	feed := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-dns", Name: "threat-feed"},
+		Data:       map[string]string{"domains": "botnet.example"},
+	}
+	owned := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "openshift-dns",
+			Name:            "dns-default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "DNS", Name: DefaultDNSController}},
+		},
+	}
+	if !pred.Create(event.CreateEvent{Meta: feed, Object: feed}) {
+		t.Errorf("expected the creation of an unowned configmap to pass")
+	}
+	if pred.Create(event.CreateEvent{Meta: owned, Object: owned}) {
+		t.Errorf("expected the creation of a configmap owned by a dns not to pass")
+	}
+	testCases := []struct {
+		description string
+		mutate      func(*corev1.ConfigMap)
+		expect      bool
+	}{
+		{"labels", func(cm *corev1.ConfigMap) { cm.Labels = map[string]string{"team": "security"} }, false},
+		{"data", func(cm *corev1.ConfigMap) { cm.Data["domains"] += "\nmalware.example" }, true},
+		{"added key", func(cm *corev1.ConfigMap) { cm.Data["other"] = "" }, true},
+	}
+	for _, tc := range testCases {
+		new := feed.DeepCopy()
+		tc.mutate(new)
+		if actual := pred.Update(event.UpdateEvent{MetaOld: feed, ObjectOld: feed, MetaNew: new, ObjectNew: new}); actual != tc.expect {
+			t.Errorf("%s: expected update to return %t, got %t", tc.description, tc.expect, actual)
+		}
+	}
+}
+
+func TestDNSBlocklistParametersTooLarge(t *testing.T) {
+	feed := func(n int, format string) map[string]*corev1.ConfigMap {
+		var domains strings.Builder
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&domains, format+"\n", i)
+		}
+		return map[string]*corev1.ConfigMap{
+			"threat-feed": {Data: map[string]string{"domains": domains.String()}},
+		}
+	}
+	long := strings.Repeat("a", 60) + ".%d." + strings.Repeat("b", 60) + ".example"
+	testCases := []struct {
+		description string
+		servers     int
+		rules       int
+		configMaps  map[string]*corev1.ConfigMap
+		expectErr   string
+	}{
+		{
+			description: "at the domain limit",
+			configMaps:  feed(maxBlocklistDomains, "d%d.example"),
+		},
+		{
+			description: "over the domain limit",
+			configMaps:  feed(maxBlocklistDomains+1, "d%d.example"),
+			expectErr:   "blocklists list 10001 domains; at most 10000 are supported",
+		},
+		{
+			description: "long domains",
+			configMaps:  feed(maxBlocklistDomains, long),
+			expectErr:   "in each of 1 servers of the Corefile",
+		},
+		{
+			description: "rendered in every server",
+			servers:     6,
+			configMaps:  feed(maxBlocklistDomains, "d%d.example"),
+			expectErr:   "in each of 7 servers of the Corefile",
+		},
+		{
+			description: "rendered for every rate limit rule",
+			servers:     1,
+			rules:       3,
+			configMaps:  feed(maxBlocklistDomains, "d%d.example"),
+			expectErr:   "in each of 8 servers of the Corefile",
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{
+			Blocklists: []operatorv1.DNSBlocklist{{Name: "malware", ConfigMap: &operatorv1.DNSBlocklistConfigMap{Name: "threat-feed"}}},
+		}}
+		for i := 0; i < tc.servers; i++ {
+			dns.Spec.Servers = append(dns.Spec.Servers, operatorv1.DNSServer{Name: fmt.Sprintf("server-%d", i)})
+		}
+		for i := 0; i < tc.rules; i++ {
+			dns.Spec.RateLimit.Rules = append(dns.Spec.RateLimit.Rules, operatorv1.DNSRateLimitRule{SourceCIDR: fmt.Sprintf("10.%d.0.0/16", i), QueriesPerSecond: 100})
+		}
+		blocklists, err := dnsBlocklistParameters(dns, "cluster.local", tc.configMaps)
+		switch {
+		case len(tc.expectErr) == 0 && err != nil:
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+		case len(tc.expectErr) == 0:
+			if _, err := desiredDNSConfigMap("openshift-dns", dns, defaultCoreDNSImage, "cluster.local", nil, blocklists, metav1.OwnerReference{}); err != nil {
+				t.Errorf("%s: unexpected error building configmap: %v", tc.description, err)
+			}
+		case err == nil || !strings.Contains(err.Error(), tc.expectErr):
+			t.Errorf("%s: expected error containing %q, got %v", tc.description, tc.expectErr, err)
+		default:
+			if _, ok := err.(*blocklistsTooLargeError); !ok {
+				t.Errorf("%s: expected a blocklistsTooLargeError, got %T", tc.description, err)
+			}
+		}
+	}
+}
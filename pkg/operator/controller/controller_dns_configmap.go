@@ -1,42 +1,98 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"strings"
+	"text/template"
+	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/cluster-dns-operator/pkg/manifests"
 
 	corev1 "k8s.io/api/core/v1"
 
-	"github.com/sirupsen/logrus"
-
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultLameDuckDuration is how long CoreDNS keeps serving after it
+	// receives SIGTERM when the dns does not specify a duration.
+	defaultLameDuckDuration = 20 * time.Second
 )
 
-// ensureDNSConfigMap ensures that a configmap exists for a given DNS.
-func (r *reconciler) ensureDNSConfigMap(dns *operatorv1.DNS, clusterDomain string, daemonsetRef metav1.OwnerReference) (*corev1.ConfigMap, error) {
+// corefileParameters are the values that are substituted into the Corefile
+// templates from the configmap assets.
+type corefileParameters struct {
+	ClusterDomain    string
+	LameDuckDuration time.Duration
+	PodsMode         string
+	Kubernetes       kubernetesParameters
+	Servers          []serverParameters
+	Blocklists       []blocklistParameters
+	Rewrites         []rewriteParameters
+	RateLimit        *rateLimitParameters
+	ACL              []aclParameters
+
+	// View, if it is set, restricts the servers to the clients of a
+	// subnet.  Views are copies of the parameters, each with its view and
+	// the rate limit of a rule of the dns, whose servers are rendered
+	// before the servers for all clients.
+	View  *viewParameters
+	Views []corefileParameters
+
+	// Ready is true if the CoreDNS image has the ready plugin, which the
+	// readiness probe of the dns daemonset then uses.
+	Ready bool
+
+	// Proxy is true if the CoreDNS image forwards with the proxy plugin
+	// and needs the upstream option of the kubernetes plugin to resolve
+	// external names.
+	Proxy bool
+
+	// ClusterIP, UpstreamIP and LocalAddress are used by the node-local
+	// cache, which binds the cluster IP and the local address and forwards
+	// to CoreDNS through the upstream IP.
+	ClusterIP    string
+	UpstreamIP   string
+	LocalAddress string
+}
+
+// ensureDNSConfigMap ensures that a configmap exists for a given DNS, with
+// the given blocklists rendered into its Corefile.
+func (r *reconciler) ensureDNSConfigMap(dns *operatorv1.DNS, clusterDomain string, blocklists []blocklistParameters, workloadRef metav1.OwnerReference) (*corev1.ConfigMap, error) {
+	upstreamServices, err := r.currentUpstreamServices(dns)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := desiredDNSConfigMap(r.OperandNamespace, dns, r.coreDNSImage(), clusterDomain, upstreamServices, blocklists, workloadRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dns configmap: %v", err)
+	}
 	current, err := r.currentDNSConfigMap(dns)
 	if err != nil {
 		return nil, err
 	}
-	if current != nil {
-		return current, nil
+	if current == nil {
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return nil, fmt.Errorf("failed to create dns configmap: %v", err)
+		}
+		r.logObject(desired).Info("created dns configmap")
+		r.recordCreated(dns, desired)
+		return desired, nil
 	}
-
-	desired := desiredDNSConfigMap(dns, clusterDomain, daemonsetRef)
-	if err := r.client.Create(context.TODO(), desired); err != nil {
-		return nil, fmt.Errorf("failed to create dns configmap: %v", err)
+	if err := r.updateDNSConfigMap(dns, current, desired); err != nil {
+		return nil, err
 	}
-	logrus.Infof("created dns configmap: %s/%s", desired.Namespace, desired.Name)
-	return desired, nil
+	return r.currentDNSConfigMap(dns)
 }
 
 func (r *reconciler) currentDNSConfigMap(dns *operatorv1.DNS) (*corev1.ConfigMap, error) {
 	current := &corev1.ConfigMap{}
-	err := r.client.Get(context.TODO(), DNSConfigMapName(dns), current)
+	err := r.client.Get(context.TODO(), DNSConfigMapName(r.OperandNamespace, dns), current)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return nil, nil
@@ -46,10 +102,42 @@ func (r *reconciler) currentDNSConfigMap(dns *operatorv1.DNS) (*corev1.ConfigMap
 	return current, nil
 }
 
-func desiredDNSConfigMap(dns *operatorv1.DNS, clusterDomain string, daemonsetRef metav1.OwnerReference) *corev1.ConfigMap {
+// updateDNSConfigMap updates a dns configmap if its Corefile has drifted
+// from the desired one.
+func (r *reconciler) updateDNSConfigMap(dns *operatorv1.DNS, current, desired *corev1.ConfigMap) error {
+	changed, updated := corefileChanged(current, desired)
+	if !changed {
+		return nil
+	}
+
+	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(dns, updated, err)
+		return fmt.Errorf("failed to update dns configmap %s/%s: %v", updated.Namespace, updated.Name, err)
+	}
+	recordDriftCorrection("configmap")
+	r.logObject(updated).Info("updated dns configmap")
+	r.recordUpdated(dns, updated)
+	return nil
+}
+
+// corefileChanged checks if the current Corefile matches the expected
+// Corefile and if not returns the updated configmap.
+func corefileChanged(current, expected *corev1.ConfigMap) (bool, *corev1.ConfigMap) {
+	if current.Data["Corefile"] == expected.Data["Corefile"] {
+		return false, nil
+	}
+	updated := current.DeepCopy()
+	if updated.Data == nil {
+		updated.Data = map[string]string{}
+	}
+	updated.Data["Corefile"] = expected.Data["Corefile"]
+	return true, updated
+}
+
+func desiredDNSConfigMap(namespace string, dns *operatorv1.DNS, image coreDNSImage, clusterDomain string, upstreamServices map[types.NamespacedName]*corev1.Service, blocklists []blocklistParameters, workloadRef metav1.OwnerReference) (*corev1.ConfigMap, error) {
 	cm := manifests.DNSConfigMap()
 
-	name := DNSConfigMapName(dns)
+	name := DNSConfigMapName(namespace, dns)
 	cm.Namespace = name.Namespace
 	cm.Name = name.Name
 	cm.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
@@ -58,8 +146,65 @@ func desiredDNSConfigMap(dns *operatorv1.DNS, clusterDomain string, daemonsetRef
 		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
 	}
 
-	if len(clusterDomain) > 0 {
-		cm.Data["Corefile"] = strings.Replace(cm.Data["Corefile"], "cluster.local", clusterDomain, -1)
+	lameDuckDuration, err := dnsLameDuckDuration(dns)
+	if err != nil {
+		return nil, err
+	}
+	podsMode, err := dnsPodsMode(dns)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := dnsServerParameters(dns, upstreamServices)
+	if err != nil {
+		return nil, err
+	}
+	rewrites, serverRewrites := dnsRewriteParameters(dns)
+	for i := range servers {
+		servers[i].Rewrites = serverRewrites[servers[i].Name]
+	}
+	params := corefileParameters{
+		ClusterDomain:    clusterDomain,
+		LameDuckDuration: lameDuckDuration,
+		PodsMode:         podsMode,
+		Kubernetes:       dnsKubernetesParameters(dns),
+		Servers:          servers,
+		Blocklists:       blocklists,
+		Rewrites:         rewrites,
+		RateLimit:        dnsRateLimitParameters(dns),
+		ACL:              dnsACLParameters(dns),
+		Ready:            image.supports("ready"),
+		Proxy:            image.usesProxy(),
+	}
+	params.Views = dnsRateLimitViews(dns, params)
+	corefile, err := renderCorefile(cm.Data["Corefile"], params)
+	if err != nil {
+		return nil, err
+	}
+	cm.Data["Corefile"] = corefile
+	return cm, nil
+}
+
+// renderCorefile executes the given Corefile template with params.
+func renderCorefile(corefileTemplate string, params corefileParameters) (string, error) {
+	tmpl, err := template.New("Corefile").Parse(corefileTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse Corefile template: %v", err)
+	}
+	corefile := &bytes.Buffer{}
+	if err := tmpl.Execute(corefile, params); err != nil {
+		return "", fmt.Errorf("failed to render Corefile: %v", err)
+	}
+	return corefile.String(), nil
+}
+
+// dnsLameDuckDuration returns the lameduck duration for the given dns,
+// falling back to defaultLameDuckDuration if the dns does not specify one.
+func dnsLameDuckDuration(dns *operatorv1.DNS) (time.Duration, error) {
+	if dns.Spec.LameDuckDuration == nil {
+		return defaultLameDuckDuration, nil
+	}
+	if d := dns.Spec.LameDuckDuration.Duration; d < 0 {
+		return 0, fmt.Errorf("invalid lameDuckDuration %v: must not be negative", d)
 	}
-	return cm
+	return dns.Spec.LameDuckDuration.Duration, nil
 }
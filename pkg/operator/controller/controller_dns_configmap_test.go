@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorconfig "github.com/openshift/cluster-dns-operator/pkg/operator/config"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultCoreDNSImage is a CoreDNS image of the release that the operator
+// manages by default.
+var defaultCoreDNSImage = coreDNSImage{
+	name:    "coredns:latest",
+	version: version.MustParse(operatorconfig.DefaultCoreDNSVersion),
+}
+
+func TestDesiredDNSConfigMap(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	clusterDomain := "cluster.local"
+	testCases := []struct {
+		version  string
+		expected string
+	}{
+		{
+			version: "1.3.1",
+			expected: `.:5353 {
+    errors
+    health {
+        lameduck 20s
+    }
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+        pods insecure
+        upstream
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    prometheus :9153
+    proxy . /etc/resolv.conf
+    cache 30
+    reload
+}
+`,
+		},
+		{
+			version: "1.5.0",
+			expected: `.:5353 {
+    errors
+    health {
+        lameduck 20s
+    }
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf
+    cache 30
+    reload
+}
+`,
+		},
+		{
+			version: operatorconfig.DefaultCoreDNSVersion,
+			expected: `.:5353 {
+    errors
+    health {
+        lameduck 20s
+    }
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf
+    cache 30
+    reload
+}
+`,
+		},
+	}
+	for _, tc := range testCases {
+		image := coreDNSImage{version: version.MustParse(tc.version)}
+		cm, err := desiredDNSConfigMap("openshift-dns", dns, image, clusterDomain, nil, nil, metav1.OwnerReference{})
+		if err != nil {
+			t.Fatalf("%s: invalid dns configmap: %v", tc.version, err)
+		}
+		if cm.Data["Corefile"] != tc.expected {
+			t.Errorf("%s: unexpected Corefile; expected:\n%s\ngot:\n%s", tc.version, tc.expected, cm.Data["Corefile"])
+		}
+	}
+}
+
+func TestDesiredDNSConfigMapLameDuck(t *testing.T) {
+	testCases := []struct {
+		description string
+		lameDuck    *metav1.Duration
+		expected    string
+	}{
+		{"custom", &metav1.Duration{Duration: 90 * time.Second}, "    health {\n        lameduck 1m30s\n    }\n"},
+		{"disabled", &metav1.Duration{Duration: 0}, "    health\n    ready\n"},
+	}
+
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: DefaultDNSController,
+			},
+			Spec: operatorv1.DNSSpec{
+				LameDuckDuration: tc.lameDuck,
+			},
+		}
+		cm, err := desiredDNSConfigMap("openshift-dns", dns, defaultCoreDNSImage, "cluster.local", nil, nil, metav1.OwnerReference{})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if !strings.Contains(cm.Data["Corefile"], tc.expected) {
+			t.Errorf("%q: expected Corefile to contain:\n%s\ngot:\n%s", tc.description, tc.expected, cm.Data["Corefile"])
+		}
+	}
+}
+
+func TestCorefileChanged(t *testing.T) {
+	current := &corev1.ConfigMap{Data: map[string]string{"Corefile": "old"}}
+	expected := &corev1.ConfigMap{Data: map[string]string{"Corefile": "new"}}
+
+	if changed, _ := corefileChanged(expected, expected); changed {
+		t.Errorf("expected identical configmaps to be unchanged")
+	}
+	changed, updated := corefileChanged(current, expected)
+	if !changed {
+		t.Fatalf("expected differing Corefiles to be changed")
+	}
+	if e, a := "new", updated.Data["Corefile"]; e != a {
+		t.Errorf("expected updated Corefile %q, got %q", e, a)
+	}
+	if e, a := "old", current.Data["Corefile"]; e != a {
+		t.Errorf("expected current configmap to be left untouched, got Corefile %q", a)
+	}
+}
@@ -3,22 +3,34 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/cluster-dns-operator/pkg/manifests"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/sirupsen/logrus"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// terminationGracePeriodPadding is added to the lameduck duration to
+	// give CoreDNS time to finish shutting down once lameduck has elapsed.
+	terminationGracePeriodPadding = 10 * time.Second
 )
 
 // ensureDNSDaemonSet ensures the dns daemonset exists for a given dns.
 func (r *reconciler) ensureDNSDaemonSet(dns *operatorv1.DNS, clusterIP, clusterDomain string) (*appsv1.DaemonSet, error) {
-	desired, err := desiredDNSDaemonSet(dns, clusterIP, clusterDomain, r.CoreDNSImage, r.OpenshiftCLIImage)
+	desired, err := desiredDNSDaemonSet(r.OperandNamespace, dns, clusterIP, clusterDomain, r.coreDNSImage(), r.OpenshiftCLIImage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build dns daemonset: %v", err)
 	}
@@ -26,13 +38,20 @@ func (r *reconciler) ensureDNSDaemonSet(dns *operatorv1.DNS, clusterIP, clusterD
 	if err != nil {
 		return nil, err
 	}
+	var currentSpec *corev1.PodSpec
+	if current != nil {
+		currentSpec = &current.Spec.Template.Spec
+	}
+	if err := r.applyDNSSizing(dns, currentSpec, &desired.Spec.Template.Spec); err != nil {
+		return nil, fmt.Errorf("failed to size dns daemonset: %v", err)
+	}
 	switch {
 	case desired != nil && current == nil:
-		if err := r.createDNSDaemonSet(desired); err != nil {
+		if err := r.createDNSDaemonSet(dns, desired); err != nil {
 			return nil, err
 		}
 	case desired != nil && current != nil:
-		if err := r.updateDNSDaemonSet(current, desired); err != nil {
+		if err := r.updateDNSDaemonSet(dns, current, desired); err != nil {
 			return nil, err
 		}
 	}
@@ -43,23 +62,27 @@ func (r *reconciler) ensureDNSDaemonSet(dns *operatorv1.DNS, clusterIP, clusterD
 // associated with the dns.
 func (r *reconciler) ensureDNSDaemonSetDeleted(dns *operatorv1.DNS) error {
 	daemonset := &appsv1.DaemonSet{}
-	name := DNSDaemonSetName(dns)
+	name := DNSDaemonSetName(r.OperandNamespace, dns)
 	daemonset.Name = name.Name
 	daemonset.Namespace = name.Namespace
 	if err := r.client.Delete(context.TODO(), daemonset); err != nil {
 		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, daemonset, err)
 			return err
 		}
 	} else {
-		logrus.Infof("deleted dns daemonset: %s", dns.Name)
+		r.logObject(daemonset).Info("deleted dns daemonset")
+		r.recordDeleted(dns, daemonset)
 	}
 	return nil
 }
 
-// desiredDNSDaemonSet returns the desired dns daemonset.
-func desiredDNSDaemonSet(dns *operatorv1.DNS, clusterIP, clusterDomain, coreDNSImage, openshiftCLIImage string) (*appsv1.DaemonSet, error) {
+// desiredDNSDaemonSet returns the desired dns daemonset.  The readiness
+// probe of CoreDNS uses the ready plugin if the CoreDNS image has it, and
+// the health plugin otherwise.
+func desiredDNSDaemonSet(namespace string, dns *operatorv1.DNS, clusterIP, clusterDomain string, image coreDNSImage, openshiftCLIImage string) (*appsv1.DaemonSet, error) {
 	daemonset := manifests.DNSDaemonSet()
-	name := DNSDaemonSetName(dns)
+	name := DNSDaemonSetName(namespace, dns)
 	daemonset.Name = name.Name
 	daemonset.Namespace = name.Namespace
 	daemonset.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
@@ -77,7 +100,7 @@ func desiredDNSDaemonSet(dns *operatorv1.DNS, clusterIP, clusterDomain, coreDNSI
 	for i := range daemonset.Spec.Template.Spec.Volumes {
 		// TODO: remove hardcoding of volume name
 		if daemonset.Spec.Template.Spec.Volumes[i].Name == "config-volume" {
-			daemonset.Spec.Template.Spec.Volumes[i].ConfigMap.Name = DNSConfigMapName(dns).Name
+			daemonset.Spec.Template.Spec.Volumes[i].ConfigMap.Name = DNSConfigMapName(namespace, dns).Name
 			coreFileVolumeFound = true
 			break
 		}
@@ -86,12 +109,33 @@ func desiredDNSDaemonSet(dns *operatorv1.DNS, clusterIP, clusterDomain, coreDNSI
 		return nil, fmt.Errorf("volume 'config-volume' is not found")
 	}
 
+	lameDuckDuration, err := dnsLameDuckDuration(dns)
+	if err != nil {
+		return nil, err
+	}
+	gracePeriod := int64((lameDuckDuration + terminationGracePeriodPadding + time.Second - 1) / time.Second)
+	daemonset.Spec.Template.Spec.TerminationGracePeriodSeconds = &gracePeriod
+
 	for i, c := range daemonset.Spec.Template.Spec.Containers {
 		switch c.Name {
 		case "dns":
-			daemonset.Spec.Template.Spec.Containers[i].Image = coreDNSImage
+			daemonset.Spec.Template.Spec.Containers[i].Image = image.name
+			if probe := daemonset.Spec.Template.Spec.Containers[i].ReadinessProbe; probe != nil && probe.HTTPGet != nil && !image.supports("ready") {
+				probe.HTTPGet.Path = "/health"
+				probe.HTTPGet.Port = intstr.FromInt(8080)
+			}
+			resources, err := mergeResourceRequirements(withPodsMemoryOverhead(dns, c.Resources), dns.Spec.Resources.DNS)
+			if err != nil {
+				return nil, fmt.Errorf("invalid resources for container %q: %v", c.Name, err)
+			}
+			daemonset.Spec.Template.Spec.Containers[i].Resources = resources
 		case "dns-node-resolver":
 			daemonset.Spec.Template.Spec.Containers[i].Image = openshiftCLIImage
+			resources, err := mergeResourceRequirements(c.Resources, dns.Spec.Resources.NodeResolver)
+			if err != nil {
+				return nil, fmt.Errorf("invalid resources for container %q: %v", c.Name, err)
+			}
+			daemonset.Spec.Template.Spec.Containers[i].Resources = resources
 			envs := []corev1.EnvVar{}
 			if len(clusterIP) > 0 {
 				envs = append(envs, corev1.EnvVar{
@@ -115,10 +159,45 @@ func desiredDNSDaemonSet(dns *operatorv1.DNS, clusterIP, clusterDomain, coreDNSI
 	return daemonset, nil
 }
 
+// mergeResourceRequirements returns the result of overriding the default
+// requests and limits with those that are set in overrides.  An error is
+// returned if any resulting request exceeds the limit for the same resource.
+func mergeResourceRequirements(defaults, overrides corev1.ResourceRequirements) (corev1.ResourceRequirements, error) {
+	merged := *defaults.DeepCopy()
+	for name, quantity := range overrides.Requests {
+		if merged.Requests == nil {
+			merged.Requests = corev1.ResourceList{}
+		}
+		merged.Requests[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range overrides.Limits {
+		if merged.Limits == nil {
+			merged.Limits = corev1.ResourceList{}
+		}
+		merged.Limits[name] = quantity.DeepCopy()
+	}
+	for name, request := range merged.Requests {
+		if limit, ok := merged.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return corev1.ResourceRequirements{}, fmt.Errorf("%s request %s exceeds limit %s", name, request.String(), limit.String())
+		}
+	}
+	return merged, nil
+}
+
+// resourceRequirementsEqual compares two ResourceRequirements values by
+// quantity rather than by representation.
+func resourceRequirementsEqual(a, b corev1.ResourceRequirements) bool {
+	cmpOpts := []cmp.Option{
+		cmpopts.EquateEmpty(),
+		cmp.Comparer(func(x, y resource.Quantity) bool { return x.Cmp(y) == 0 }),
+	}
+	return cmp.Equal(a, b, cmpOpts...)
+}
+
 // currentDNSDaemonSet returns the current dns daemonset.
 func (r *reconciler) currentDNSDaemonSet(dns *operatorv1.DNS) (*appsv1.DaemonSet, error) {
 	daemonset := &appsv1.DaemonSet{}
-	if err := r.client.Get(context.TODO(), DNSDaemonSetName(dns), daemonset); err != nil {
+	if err := r.client.Get(context.TODO(), DNSDaemonSetName(r.OperandNamespace, dns), daemonset); err != nil {
 		if errors.IsNotFound(err) {
 			return nil, nil
 		}
@@ -128,65 +207,97 @@ func (r *reconciler) currentDNSDaemonSet(dns *operatorv1.DNS) (*appsv1.DaemonSet
 }
 
 // createDNSDaemonSet creates a dns daemonset.
-func (r *reconciler) createDNSDaemonSet(daemonset *appsv1.DaemonSet) error {
+func (r *reconciler) createDNSDaemonSet(dns *operatorv1.DNS, daemonset *appsv1.DaemonSet) error {
 	if err := r.client.Create(context.TODO(), daemonset); err != nil {
+		r.recordCreateFailed(dns, daemonset, err)
 		return fmt.Errorf("failed to create dns daemonset %s/%s: %v", daemonset.Namespace, daemonset.Name, err)
 	}
-	logrus.Infof("created dns daemonset: %s/%s", daemonset.Namespace, daemonset.Name)
+	r.logObject(daemonset).Info("created dns daemonset")
+	r.recordCreated(dns, daemonset)
 	return nil
 }
 
 // updateDNSDaemonSet updates a dns daemonset.
-func (r *reconciler) updateDNSDaemonSet(current, desired *appsv1.DaemonSet) error {
+func (r *reconciler) updateDNSDaemonSet(dns *operatorv1.DNS, current, desired *appsv1.DaemonSet) error {
 	changed, updated := daemonsetConfigChanged(current, desired)
 	if !changed {
 		return nil
 	}
 
 	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(dns, updated, err)
 		return fmt.Errorf("failed to update dns daemonset %s/%s: %v", updated.Namespace, updated.Name, err)
 	}
-	logrus.Infof("updated dns daemonset: %s/%s", updated.Namespace, updated.Name)
+	recordDriftCorrection("daemonset")
+	r.logObject(updated).Info("updated dns daemonset")
+	r.recordUpdated(dns, updated)
 	return nil
 }
 
 // daemonsetConfigChanged checks if current config matches the expected config
 // for the dns daemonset and if not returns the updated config.
 func daemonsetConfigChanged(current, expected *appsv1.DaemonSet) (bool, *appsv1.DaemonSet) {
-	changed := false
 	updated := current.DeepCopy()
-	for _, name := range []string{"dns", "dns-node-resolver"} {
+	what := fmt.Sprintf("daemonset %s/%s", current.Namespace, current.Name)
+	if !podSpecChanged(what, &current.Spec.Template.Spec, &expected.Spec.Template.Spec, &updated.Spec.Template.Spec) {
+		return false, nil
+	}
+	return true, updated
+}
+
+// podSpecChanged checks if the current pod spec of a dns workload matches
+// the expected pod spec and if not updates updated, which must be a copy of
+// current, to match.
+func podSpecChanged(what string, current, expected, updated *corev1.PodSpec) bool {
+	changed := false
+	for _, c := range expected.Containers {
+		name := c.Name
 		var curIndex int
 		var curImage, expImage string
+		var curReadinessProbe, expReadinessProbe *corev1.Probe
+		var curResources, expResources corev1.ResourceRequirements
 
-		for i, c := range current.Spec.Template.Spec.Containers {
+		for i, c := range current.Containers {
 			if name == c.Name {
 				curIndex = i
-				curImage = current.Spec.Template.Spec.Containers[i].Image
+				curImage = current.Containers[i].Image
+				curReadinessProbe = current.Containers[i].ReadinessProbe
+				curResources = current.Containers[i].Resources
 				break
 			}
 		}
-		for i, c := range expected.Spec.Template.Spec.Containers {
+		for i, c := range expected.Containers {
 			if name == c.Name {
-				expImage = expected.Spec.Template.Spec.Containers[i].Image
+				expImage = expected.Containers[i].Image
+				expReadinessProbe = expected.Containers[i].ReadinessProbe
+				expResources = expected.Containers[i].Resources
 				break
 			}
 		}
 
 		if len(curImage) == 0 {
-			logrus.Errorf("current daemonset %s/%s did not contain expected %s container", current.Namespace, current.Name, name)
-			updated.Spec.Template.Spec.Containers = expected.Spec.Template.Spec.Containers
+			logrus.WithFields(logrus.Fields{"workload": what, "container": name}).Error("current workload did not contain expected container")
+			updated.Containers = expected.Containers
 			changed = true
 			break
 		} else if curImage != expImage {
-			updated.Spec.Template.Spec.Containers[curIndex].Image = expImage
+			updated.Containers[curIndex].Image = expImage
 			changed = true
 		}
+		if !cmp.Equal(curReadinessProbe, expReadinessProbe) {
+			updated.Containers[curIndex].ReadinessProbe = expReadinessProbe
+			changed = true
+		}
+		if !resourceRequirementsEqual(curResources, expResources) {
+			updated.Containers[curIndex].Resources = expResources
+			changed = true
+		}
+	}
+	if !cmp.Equal(current.TerminationGracePeriodSeconds, expected.TerminationGracePeriodSeconds) {
+		updated.TerminationGracePeriodSeconds = expected.TerminationGracePeriodSeconds
+		changed = true
 	}
 	// TODO: Also check Env and Volume sources?
 
-	if !changed {
-		return false, nil
-	}
-	return true, updated
+	return changed
 }
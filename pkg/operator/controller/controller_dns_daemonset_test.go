@@ -2,16 +2,22 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorconfig "github.com/openshift/cluster-dns-operator/pkg/operator/config"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestDesiredDNSDaemonset(t *testing.T) {
 	clusterDomain := "cluster.local"
 	clusterIP := "172.30.77.10"
-	coreDNSImage := "quay.io/openshift/coredns:test"
+	coreDNSImage := coreDNSImage{name: "quay.io/openshift/coredns:test", version: version.MustParse("1.5.0")}
 	openshiftCLIImage := "openshift/origin-cli:test"
 
 	dns := &operatorv1.DNS{
@@ -20,17 +26,22 @@ func TestDesiredDNSDaemonset(t *testing.T) {
 		},
 	}
 
-	if ds, err := desiredDNSDaemonSet(dns, clusterIP, clusterDomain, coreDNSImage, openshiftCLIImage); err != nil {
+	if ds, err := desiredDNSDaemonSet("openshift-dns", dns, clusterIP, clusterDomain, coreDNSImage, openshiftCLIImage); err != nil {
 		t.Errorf("invalid dns daemonset: %v", err)
 	} else {
 		// Validate the daemonset
 		if len(ds.Spec.Template.Spec.Containers) != 2 {
 			t.Errorf("expected number of daemonset containers 2, got %d", len(ds.Spec.Template.Spec.Containers))
 		}
+		if gracePeriod := ds.Spec.Template.Spec.TerminationGracePeriodSeconds; gracePeriod == nil {
+			t.Errorf("expected daemonset termination grace period to be set")
+		} else if e, a := int64(30), *gracePeriod; e != a {
+			t.Errorf("expected daemonset termination grace period %d, got %d", e, a)
+		}
 		for _, c := range ds.Spec.Template.Spec.Containers {
 			switch c.Name {
 			case "dns":
-				if e, a := coreDNSImage, c.Image; e != a {
+				if e, a := coreDNSImage.name, c.Image; e != a {
 					t.Errorf("expected daemonset dns image %q, got %q", e, a)
 				}
 			case "dns-node-resolver":
@@ -60,3 +71,255 @@ func TestDesiredDNSDaemonset(t *testing.T) {
 		}
 	}
 }
+
+func TestDesiredDNSDaemonSetReadinessProbe(t *testing.T) {
+	dns := &operatorv1.DNS{ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController}}
+	testCases := []struct {
+		version      string
+		expectedPath string
+		expectedPort int
+	}{
+		{"1.3.1", "/health", 8080},
+		{"1.5.0", "/ready", 8181},
+		// The default version must have the ready plugin, so that
+		// readiness does not fall back to liveness by default.
+		{operatorconfig.DefaultCoreDNSVersion, "/ready", 8181},
+	}
+	for _, tc := range testCases {
+		image := coreDNSImage{name: "coredns", version: version.MustParse(tc.version)}
+		ds, err := desiredDNSDaemonSet("openshift-dns", dns, "172.30.77.10", "cluster.local", image, "cli")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.version, err)
+		}
+		for _, c := range ds.Spec.Template.Spec.Containers {
+			if c.Name != "dns" {
+				continue
+			}
+			if c.ReadinessProbe == nil || c.ReadinessProbe.HTTPGet == nil {
+				t.Errorf("%s: expected dns container to have an http readiness probe", tc.version)
+				continue
+			}
+			if get := c.ReadinessProbe.HTTPGet; get.Path != tc.expectedPath || get.Port.IntValue() != tc.expectedPort {
+				t.Errorf("%s: expected readiness probe on %s:%d, got %s:%s", tc.version, tc.expectedPath, tc.expectedPort, get.Path, get.Port.String())
+			}
+		}
+	}
+}
+
+func TestDesiredDNSDaemonsetLameDuck(t *testing.T) {
+	testCases := []struct {
+		description string
+		lameDuck    *metav1.Duration
+		expected    int64
+		expectErr   bool
+	}{
+		{"default", nil, 30, false},
+		{"disabled", &metav1.Duration{Duration: 0}, 10, false},
+		{"fractional seconds", &metav1.Duration{Duration: 5500 * time.Millisecond}, 16, false},
+		{"negative", &metav1.Duration{Duration: -1 * time.Second}, 0, true},
+	}
+
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: DefaultDNSController,
+			},
+			Spec: operatorv1.DNSSpec{
+				LameDuckDuration: tc.lameDuck,
+			},
+		}
+		ds, err := desiredDNSDaemonSet("openshift-dns", dns, "172.30.77.10", "cluster.local", defaultCoreDNSImage, "cli")
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if e, a := tc.expected, *ds.Spec.Template.Spec.TerminationGracePeriodSeconds; e != a {
+			t.Errorf("%q: expected termination grace period %d, got %d", tc.description, e, a)
+		}
+	}
+}
+
+func TestDaemonsetConfigChanged(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	original, err := desiredDNSDaemonSet("openshift-dns", dns, "172.30.77.10", "cluster.local", defaultCoreDNSImage, "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		description string
+		mutate      func(*appsv1.DaemonSet)
+		expect      bool
+	}{
+		{
+			description: "if nothing changes",
+			mutate:      func(_ *appsv1.DaemonSet) {},
+			expect:      false,
+		},
+		{
+			description: "if the dns image changes",
+			mutate: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].Image = "coredns:new"
+			},
+			expect: true,
+		},
+		{
+			description: "if the readiness probe is removed",
+			mutate: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].ReadinessProbe = nil
+			},
+			expect: true,
+		},
+		{
+			description: "if the dns memory limit changes",
+			mutate: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1Gi")
+			},
+			expect: true,
+		},
+		{
+			description: "if a resource quantity is only represented differently",
+			mutate: func(ds *appsv1.DaemonSet) {
+				ds.Spec.Template.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("536870912")
+			},
+			expect: false,
+		},
+		{
+			description: "if the termination grace period changes",
+			mutate: func(ds *appsv1.DaemonSet) {
+				gracePeriod := int64(1)
+				ds.Spec.Template.Spec.TerminationGracePeriodSeconds = &gracePeriod
+			},
+			expect: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		mutated := original.DeepCopy()
+		tc.mutate(mutated)
+		if changed, updated := daemonsetConfigChanged(mutated, original); changed != tc.expect {
+			t.Errorf("%s, expect daemonsetConfigChanged to be %t, got %t", tc.description, tc.expect, changed)
+		} else if changed {
+			if changedAgain, _ := daemonsetConfigChanged(updated, original); changedAgain {
+				t.Errorf("%s, daemonsetConfigChanged does not behave as a fixed point function", tc.description)
+			}
+		}
+	}
+}
+
+func TestDesiredDNSDaemonsetResources(t *testing.T) {
+	testCases := []struct {
+		description  string
+		resources    operatorv1.DNSResources
+		expectErr    bool
+		dnsRequests  corev1.ResourceList
+		dnsLimits    corev1.ResourceList
+		nodeRequests corev1.ResourceList
+	}{
+		{
+			description: "defaults",
+			dnsRequests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("70Mi"),
+			},
+			dnsLimits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+			nodeRequests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("10m"),
+			},
+		},
+		{
+			description: "override only the dns memory limit",
+			resources: operatorv1.DNSResources{
+				DNS: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+				NodeResolver: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("20Mi"),
+					},
+				},
+			},
+			dnsRequests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("70Mi"),
+			},
+			dnsLimits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+			nodeRequests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("20Mi"),
+			},
+		},
+		{
+			description: "request exceeds default limit",
+			resources: operatorv1.DNSResources{
+				DNS: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			description: "request exceeds node resolver limit",
+			resources: operatorv1.DNSResources{
+				NodeResolver: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("5m"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: DefaultDNSController,
+			},
+			Spec: operatorv1.DNSSpec{
+				Resources: tc.resources,
+			},
+		}
+		ds, err := desiredDNSDaemonSet("openshift-dns", dns, "172.30.77.10", "cluster.local", defaultCoreDNSImage, "cli")
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tc.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.description, err)
+			continue
+		}
+		for _, c := range ds.Spec.Template.Spec.Containers {
+			var expected corev1.ResourceRequirements
+			switch c.Name {
+			case "dns":
+				expected = corev1.ResourceRequirements{Requests: tc.dnsRequests, Limits: tc.dnsLimits}
+			case "dns-node-resolver":
+				expected = corev1.ResourceRequirements{Requests: tc.nodeRequests}
+			}
+			if !resourceRequirementsEqual(expected, c.Resources) {
+				t.Errorf("%q: expected container %q resources %v, got %v", tc.description, c.Name, expected, c.Resources)
+			}
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultMinDNSReplicas is the lower bound on the number of CoreDNS
+	// replicas when the dns does not specify one.
+	defaultMinDNSReplicas = 2
+)
+
+var (
+	// defaultCoresToReplicas is the cores ladder when the dns does not
+	// specify one.
+	defaultCoresToReplicas = []operatorv1.DNSReplicaStep{
+		{Threshold: 1, Replicas: 2},
+		{Threshold: 64, Replicas: 3},
+		{Threshold: 512, Replicas: 5},
+		{Threshold: 1024, Replicas: 7},
+		{Threshold: 2048, Replicas: 10},
+		{Threshold: 4096, Replicas: 15},
+	}
+
+	// defaultNodesToReplicas is the nodes ladder when the dns does not
+	// specify one.
+	defaultNodesToReplicas = []operatorv1.DNSReplicaStep{
+		{Threshold: 1, Replicas: 2},
+		{Threshold: 16, Replicas: 3},
+		{Threshold: 128, Replicas: 5},
+		{Threshold: 512, Replicas: 7},
+	}
+)
+
+// ensureDNSDeployment ensures the dns deployment, and the node resolver
+// daemonset that goes with it, exist for a given dns.
+func (r *reconciler) ensureDNSDeployment(dns *operatorv1.DNS, clusterIP, clusterDomain string) (*appsv1.Deployment, error) {
+	daemonset, err := desiredDNSDaemonSet(r.OperandNamespace, dns, clusterIP, clusterDomain, r.coreDNSImage(), r.OpenshiftCLIImage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dns daemonset: %v", err)
+	}
+	if err := r.ensureNodeResolverDaemonSet(dns, daemonset); err != nil {
+		return nil, err
+	}
+
+	replicas, err := r.desiredDNSReplicas(dns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute dns replicas: %v", err)
+	}
+	desired := desiredDNSDeployment(r.OperandNamespace, dns, daemonset, replicas)
+	current, err := r.currentDNSDeployment(dns)
+	if err != nil {
+		return nil, err
+	}
+	var currentSpec *corev1.PodSpec
+	if current != nil {
+		currentSpec = &current.Spec.Template.Spec
+	}
+	if err := r.applyDNSSizing(dns, currentSpec, &desired.Spec.Template.Spec); err != nil {
+		return nil, fmt.Errorf("failed to size dns deployment: %v", err)
+	}
+	if current == nil {
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return nil, fmt.Errorf("failed to create dns deployment %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns deployment")
+		r.recordCreated(dns, desired)
+	} else if changed, updated := deploymentConfigChanged(current, desired); changed {
+		if err := r.client.Update(context.TODO(), updated); err != nil {
+			r.recordUpdateFailed(dns, updated, err)
+			return nil, fmt.Errorf("failed to update dns deployment %s/%s: %v", updated.Namespace, updated.Name, err)
+		}
+		recordDriftCorrection("deployment")
+		r.logObject(updated).Info("updated dns deployment")
+		r.recordUpdated(dns, updated)
+	}
+	return r.currentDNSDeployment(dns)
+}
+
+// ensureDNSDeploymentDeleted ensures deletion of the dns deployment and the
+// node resolver daemonset that goes with it.
+func (r *reconciler) ensureDNSDeploymentDeleted(dns *operatorv1.DNS) error {
+	deployment := &appsv1.Deployment{}
+	name := DNSDeploymentName(r.OperandNamespace, dns)
+	deployment.Name = name.Name
+	deployment.Namespace = name.Namespace
+	if err := r.client.Delete(context.TODO(), deployment); err != nil {
+		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, deployment, err)
+			return err
+		}
+	} else {
+		r.logObject(deployment).Info("deleted dns deployment")
+		r.recordDeleted(dns, deployment)
+	}
+
+	daemonset := &appsv1.DaemonSet{}
+	name = NodeResolverDaemonSetName(r.OperandNamespace, dns)
+	daemonset.Name = name.Name
+	daemonset.Namespace = name.Namespace
+	if err := r.client.Delete(context.TODO(), daemonset); err != nil {
+		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, daemonset, err)
+			return err
+		}
+	} else {
+		r.logObject(daemonset).Info("deleted node resolver daemonset")
+		r.recordDeleted(dns, daemonset)
+	}
+	return nil
+}
+
+// desiredDNSDeployment returns the desired dns deployment.  The pod spec is
+// taken from the CoreDNS container of the desired dns daemonset so that
+// both topologies run the same CoreDNS.
+func desiredDNSDeployment(namespace string, dns *operatorv1.DNS, daemonset *appsv1.DaemonSet, replicas int32) *appsv1.Deployment {
+	deployment := manifests.DNSDeployment()
+	name := DNSDeploymentName(namespace, dns)
+	deployment.Name = name.Name
+	deployment.Namespace = name.Namespace
+	deployment.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+
+	deployment.Labels = map[string]string{
+		// associate the deployment with the dns
+		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
+	}
+
+	deployment.Spec.Replicas = &replicas
+	deployment.Spec.Selector = DNSDeploymentPodSelector(dns)
+
+	// The pods also carry the daemonset pod labels, which the dns service
+	// selects.
+	deployment.Spec.Template.Labels = map[string]string{}
+	for k, v := range deployment.Spec.Selector.MatchLabels {
+		deployment.Spec.Template.Labels[k] = v
+	}
+	for k, v := range DNSDaemonSetPodSelector(dns).MatchLabels {
+		deployment.Spec.Template.Labels[k] = v
+	}
+
+	template := deployment.Spec.Template.Spec
+	spec := podSpecWithContainers(&daemonset.Spec.Template.Spec, "dns")
+	spec.PriorityClassName = template.PriorityClassName
+	spec.Tolerations = template.Tolerations
+	spec.Affinity = template.Affinity
+	if spec.Affinity != nil && spec.Affinity.PodAntiAffinity != nil {
+		terms := spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		for i := range terms {
+			terms[i].PodAffinityTerm.LabelSelector = DNSDeploymentPodSelector(dns)
+		}
+	}
+	deployment.Spec.Template.Spec = spec
+	return deployment
+}
+
+// podSpecWithContainers returns a copy of the given pod spec with only the
+// named containers and the volumes that they mount.
+func podSpecWithContainers(spec *corev1.PodSpec, names ...string) corev1.PodSpec {
+	result := *spec.DeepCopy()
+	result.Containers = nil
+	mounted := map[string]bool{}
+	for _, c := range spec.Containers {
+		for _, name := range names {
+			if c.Name != name {
+				continue
+			}
+			result.Containers = append(result.Containers, *c.DeepCopy())
+			for _, m := range c.VolumeMounts {
+				mounted[m.Name] = true
+			}
+		}
+	}
+	result.Volumes = nil
+	for _, v := range spec.Volumes {
+		if mounted[v.Name] {
+			result.Volumes = append(result.Volumes, *v.DeepCopy())
+		}
+	}
+	return result
+}
+
+// currentDNSDeployment returns the current dns deployment.
+func (r *reconciler) currentDNSDeployment(dns *operatorv1.DNS) (*appsv1.Deployment, error) {
+	deployment := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), DNSDeploymentName(r.OperandNamespace, dns), deployment); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// deploymentConfigChanged checks if current config matches the expected
+// config for the dns deployment and if not returns the updated config.
+func deploymentConfigChanged(current, expected *appsv1.Deployment) (bool, *appsv1.Deployment) {
+	updated := current.DeepCopy()
+	what := fmt.Sprintf("deployment %s/%s", current.Namespace, current.Name)
+	changed := podSpecChanged(what, &current.Spec.Template.Spec, &expected.Spec.Template.Spec, &updated.Spec.Template.Spec)
+	if current.Spec.Replicas == nil || *current.Spec.Replicas != *expected.Spec.Replicas {
+		updated.Spec.Replicas = expected.Spec.Replicas
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, updated
+}
+
+// desiredDNSReplicas returns the number of CoreDNS replicas for the current
+// size of the cluster.
+func (r *reconciler) desiredDNSReplicas(dns *operatorv1.DNS) (int32, error) {
+	if err := r.ensureClusterWatch(&corev1.Node{}); err != nil {
+		return 0, err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.clusterCache.List(context.TODO(), nodes); err != nil {
+		return 0, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	var schedulable, milliCores int64
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		schedulable++
+		cpu := node.Status.Allocatable[corev1.ResourceCPU]
+		milliCores += cpu.MilliValue()
+	}
+	topology := dns.Spec.Topology.Deployment
+	if topology == nil {
+		topology = &operatorv1.DeploymentDNSTopology{}
+	}
+	return dnsReplicas(topology, schedulable, (milliCores+999)/1000)
+}
+
+// dnsReplicas returns the number of CoreDNS replicas for a cluster with the
+// given number of nodes and cores.
+func dnsReplicas(topology *operatorv1.DeploymentDNSTopology, nodes, cores int64) (int32, error) {
+	coresToReplicas := topology.CoresToReplicas
+	if len(coresToReplicas) == 0 {
+		coresToReplicas = defaultCoresToReplicas
+	}
+	nodesToReplicas := topology.NodesToReplicas
+	if len(nodesToReplicas) == 0 {
+		nodesToReplicas = defaultNodesToReplicas
+	}
+	minReplicas := int32(defaultMinDNSReplicas)
+	if topology.MinReplicas != nil {
+		minReplicas = *topology.MinReplicas
+	}
+	if minReplicas < 1 {
+		return 0, fmt.Errorf("invalid minReplicas %d: must be at least 1", minReplicas)
+	}
+	if topology.MaxReplicas != nil && *topology.MaxReplicas < minReplicas {
+		return 0, fmt.Errorf("invalid maxReplicas %d: must not be less than minReplicas %d", *topology.MaxReplicas, minReplicas)
+	}
+
+	byCores, err := replicasFromLadder(coresToReplicas, cores)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coresToReplicas: %v", err)
+	}
+	byNodes, err := replicasFromLadder(nodesToReplicas, nodes)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nodesToReplicas: %v", err)
+	}
+
+	replicas := minReplicas
+	if byCores > replicas {
+		replicas = byCores
+	}
+	if byNodes > replicas {
+		replicas = byNodes
+	}
+	if topology.MaxReplicas != nil && replicas > *topology.MaxReplicas {
+		replicas = *topology.MaxReplicas
+	}
+	return replicas, nil
+}
+
+// replicasFromLadder returns the replicas of the step with the largest
+// threshold that does not exceed count, or 0 if there is no such step.
+func replicasFromLadder(ladder []operatorv1.DNSReplicaStep, count int64) (int32, error) {
+	steps := make([]operatorv1.DNSReplicaStep, len(ladder))
+	copy(steps, ladder)
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Threshold < steps[j].Threshold })
+
+	var replicas int32
+	for _, step := range steps {
+		if step.Threshold < 0 || step.Replicas < 1 {
+			return 0, fmt.Errorf("invalid step %d:%d", step.Threshold, step.Replicas)
+		}
+		if int64(step.Threshold) <= count {
+			replicas = step.Replicas
+		}
+	}
+	return replicas, nil
+}
+
+// ensureNodeResolverDaemonSet ensures that the node resolver runs on every
+// node when CoreDNS does not.
+func (r *reconciler) ensureNodeResolverDaemonSet(dns *operatorv1.DNS, daemonset *appsv1.DaemonSet) error {
+	desired := desiredNodeResolverDaemonSet(r.OperandNamespace, dns, daemonset)
+	current := &appsv1.DaemonSet{}
+	if err := r.client.Get(context.TODO(), NodeResolverDaemonSetName(r.OperandNamespace, dns), current); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get node resolver daemonset: %v", err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return fmt.Errorf("failed to create node resolver daemonset %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created node resolver daemonset")
+		r.recordCreated(dns, desired)
+		return nil
+	}
+	if changed, updated := daemonsetConfigChanged(current, desired); changed {
+		if err := r.client.Update(context.TODO(), updated); err != nil {
+			r.recordUpdateFailed(dns, updated, err)
+			return fmt.Errorf("failed to update node resolver daemonset %s/%s: %v", updated.Namespace, updated.Name, err)
+		}
+		recordDriftCorrection("daemonset")
+		r.logObject(updated).Info("updated node resolver daemonset")
+		r.recordUpdated(dns, updated)
+	}
+	return nil
+}
+
+// desiredNodeResolverDaemonSet returns the desired node resolver daemonset,
+// which runs only the node resolver container of the dns daemonset.
+func desiredNodeResolverDaemonSet(namespace string, dns *operatorv1.DNS, daemonset *appsv1.DaemonSet) *appsv1.DaemonSet {
+	resolver := daemonset.DeepCopy()
+	name := NodeResolverDaemonSetName(namespace, dns)
+	resolver.Name = name.Name
+	resolver.Namespace = name.Namespace
+	resolver.Spec.Selector = NodeResolverDaemonSetPodSelector(dns)
+	resolver.Spec.Template.Labels = resolver.Spec.Selector.MatchLabels
+	resolver.Spec.Template.Spec = podSpecWithContainers(&daemonset.Spec.Template.Spec, "dns-node-resolver")
+	return resolver
+}
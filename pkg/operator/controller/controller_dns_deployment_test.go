@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDesiredDNSDeployment(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	ds, err := desiredDNSDaemonSet("openshift-dns", dns, "172.30.77.10", "cluster.local", defaultCoreDNSImage, "openshift/origin-cli:test")
+	if err != nil {
+		t.Fatalf("invalid dns daemonset: %v", err)
+	}
+
+	deployment := desiredDNSDeployment("openshift-dns", dns, ds, 3)
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 3 {
+		t.Errorf("expected 3 replicas, got %v", deployment.Spec.Replicas)
+	}
+	spec := deployment.Spec.Template.Spec
+	if len(spec.Containers) != 1 || spec.Containers[0].Name != "dns" {
+		t.Errorf("expected only the dns container, got %v", spec.Containers)
+	}
+	if len(spec.Volumes) != 1 || spec.Volumes[0].Name != "config-volume" {
+		t.Errorf("expected only the config-volume volume, got %v", spec.Volumes)
+	}
+	if len(spec.Tolerations) != 0 {
+		t.Errorf("expected no tolerations, got %v", spec.Tolerations)
+	}
+	if spec.PriorityClassName != "system-cluster-critical" {
+		t.Errorf("expected priority class system-cluster-critical, got %q", spec.PriorityClassName)
+	}
+	if spec.Affinity == nil || spec.Affinity.PodAntiAffinity == nil || len(spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Fatalf("expected a preferred pod anti-affinity term, got %v", spec.Affinity)
+	}
+	term := spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+	if term.LabelSelector == nil || term.LabelSelector.MatchLabels[controllerDeploymentLabel] != DefaultDNSController {
+		t.Errorf("expected anti-affinity to select deployment pods, got %v", term.LabelSelector)
+	}
+
+	// The dns service selects pods by the daemonset pod labels.
+	labels := deployment.Spec.Template.Labels
+	for k, v := range DNSDaemonSetPodSelector(dns).MatchLabels {
+		if labels[k] != v {
+			t.Errorf("expected pod label %s=%s, got %v", k, v, labels)
+		}
+	}
+	for k, v := range deployment.Spec.Selector.MatchLabels {
+		if labels[k] != v {
+			t.Errorf("expected pod label %s=%s from the selector, got %v", k, v, labels)
+		}
+	}
+
+	resolver := desiredNodeResolverDaemonSet("openshift-dns", dns, ds)
+	resolverSpec := resolver.Spec.Template.Spec
+	if len(resolverSpec.Containers) != 1 || resolverSpec.Containers[0].Name != "dns-node-resolver" {
+		t.Errorf("expected only the dns-node-resolver container, got %v", resolverSpec.Containers)
+	}
+	if len(resolverSpec.Volumes) != 1 || resolverSpec.Volumes[0].Name != "hosts-file" {
+		t.Errorf("expected only the hosts-file volume, got %v", resolverSpec.Volumes)
+	}
+	if _, ok := resolver.Spec.Template.Labels[controllerDaemonSetLabel]; ok {
+		t.Errorf("expected node resolver pods not to be selected by the dns service, got labels %v", resolver.Spec.Template.Labels)
+	}
+	if !isAuxiliaryDaemonSet(resolver) {
+		t.Errorf("expected node resolver daemonset to be recognized")
+	}
+}
+
+func TestDNSReplicas(t *testing.T) {
+	one, four, ten := int32(1), int32(4), int32(10)
+	testCases := []struct {
+		description string
+		topology    operatorv1.DeploymentDNSTopology
+		nodes       int64
+		cores       int64
+		expected    int32
+	}{
+		{
+			description: "defaults for a small cluster",
+			nodes:       3,
+			cores:       12,
+			expected:    2,
+		},
+		{
+			description: "defaults scale with cores",
+			nodes:       10,
+			cores:       600,
+			expected:    5,
+		},
+		{
+			description: "defaults scale with nodes",
+			nodes:       500,
+			cores:       100,
+			expected:    5,
+		},
+		{
+			description: "defaults for a 500 node cluster with 4 cores each",
+			nodes:       500,
+			cores:       2000,
+			expected:    7,
+		},
+		{
+			description: "empty cluster gets minReplicas",
+			expected:    2,
+		},
+		{
+			description: "custom ladders are not assumed to be sorted",
+			topology: operatorv1.DeploymentDNSTopology{
+				CoresToReplicas: []operatorv1.DNSReplicaStep{{Threshold: 100, Replicas: 6}, {Threshold: 0, Replicas: 1}},
+				NodesToReplicas: []operatorv1.DNSReplicaStep{{Threshold: 0, Replicas: 1}},
+				MinReplicas:     &one,
+			},
+			nodes:    5,
+			cores:    150,
+			expected: 6,
+		},
+		{
+			description: "maxReplicas bounds the result",
+			topology: operatorv1.DeploymentDNSTopology{
+				MaxReplicas: &four,
+			},
+			nodes:    500,
+			cores:    2000,
+			expected: 4,
+		},
+		{
+			description: "minReplicas bounds the result",
+			topology: operatorv1.DeploymentDNSTopology{
+				MinReplicas: &ten,
+			},
+			nodes:    3,
+			cores:    12,
+			expected: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		replicas, err := dnsReplicas(&tc.topology, tc.nodes, tc.cores)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if replicas != tc.expected {
+			t.Errorf("%q: expected %d replicas, got %d", tc.description, tc.expected, replicas)
+		}
+	}
+}
+
+func TestDNSReplicasInvalid(t *testing.T) {
+	four, two := int32(4), int32(2)
+	testCases := []struct {
+		description string
+		topology    operatorv1.DeploymentDNSTopology
+	}{
+		{
+			description: "maxReplicas below minReplicas",
+			topology: operatorv1.DeploymentDNSTopology{
+				MinReplicas: &four,
+				MaxReplicas: &two,
+			},
+		},
+		{
+			description: "step with no replicas",
+			topology: operatorv1.DeploymentDNSTopology{
+				NodesToReplicas: []operatorv1.DNSReplicaStep{{Threshold: 1, Replicas: 0}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		if _, err := dnsReplicas(&tc.topology, 3, 12); err == nil {
+			t.Errorf("%q: expected an error", tc.description)
+		}
+	}
+}
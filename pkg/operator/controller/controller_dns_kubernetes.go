@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxKubernetesTTL is the largest TTL that the kubernetes plugin accepts.
+const maxKubernetesTTL = 3600
+
+// kubernetesParameters are the options of the kubernetes plugin that are
+// substituted into the Corefile template.  Options that are empty are not
+// rendered.
+type kubernetesParameters struct {
+	TTL              string
+	Namespaces       []string
+	EndpointPodNames bool
+	Autopath         bool
+}
+
+// dnsKubernetesParameters returns the kubernetes plugin options of the
+// given dns.
+func dnsKubernetesParameters(dns *operatorv1.DNS) kubernetesParameters {
+	params := kubernetesParameters{
+		Namespaces:       dns.Spec.Kubernetes.Namespaces,
+		EndpointPodNames: dns.Spec.Kubernetes.EndpointPodNames,
+		Autopath:         dns.Spec.Kubernetes.Autopath,
+	}
+	if ttl := dns.Spec.Kubernetes.TTL; ttl != nil {
+		params.TTL = strconv.Itoa(int(*ttl))
+	}
+	return params
+}
+
+// validateDNSKubernetes returns an error describing every kubernetes plugin
+// option of the dns that is invalid or that the CoreDNS image does not
+// support.
+func validateDNSKubernetes(dns *operatorv1.DNS, image coreDNSImage) error {
+	var errs []error
+	spec := dns.Spec.Kubernetes
+	if spec.TTL != nil {
+		if ttl := *spec.TTL; ttl < 0 || ttl > maxKubernetesTTL {
+			errs = append(errs, fmt.Errorf("spec.kubernetes.ttl: invalid value %d: must be between 0 and %d", ttl, maxKubernetesTTL))
+		}
+		if err := requireCoreDNSOption(image, "kubernetes ttl", "spec.kubernetes.ttl"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(spec.Namespaces) != 0 {
+		seen := map[string]bool{}
+		for _, ns := range spec.Namespaces {
+			if msgs := validation.IsDNS1123Label(ns); len(msgs) != 0 {
+				errs = append(errs, fmt.Errorf("spec.kubernetes.namespaces: invalid namespace %q: %s", ns, strings.Join(msgs, ", ")))
+			}
+			if seen[ns] {
+				errs = append(errs, fmt.Errorf("spec.kubernetes.namespaces: duplicate namespace %q", ns))
+			}
+			seen[ns] = true
+		}
+		if err := requireCoreDNSOption(image, "kubernetes namespaces", "spec.kubernetes.namespaces"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if spec.EndpointPodNames {
+		if err := requireCoreDNSOption(image, "kubernetes endpoint_pod_names", "spec.kubernetes.endpointPodNames"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if spec.Autopath {
+		if mode := dns.Spec.Pods.Mode; len(mode) != 0 && mode != operatorv1.VerifiedDNSPodsMode {
+			errs = append(errs, fmt.Errorf("spec.kubernetes.autopath: requires pods mode %s, but spec.pods.mode is %s; unset spec.pods.mode or set it to %s", operatorv1.VerifiedDNSPodsMode, mode, operatorv1.VerifiedDNSPodsMode))
+		}
+		if err := requireCoreDNSOption(image, "autopath", "spec.kubernetes.autopath"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDesiredDNSConfigMapKubernetes(t *testing.T) {
+	ttl := int32(30)
+	zero := int32(0)
+	testCases := []struct {
+		description string
+		spec        operatorv1.DNSKubernetes
+		pods        operatorv1.DNSPodsMode
+		expected    string
+	}{
+		{
+			description: "defaults",
+			expected:    "        pods insecure\n        fallthrough in-addr.arpa ip6.arpa\n",
+		},
+		{
+			description: "all options",
+			spec: operatorv1.DNSKubernetes{
+				TTL:              &ttl,
+				Namespaces:       []string{"team-a", "team-b"},
+				EndpointPodNames: true,
+			},
+			expected: "        pods insecure\n        namespaces team-a team-b\n        endpoint_pod_names\n        ttl 30\n        fallthrough in-addr.arpa ip6.arpa\n",
+		},
+		{
+			description: "zero ttl",
+			spec:        operatorv1.DNSKubernetes{TTL: &zero},
+			expected:    "        pods insecure\n        ttl 0\n        fallthrough in-addr.arpa ip6.arpa\n",
+		},
+		{
+			description: "autopath",
+			spec:        operatorv1.DNSKubernetes{Autopath: true},
+			expected:    "    ready\n    autopath @kubernetes\n    kubernetes cluster.local in-addr.arpa ip6.arpa {\n        pods verified\n",
+		},
+		{
+			description: "autopath with verified pods",
+			spec:        operatorv1.DNSKubernetes{Autopath: true},
+			pods:        operatorv1.VerifiedDNSPodsMode,
+			expected:    "    autopath @kubernetes\n    kubernetes cluster.local in-addr.arpa ip6.arpa {\n        pods verified\n",
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+			Spec: operatorv1.DNSSpec{
+				Kubernetes: tc.spec,
+				Pods:       operatorv1.DNSPods{Mode: tc.pods},
+			},
+		}
+		cm, err := desiredDNSConfigMap("openshift-dns", dns, defaultCoreDNSImage, "cluster.local", nil, nil, metav1.OwnerReference{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if !strings.Contains(cm.Data["Corefile"], tc.expected) {
+			t.Errorf("%s: expected Corefile to contain:\n%s\ngot:\n%s", tc.description, tc.expected, cm.Data["Corefile"])
+		}
+	}
+}
+
+func TestValidateDNSKubernetes(t *testing.T) {
+	ttl := int32(30)
+	tooLong := int32(maxKubernetesTTL + 1)
+	testCases := []struct {
+		description string
+		spec        operatorv1.DNSKubernetes
+		pods        operatorv1.DNSPodsMode
+		version     string
+		expectErrs  []string
+	}{
+		{
+			description: "defaults",
+			version:     "1.0.0",
+		},
+		{
+			description: "supported options",
+			spec: operatorv1.DNSKubernetes{
+				TTL:              &ttl,
+				Namespaces:       []string{"team-a"},
+				EndpointPodNames: true,
+			},
+			version: "1.3.1",
+		},
+		{
+			description: "invalid values",
+			spec: operatorv1.DNSKubernetes{
+				TTL:        &tooLong,
+				Namespaces: []string{"team-a", "Team_B", "team-a"},
+			},
+			version: "1.3.1",
+			expectErrs: []string{
+				"spec.kubernetes.ttl: invalid value 3601",
+				`spec.kubernetes.namespaces: invalid namespace "Team_B"`,
+				`spec.kubernetes.namespaces: duplicate namespace "team-a"`,
+			},
+		},
+		{
+			description: "autopath with default pods mode",
+			spec:        operatorv1.DNSKubernetes{Autopath: true},
+			version:     "1.3.1",
+		},
+		{
+			description: "autopath with verified pods",
+			spec:        operatorv1.DNSKubernetes{Autopath: true},
+			pods:        operatorv1.VerifiedDNSPodsMode,
+			version:     "1.3.1",
+		},
+		{
+			description: "autopath with insecure pods",
+			spec:        operatorv1.DNSKubernetes{Autopath: true},
+			pods:        operatorv1.InsecureDNSPodsMode,
+			version:     "1.3.1",
+			expectErrs:  []string{"spec.kubernetes.autopath: requires pods mode Verified, but spec.pods.mode is Insecure"},
+		},
+		{
+			description: "unsupported options",
+			spec: operatorv1.DNSKubernetes{
+				TTL:              &ttl,
+				EndpointPodNames: true,
+			},
+			version: "1.0.4",
+			expectErrs: []string{
+				`spec.kubernetes.ttl: requires CoreDNS 1.1.2 or later for "kubernetes ttl", but the CoreDNS image is version 1.0.4`,
+				`spec.kubernetes.endpointPodNames: requires CoreDNS 1.0.5 or later`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{
+			Kubernetes: tc.spec,
+			Pods:       operatorv1.DNSPods{Mode: tc.pods},
+		}}
+		err := validateDNSKubernetes(dns, coreDNSImage{version: version.MustParse(tc.version)})
+		if len(tc.expectErrs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.description, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected errors %q, got none", tc.description, tc.expectErrs)
+			continue
+		}
+		for _, expect := range tc.expectErrs {
+			if !strings.Contains(err.Error(), expect) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.description, expect, err)
+			}
+		}
+	}
+}
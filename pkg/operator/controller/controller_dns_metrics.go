@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+	operatorclient "github.com/openshift/cluster-dns-operator/pkg/operator/client"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// serviceMonitorCRDName and prometheusRuleCRDName are the names of
+	// the CRDs that provide the monitoring APIs that the operator uses.
+	serviceMonitorCRDName = "servicemonitors.monitoring.coreos.com"
+	prometheusRuleCRDName = "prometheusrules.monitoring.coreos.com"
+)
+
+var (
+	// serviceMonitorGVK and prometheusRuleGVK are the kinds of the
+	// monitoring APIs that the operator uses.
+	serviceMonitorGVK = schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1",
+		Kind:    "ServiceMonitor",
+	}
+	prometheusRuleGVK = schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1",
+		Kind:    "PrometheusRule",
+	}
+)
+
+// ensureDNSMetrics ensures that CoreDNS metrics are scraped and alerted on
+// by cluster monitoring.  The metrics service and service monitor are only
+// created once the ServiceMonitor API is available, and the alerting rules
+// once the PrometheusRule API is available; the CRDs are watched so that
+// they are created as soon as the APIs become available.
+func (r *reconciler) ensureDNSMetrics(dns *operatorv1.DNS) error {
+	if err := r.ensureClusterWatch(&apiextensionsv1beta1.CustomResourceDefinition{}); err != nil {
+		return err
+	}
+	kinds, err := r.discoverMonitoringKinds()
+	if err != nil {
+		return err
+	}
+
+	if kinds[serviceMonitorGVK.Kind] {
+		if err := r.ensureDNSMetricsService(dns); err != nil {
+			return err
+		}
+		if err := r.ensureDNSServiceMonitor(dns); err != nil {
+			return err
+		}
+	}
+	if kinds[prometheusRuleGVK.Kind] {
+		if err := r.ensureDNSPrometheusRule(dns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureDNSMetricsDeleted ensures deletion of the metrics service, service
+// monitor and alerting rules of the dns.
+func (r *reconciler) ensureDNSMetricsDeleted(dns *operatorv1.DNS) error {
+	service := &corev1.Service{}
+	name := DNSMetricsServiceName(r.OperandNamespace, dns)
+	service.Name = name.Name
+	service.Namespace = name.Namespace
+	if err := r.client.Delete(context.TODO(), service); err != nil {
+		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, service, err)
+			return fmt.Errorf("failed to delete dns metrics service: %v", err)
+		}
+	} else {
+		r.logObject(service).Info("deleted dns metrics service")
+		r.recordDeleted(dns, service)
+	}
+
+	kinds, err := r.discoverMonitoringKinds()
+	if err != nil {
+		return err
+	}
+	if kinds[serviceMonitorGVK.Kind] {
+		sm := &unstructured.Unstructured{}
+		sm.SetGroupVersionKind(serviceMonitorGVK)
+		name = DNSServiceMonitorName(r.OperandNamespace, dns)
+		sm.SetName(name.Name)
+		sm.SetNamespace(name.Namespace)
+		if err := r.client.Delete(context.TODO(), sm); err != nil {
+			if !errors.IsNotFound(err) {
+				r.recordDeleteFailed(dns, sm, err)
+				return fmt.Errorf("failed to delete dns service monitor: %v", err)
+			}
+		} else {
+			r.logObject(sm).Info("deleted dns service monitor")
+			r.recordDeleted(dns, sm)
+		}
+	}
+	if kinds[prometheusRuleGVK.Kind] {
+		pr := &unstructured.Unstructured{}
+		pr.SetGroupVersionKind(prometheusRuleGVK)
+		name = DNSPrometheusRuleName(r.OperandNamespace, dns)
+		pr.SetName(name.Name)
+		pr.SetNamespace(name.Namespace)
+		if err := r.client.Delete(context.TODO(), pr); err != nil {
+			if !errors.IsNotFound(err) {
+				r.recordDeleteFailed(dns, pr, err)
+				return fmt.Errorf("failed to delete dns prometheus rule: %v", err)
+			}
+		} else {
+			r.logObject(pr).Info("deleted dns prometheus rule")
+			r.recordDeleted(dns, pr)
+		}
+	}
+	return nil
+}
+
+// discoverMonitoringKinds returns the kinds of the monitoring API that are
+// served.  The client is rebuilt when a kind first appears, because its REST
+// mapper is only populated from discovery when it is created.
+func (r *reconciler) discoverMonitoringKinds() (map[string]bool, error) {
+	groupVersion := serviceMonitorGVK.GroupVersion()
+	kinds := map[string]bool{}
+	resources, err := r.discovery.ServerResourcesForGroupVersion(groupVersion.String())
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to discover %s: %v", groupVersion, err)
+	}
+	if err == nil {
+		for _, resource := range resources.APIResources {
+			kinds[resource.Kind] = true
+		}
+	}
+
+	refresh := false
+	for _, kind := range []string{serviceMonitorGVK.Kind, prometheusRuleGVK.Kind} {
+		switch {
+		case kinds[kind] && !r.monitoringKinds[kind]:
+			refresh = true
+			r.log.WithField("kind", groupVersion.WithKind(kind).String()).Info("discovered monitoring api")
+		case !kinds[kind] && r.monitoringKinds[kind]:
+			r.log.WithField("kind", groupVersion.WithKind(kind).String()).Info("monitoring api is no longer served")
+		}
+	}
+	if refresh {
+		kubeClient, err := operatorclient.NewClient(r.KubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh kube client: %v", err)
+		}
+		r.client = kubeClient
+	}
+	r.monitoringKinds = kinds
+	return kinds, nil
+}
+
+// ensureDNSMetricsService ensures that the metrics service exists and
+// matches the desired one.
+func (r *reconciler) ensureDNSMetricsService(dns *operatorv1.DNS) error {
+	desired := desiredDNSMetricsService(r.OperandNamespace, dns)
+	current := &corev1.Service{}
+	if err := r.client.Get(context.TODO(), DNSMetricsServiceName(r.OperandNamespace, dns), current); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get dns metrics service: %v", err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return fmt.Errorf("failed to create dns metrics service %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns metrics service")
+		r.recordCreated(dns, desired)
+		return nil
+	}
+	if cmp.Equal(current.Spec.Selector, desired.Spec.Selector) && cmp.Equal(current.Spec.Ports, desired.Spec.Ports) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Spec.Selector = desired.Spec.Selector
+	updated.Spec.Ports = desired.Spec.Ports
+	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(dns, updated, err)
+		return fmt.Errorf("failed to update dns metrics service %s/%s: %v", updated.Namespace, updated.Name, err)
+	}
+	recordDriftCorrection("service")
+	r.logObject(updated).Info("updated dns metrics service")
+	r.recordUpdated(dns, updated)
+	return nil
+}
+
+// ensureDNSServiceMonitor ensures that the service monitor exists and
+// matches the desired one.
+func (r *reconciler) ensureDNSServiceMonitor(dns *operatorv1.DNS) error {
+	desired, err := desiredDNSServiceMonitor(r.OperandNamespace, dns)
+	if err != nil {
+		return fmt.Errorf("failed to build dns service monitor: %v", err)
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(serviceMonitorGVK)
+	if err := r.client.Get(context.TODO(), DNSServiceMonitorName(r.OperandNamespace, dns), current); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get dns service monitor: %v", err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return fmt.Errorf("failed to create dns service monitor %s/%s: %v", desired.GetNamespace(), desired.GetName(), err)
+		}
+		r.logObject(desired).Info("created dns service monitor")
+		r.recordCreated(dns, desired)
+		return nil
+	}
+	if cmp.Equal(current.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Object["spec"] = desired.Object["spec"]
+	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(dns, updated, err)
+		return fmt.Errorf("failed to update dns service monitor %s/%s: %v", updated.GetNamespace(), updated.GetName(), err)
+	}
+	recordDriftCorrection("servicemonitor")
+	r.logObject(updated).Info("updated dns service monitor")
+	r.recordUpdated(dns, updated)
+	return nil
+}
+
+// desiredDNSMetricsService returns the desired metrics service, which selects
+// the CoreDNS pods of either topology.
+func desiredDNSMetricsService(namespace string, dns *operatorv1.DNS) *corev1.Service {
+	s := manifests.MetricsService()
+
+	name := DNSMetricsServiceName(namespace, dns)
+	s.Namespace = name.Namespace
+	s.Name = name.Name
+	s.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+
+	s.Labels = map[string]string{
+		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
+	}
+
+	s.Spec.Selector = DNSDaemonSetPodSelector(dns).MatchLabels
+	return s
+}
+
+// desiredDNSServiceMonitor returns the desired service monitor, which
+// selects the metrics service.
+func desiredDNSServiceMonitor(namespace string, dns *operatorv1.DNS) (*unstructured.Unstructured, error) {
+	sm := manifests.MetricsServiceMonitor()
+
+	name := DNSServiceMonitorName(namespace, dns)
+	sm.SetNamespace(name.Namespace)
+	sm.SetName(name.Name)
+	sm.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+	if err := unstructured.SetNestedStringSlice(sm.Object, []string{name.Namespace}, "spec", "namespaceSelector", "matchNames"); err != nil {
+		return nil, fmt.Errorf("failed to set namespace selector: %v", err)
+	}
+
+	labels := map[string]string{
+		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
+	}
+	sm.SetLabels(labels)
+
+	// Select the metrics service by its labels; the dns service has the
+	// same labels but no metrics port, so it contributes no endpoints.
+	matchLabels := map[string]interface{}{}
+	for k, v := range labels {
+		matchLabels[k] = v
+	}
+	if err := unstructured.SetNestedField(sm.Object, matchLabels, "spec", "selector", "matchLabels"); err != nil {
+		return nil, fmt.Errorf("failed to set selector: %v", err)
+	}
+	return sm, nil
+}
+
+// monitoringCRDPredicate filters out events for CRDs other than the ones
+// that provide the monitoring APIs.
+func monitoringCRDPredicate() predicate.Predicate {
+	isMonitoringCRD := func(meta metav1.Object) bool {
+		return meta.GetName() == serviceMonitorCRDName || meta.GetName() == prometheusRuleCRDName
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isMonitoringCRD(e.Meta) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isMonitoringCRD(e.Meta) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isMonitoringCRD(e.MetaNew) },
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestDesiredDNSMetricsService(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+	}
+	service := desiredDNSMetricsService("openshift-dns", dns)
+	if expected := DNSMetricsServiceName("openshift-dns", dns); service.Namespace != expected.Namespace || service.Name != expected.Name {
+		t.Errorf("expected name %s, got %s/%s", expected, service.Namespace, service.Name)
+	}
+	for k, v := range DNSDaemonSetPodSelector(dns).MatchLabels {
+		if service.Spec.Selector[k] != v {
+			t.Errorf("expected selector to have %s=%s, got %v", k, v, service.Spec.Selector)
+		}
+	}
+	if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].Name != "metrics" || service.Spec.Ports[0].Port != 9153 {
+		t.Errorf("expected a single metrics port 9153, got %v", service.Spec.Ports)
+	}
+	if len(service.OwnerReferences) != 1 || service.OwnerReferences[0].Name != dns.Name {
+		t.Errorf("expected owner reference to dns %s, got %v", dns.Name, service.OwnerReferences)
+	}
+}
+
+func TestDesiredDNSServiceMonitor(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+	}
+	sm, err := desiredDNSServiceMonitor("openshift-dns", dns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sm.GroupVersionKind() != serviceMonitorGVK {
+		t.Errorf("expected kind %s, got %s", serviceMonitorGVK, sm.GroupVersionKind())
+	}
+	if expected := DNSServiceMonitorName("openshift-dns", dns); sm.GetNamespace() != expected.Namespace || sm.GetName() != expected.Name {
+		t.Errorf("expected name %s, got %s/%s", expected, sm.GetNamespace(), sm.GetName())
+	}
+
+	// The service monitor must select the metrics service.
+	service := desiredDNSMetricsService("openshift-dns", dns)
+	matchLabels, found, err := unstructured.NestedStringMap(sm.Object, "spec", "selector", "matchLabels")
+	if err != nil || !found || len(matchLabels) == 0 {
+		t.Fatalf("expected spec.selector.matchLabels, got %v (found: %t, err: %v)", matchLabels, found, err)
+	}
+	for k, v := range matchLabels {
+		if service.Labels[k] != v {
+			t.Errorf("expected metrics service to have label %s=%s, got %v", k, v, service.Labels)
+		}
+	}
+
+	endpoints, _, err := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+	if err != nil || len(endpoints) != 1 {
+		t.Fatalf("expected a single endpoint, got %v (err: %v)", endpoints, err)
+	}
+	if port := endpoints[0].(map[string]interface{})["port"]; port != service.Spec.Ports[0].Name {
+		t.Errorf("expected endpoint port %q, got %v", service.Spec.Ports[0].Name, port)
+	}
+	namespaces, _, _ := unstructured.NestedStringSlice(sm.Object, "spec", "namespaceSelector", "matchNames")
+	if len(namespaces) != 1 || namespaces[0] != service.Namespace {
+		t.Errorf("expected namespace selector for %s, got %v", service.Namespace, namespaces)
+	}
+	if sm.GetLabels()[manifests.OwningDNSLabel] != DNSDaemonSetLabel(dns) {
+		t.Errorf("expected owning dns label, got %v", sm.GetLabels())
+	}
+}
+
+func TestMonitoringCRDPredicate(t *testing.T) {
+	pred := monitoringCRDPredicate()
+	testCases := []struct {
+		name   string
+		expect bool
+	}{
+		{serviceMonitorCRDName, true},
+		{prometheusRuleCRDName, true},
+		{"alertmanagers.monitoring.coreos.com", false},
+		{"dnses.operator.openshift.io", false},
+	}
+	for _, tc := range testCases {
+		crd := &apiextensionsv1beta1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: tc.name},
+		}
+		if actual := pred.Create(event.CreateEvent{Meta: crd, Object: crd}); actual != tc.expect {
+			t.Errorf("%s: expected create to return %t, got %t", tc.name, tc.expect, actual)
+		}
+		if actual := pred.Update(event.UpdateEvent{MetaOld: crd, ObjectOld: crd, MetaNew: crd, ObjectNew: crd}); actual != tc.expect {
+			t.Errorf("%s: expected update to return %t, got %t", tc.name, tc.expect, actual)
+		}
+	}
+}
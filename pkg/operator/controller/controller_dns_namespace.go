@@ -0,0 +1,404 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// endpointSliceGroup is the API group of EndpointSlices.
+	endpointSliceGroup = "discovery.k8s.io"
+)
+
+// endpointSliceGroupVersions are the versions of the EndpointSlice API that
+// CoreDNS may watch.
+var endpointSliceGroupVersions = []string{endpointSliceGroup + "/v1", endpointSliceGroup + "/v1beta1"}
+
+// ensureDNSNamespace ensures all the necessary scaffolding exists for
+// dns generally, including a namespace and all RBAC setup, and that it
+// matches the assets.  It returns descriptions of the objects that differed
+// from their assets and were updated.
+func (r *reconciler) ensureDNSNamespace() ([]string, error) {
+	var drifted []string
+	var errs []error
+	for _, ensure := range []func() (runtime.Object, error){
+		r.ensureDNSNamespaceObject,
+		r.ensureDNSClusterRole,
+		r.ensureDNSClusterRoleBinding,
+		r.ensureDNSServiceAccount,
+		r.ensureMetricsRole,
+		r.ensureMetricsRoleBinding,
+	} {
+		updated, err := ensure()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if updated != nil {
+			drifted = append(drifted, describeObject(updated))
+		}
+	}
+	return drifted, utilerrors.NewAggregate(errs)
+}
+
+// ensureDNSNamespaceObject ensures that the dns namespace exists and has
+// the labels of the asset.  It returns the namespace if it was updated.
+func (r *reconciler) ensureDNSNamespaceObject() (runtime.Object, error) {
+	desired := desiredDNSNamespace(r.OperandNamespace)
+	current := &corev1.Namespace{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name}, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get dns namespace %q: %v", desired.Name, err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(nil, desired, err)
+			return nil, fmt.Errorf("failed to create dns namespace %s: %v", desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns namespace")
+		r.recordCreated(nil, desired)
+		return nil, nil
+	}
+	changed, updated := namespaceChanged(current, desired)
+	if !changed {
+		return nil, nil
+	}
+	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(nil, updated, err)
+		return nil, fmt.Errorf("failed to update dns namespace %s: %v", updated.Name, err)
+	}
+	recordDriftCorrection("namespace")
+	r.logObject(updated).Info("updated dns namespace")
+	r.recordUpdated(nil, updated)
+	return updated, nil
+}
+
+// ensureDNSClusterRole ensures that the dns cluster role exists and has
+// the rules of the asset, and the EndpointSlice rule if the API serves
+// EndpointSlices.  It returns the cluster role if it was updated.
+func (r *reconciler) ensureDNSClusterRole() (runtime.Object, error) {
+	endpointSlices, err := r.discoverEndpointSlices()
+	if err != nil {
+		return nil, err
+	}
+	desired := desiredDNSClusterRole(endpointSlices)
+	current := &rbacv1.ClusterRole{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name}, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get dns cluster role %s: %v", desired.Name, err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(nil, desired, err)
+			return nil, fmt.Errorf("failed to create dns cluster role %s: %v", desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns cluster role")
+		r.recordCreated(nil, desired)
+		return nil, nil
+	}
+	if rulesEqual(current.Rules, desired.Rules) {
+		return nil, nil
+	}
+	updated := current.DeepCopy()
+	updated.Rules = desired.Rules
+	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(nil, updated, err)
+		return nil, fmt.Errorf("failed to update dns cluster role %s: %v", updated.Name, err)
+	}
+	recordDriftCorrection("clusterrole")
+	r.logObject(updated).Info("updated dns cluster role")
+	r.recordUpdated(nil, updated)
+	return updated, nil
+}
+
+// ensureDNSClusterRoleBinding ensures that the dns cluster role binding
+// exists and has the subjects and role of the asset.  The role of a binding
+// cannot be changed, so a binding to another role is recreated.  It returns
+// the cluster role binding if it was updated or recreated.
+func (r *reconciler) ensureDNSClusterRoleBinding() (runtime.Object, error) {
+	desired := desiredDNSClusterRoleBinding(r.OperandNamespace)
+	current := &rbacv1.ClusterRoleBinding{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name}, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get dns cluster role binding %s: %v", desired.Name, err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(nil, desired, err)
+			return nil, fmt.Errorf("failed to create dns cluster role binding %s: %v", desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns cluster role binding")
+		r.recordCreated(nil, desired)
+		return nil, nil
+	}
+	switch {
+	case !roleRefsEqual(current.RoleRef, desired.RoleRef):
+		if err := r.client.Delete(context.TODO(), current); err != nil && !errors.IsNotFound(err) {
+			r.recordDeleteFailed(nil, current, err)
+			return nil, fmt.Errorf("failed to delete dns cluster role binding %s: %v", current.Name, err)
+		}
+		r.recordDeleted(nil, current)
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(nil, desired, err)
+			return nil, fmt.Errorf("failed to recreate dns cluster role binding %s: %v", desired.Name, err)
+		}
+		recordDriftCorrection("clusterrolebinding")
+		r.logObject(desired).Info("recreated dns cluster role binding")
+		r.recordCreated(nil, desired)
+		return desired, nil
+	case !subjectsEqual(current.Subjects, desired.Subjects):
+		updated := current.DeepCopy()
+		updated.Subjects = desired.Subjects
+		if err := r.client.Update(context.TODO(), updated); err != nil {
+			r.recordUpdateFailed(nil, updated, err)
+			return nil, fmt.Errorf("failed to update dns cluster role binding %s: %v", updated.Name, err)
+		}
+		recordDriftCorrection("clusterrolebinding")
+		r.logObject(updated).Info("updated dns cluster role binding")
+		r.recordUpdated(nil, updated)
+		return updated, nil
+	}
+	return nil, nil
+}
+
+// ensureDNSServiceAccount ensures that the dns service account exists.  The
+// service account has nothing to reconcile once it exists, so it never
+// returns an object.
+func (r *reconciler) ensureDNSServiceAccount() (runtime.Object, error) {
+	sa := desiredDNSServiceAccount(r.OperandNamespace)
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: sa.Namespace, Name: sa.Name}, sa); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get dns service account %s/%s: %v", sa.Namespace, sa.Name, err)
+		}
+		if err := r.client.Create(context.TODO(), sa); err != nil {
+			r.recordCreateFailed(nil, sa, err)
+			return nil, fmt.Errorf("failed to create dns service account %s/%s: %v", sa.Namespace, sa.Name, err)
+		}
+		r.logObject(sa).Info("created dns service account")
+		r.recordCreated(nil, sa)
+	}
+	return nil, nil
+}
+
+// ensureMetricsRole ensures that the dns metrics role exists and has the
+// rules of the asset.  It returns the role if it was updated.
+func (r *reconciler) ensureMetricsRole() (runtime.Object, error) {
+	desired := desiredMetricsRole(r.OperandNamespace)
+	current := &rbacv1.Role{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get dns metrics role %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(nil, desired, err)
+			return nil, fmt.Errorf("failed to create dns metrics role %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns metrics role")
+		r.recordCreated(nil, desired)
+		return nil, nil
+	}
+	if rulesEqual(current.Rules, desired.Rules) {
+		return nil, nil
+	}
+	updated := current.DeepCopy()
+	updated.Rules = desired.Rules
+	if err := r.client.Update(context.TODO(), updated); err != nil {
+		r.recordUpdateFailed(nil, updated, err)
+		return nil, fmt.Errorf("failed to update dns metrics role %s/%s: %v", updated.Namespace, updated.Name, err)
+	}
+	recordDriftCorrection("role")
+	r.logObject(updated).Info("updated dns metrics role")
+	r.recordUpdated(nil, updated)
+	return updated, nil
+}
+
+// ensureMetricsRoleBinding ensures that the dns metrics role binding exists
+// and has the subjects and role of the asset.  A binding to another role is
+// recreated.  It returns the role binding if it was updated or recreated.
+func (r *reconciler) ensureMetricsRoleBinding() (runtime.Object, error) {
+	desired := desiredMetricsRoleBinding(r.OperandNamespace)
+	current := &rbacv1.RoleBinding{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get dns metrics role binding %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(nil, desired, err)
+			return nil, fmt.Errorf("failed to create dns metrics role binding %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns metrics role binding")
+		r.recordCreated(nil, desired)
+		return nil, nil
+	}
+	switch {
+	case !roleRefsEqual(current.RoleRef, desired.RoleRef):
+		if err := r.client.Delete(context.TODO(), current); err != nil && !errors.IsNotFound(err) {
+			r.recordDeleteFailed(nil, current, err)
+			return nil, fmt.Errorf("failed to delete dns metrics role binding %s/%s: %v", current.Namespace, current.Name, err)
+		}
+		r.recordDeleted(nil, current)
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(nil, desired, err)
+			return nil, fmt.Errorf("failed to recreate dns metrics role binding %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		recordDriftCorrection("rolebinding")
+		r.logObject(desired).Info("recreated dns metrics role binding")
+		r.recordCreated(nil, desired)
+		return desired, nil
+	case !subjectsEqual(current.Subjects, desired.Subjects):
+		updated := current.DeepCopy()
+		updated.Subjects = desired.Subjects
+		if err := r.client.Update(context.TODO(), updated); err != nil {
+			r.recordUpdateFailed(nil, updated, err)
+			return nil, fmt.Errorf("failed to update dns metrics role binding %s/%s: %v", updated.Namespace, updated.Name, err)
+		}
+		recordDriftCorrection("rolebinding")
+		r.logObject(updated).Info("updated dns metrics role binding")
+		r.recordUpdated(nil, updated)
+		return updated, nil
+	}
+	return nil, nil
+}
+
+// desiredDNSNamespace returns the desired namespace of the dns resources.
+func desiredDNSNamespace(namespace string) *corev1.Namespace {
+	ns := manifests.DNSNamespace()
+	ns.Name = namespace
+	return ns
+}
+
+// discoverEndpointSlices returns true if the API serves EndpointSlices.
+func (r *reconciler) discoverEndpointSlices() (bool, error) {
+	served := false
+	for _, groupVersion := range endpointSliceGroupVersions {
+		resources, err := r.discovery.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("failed to discover %s: %v", groupVersion, err)
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == "endpointslices" {
+				served = true
+			}
+		}
+	}
+	switch {
+	case served && !r.endpointSlicesServed:
+		r.log.Info("discovered endpointslice api")
+	case !served && r.endpointSlicesServed:
+		r.log.Info("endpointslice api is no longer served")
+	}
+	r.endpointSlicesServed = served
+	return served, nil
+}
+
+// desiredDNSClusterRole returns the desired dns cluster role.  CoreDNS
+// watches EndpointSlices instead of Endpoints if its version supports them
+// and the API serves them, so the role allows watching EndpointSlices
+// whenever they are served; otherwise, upgrading the CoreDNS image would
+// break service discovery until the role was changed.
+func desiredDNSClusterRole(endpointSlices bool) *rbacv1.ClusterRole {
+	cr := manifests.DNSClusterRole()
+	if endpointSlices {
+		cr.Rules = append(cr.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{endpointSliceGroup},
+			Resources: []string{"endpointslices"},
+			Verbs:     []string{"list", "watch"},
+		})
+	}
+	return cr
+}
+
+// desiredDNSClusterRoleBinding returns the desired binding of the dns
+// cluster role to the dns service account.
+func desiredDNSClusterRoleBinding(namespace string) *rbacv1.ClusterRoleBinding {
+	crb := manifests.DNSClusterRoleBinding()
+	for i := range crb.Subjects {
+		crb.Subjects[i].Namespace = namespace
+	}
+	return crb
+}
+
+// desiredDNSServiceAccount returns the desired dns service account.
+func desiredDNSServiceAccount(namespace string) *corev1.ServiceAccount {
+	sa := manifests.DNSServiceAccount()
+	sa.Namespace = namespace
+	return sa
+}
+
+// desiredMetricsRole returns the desired role that allows prometheus to
+// discover the metrics endpoints in the dns namespace.
+func desiredMetricsRole(namespace string) *rbacv1.Role {
+	mr := manifests.MetricsRole()
+	mr.Namespace = namespace
+	return mr
+}
+
+// desiredMetricsRoleBinding returns the desired binding of the metrics
+// role to prometheus.
+func desiredMetricsRoleBinding(namespace string) *rbacv1.RoleBinding {
+	mrb := manifests.MetricsRoleBinding()
+	mrb.Namespace = namespace
+	return mrb
+}
+
+// namespaceChanged checks whether the current namespace lacks a label of
+// the desired namespace or has another value for it and, if so, returns an
+// updated namespace with the desired labels.  Labels that only the current
+// namespace has, such as those added by other components, are kept.
+func namespaceChanged(current, desired *corev1.Namespace) (bool, *corev1.Namespace) {
+	changed := false
+	for k, v := range desired.Labels {
+		if current.Labels[k] != v {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	updated := current.DeepCopy()
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		updated.Labels[k] = v
+	}
+	return true, updated
+}
+
+// rulesEqual returns true if the given policy rules are the same, ignoring
+// the difference between empty and nil lists.
+func rulesEqual(a, b []rbacv1.PolicyRule) bool {
+	return cmp.Equal(a, b, cmpopts.EquateEmpty())
+}
+
+// roleRefsEqual returns true if the given role references are the same.
+// The API server defaults an empty API group to the RBAC API group.
+func roleRefsEqual(a, b rbacv1.RoleRef) bool {
+	for _, ref := range []*rbacv1.RoleRef{&a, &b} {
+		if len(ref.APIGroup) == 0 {
+			ref.APIGroup = rbacv1.GroupName
+		}
+	}
+	return a == b
+}
+
+// subjectsEqual returns true if the given subjects are the same, in any
+// order.
+func subjectsEqual(a, b []rbacv1.Subject) bool {
+	return cmp.Equal(a, b, cmpopts.EquateEmpty(), cmpopts.SortSlices(func(x, y rbacv1.Subject) bool {
+		return x.Kind+"/"+x.Namespace+"/"+x.Name < y.Kind+"/"+y.Namespace+"/"+y.Name
+	}))
+}
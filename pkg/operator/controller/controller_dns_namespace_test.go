@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// fakeDiscovery serves the resources of the given group versions.  Methods
+// other than ServerResourcesForGroupVersion are not implemented.
+type fakeDiscovery struct {
+	discovery.DiscoveryInterface
+	resources map[string][]metav1.APIResource
+}
+
+func (d *fakeDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	resources, ok := d.resources[groupVersion]
+	if !ok {
+		gv, _ := schema.ParseGroupVersion(groupVersion)
+		return nil, errors.NewNotFound(gv.WithResource("").GroupResource(), "")
+	}
+	return &metav1.APIResourceList{GroupVersion: groupVersion, APIResources: resources}, nil
+}
+
+func TestNamespaceChanged(t *testing.T) {
+	desired := desiredDNSNamespace("openshift-dns")
+	testCases := []struct {
+		description string
+		labels      map[string]string
+		expect      bool
+	}{
+		{
+			description: "no labels",
+			expect:      true,
+		},
+		{
+			description: "run-level removed",
+			labels:      map[string]string{"openshift.io/cluster-monitoring": "true"},
+			expect:      true,
+		},
+		{
+			description: "run-level changed",
+			labels:      map[string]string{"openshift.io/run-level": "1", "openshift.io/cluster-monitoring": "true"},
+			expect:      true,
+		},
+		{
+			description: "extra label",
+			labels:      map[string]string{"openshift.io/run-level": "0", "openshift.io/cluster-monitoring": "true", "team": "network"},
+			expect:      false,
+		},
+	}
+	for _, tc := range testCases {
+		current := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Labels: tc.labels}}
+		changed, updated := namespaceChanged(current, desired)
+		if changed != tc.expect {
+			t.Errorf("%s: expected namespaceChanged to be %t, got %t", tc.description, tc.expect, changed)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		for k, v := range desired.Labels {
+			if updated.Labels[k] != v {
+				t.Errorf("%s: expected label %s=%s, got %q", tc.description, k, v, updated.Labels[k])
+			}
+		}
+		for k, v := range tc.labels {
+			if _, ok := desired.Labels[k]; !ok && updated.Labels[k] != v {
+				t.Errorf("%s: expected extra label %s=%s to be kept, got %q", tc.description, k, v, updated.Labels[k])
+			}
+		}
+		if changed, _ := namespaceChanged(updated, desired); changed {
+			t.Errorf("%s: expected the updated namespace to be unchanged", tc.description)
+		}
+	}
+}
+
+func TestRoleRefsEqual(t *testing.T) {
+	desired := desiredDNSClusterRoleBinding("openshift-dns").RoleRef
+	defaulted := desired
+	defaulted.APIGroup = rbacv1.GroupName
+	if !roleRefsEqual(defaulted, desired) {
+		t.Errorf("expected role ref %+v with a defaulted API group to equal %+v", defaulted, desired)
+	}
+	other := defaulted
+	other.Name = "cluster-admin"
+	if roleRefsEqual(other, desired) {
+		t.Errorf("expected role ref %+v to differ from %+v", other, desired)
+	}
+}
+
+func TestSubjectsEqual(t *testing.T) {
+	a := []rbacv1.Subject{
+		{Kind: "ServiceAccount", Namespace: "openshift-dns", Name: "dns"},
+		{Kind: "ServiceAccount", Namespace: "openshift-dns", Name: "node-resolver"},
+	}
+	reordered := []rbacv1.Subject{a[1], a[0]}
+	if !subjectsEqual(a, reordered) {
+		t.Errorf("expected reordered subjects to be equal")
+	}
+	if subjectsEqual(a, a[:1]) {
+		t.Errorf("expected a removed subject to differ")
+	}
+	moved := []rbacv1.Subject{a[0], a[1]}
+	moved[0].Namespace = "dns"
+	if subjectsEqual(a, moved) {
+		t.Errorf("expected a subject in another namespace to differ")
+	}
+}
+
+func TestRulesEqual(t *testing.T) {
+	desired := desiredMetricsRole("openshift-dns").Rules
+	current := make([]rbacv1.PolicyRule, len(desired))
+	for i := range desired {
+		desired[i].DeepCopyInto(&current[i])
+	}
+	if !rulesEqual(current, desired) {
+		t.Errorf("expected copied rules to be equal")
+	}
+	current[0].Resources = current[0].Resources[1:]
+	if rulesEqual(current, desired) {
+		t.Errorf("expected rules with a removed resource to differ")
+	}
+}
+
+func TestComputeStatusConditionsScaffolding(t *testing.T) {
+	testCases := []struct {
+		description       string
+		err               error
+		drift             *scaffoldingDrift
+		expectFailing     configv1.ConditionStatus
+		expectProgressing configv1.ConditionStatus
+		expectMessage     string
+	}{
+		{
+			description:       "no drift",
+			expectFailing:     configv1.ConditionFalse,
+			expectProgressing: configv1.ConditionFalse,
+		},
+		{
+			description:       "failed to correct drift",
+			err:               fmt.Errorf("forbidden"),
+			expectFailing:     configv1.ConditionTrue,
+			expectProgressing: configv1.ConditionFalse,
+			expectMessage:     "failed to ensure DNS namespace and RBAC: forbidden",
+		},
+		{
+			description:       "corrected drift",
+			drift:             &scaffoldingDrift{described: "namespace openshift-dns", corrected: time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)},
+			expectFailing:     configv1.ConditionFalse,
+			expectProgressing: configv1.ConditionFalse,
+			expectMessage:     "corrected drift of namespace openshift-dns from its manifest at 2019-03-01T12:00:00Z",
+		},
+		{
+			description:       "failed to correct drift after an earlier correction",
+			err:               fmt.Errorf("forbidden"),
+			drift:             &scaffoldingDrift{described: "namespace openshift-dns", corrected: time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)},
+			expectFailing:     configv1.ConditionTrue,
+			expectProgressing: configv1.ConditionFalse,
+			expectMessage:     "failed to ensure DNS namespace and RBAC: forbidden",
+		},
+	}
+	for _, tc := range testCases {
+		conditions := computeStatusConditions(nil, &corev1.Namespace{}, tc.err, tc.drift, nil, nil, nil, nil)
+		var messages []string
+		for _, c := range conditions {
+			switch c.Type {
+			case configv1.OperatorFailing:
+				if c.Status != tc.expectFailing {
+					t.Errorf("%s: expected Failing=%s, got %s", tc.description, tc.expectFailing, c.Status)
+				}
+			case configv1.OperatorProgressing:
+				if c.Status != tc.expectProgressing {
+					t.Errorf("%s: expected Progressing=%s, got %s", tc.description, tc.expectProgressing, c.Status)
+				}
+			}
+			messages = append(messages, c.Message)
+		}
+		if !strings.Contains(strings.Join(messages, "\n"), tc.expectMessage) {
+			t.Errorf("%s: expected a condition with message %q, got %q", tc.description, tc.expectMessage, messages)
+		}
+	}
+}
+
+func TestDesiredDNSClusterRoleEndpointSlices(t *testing.T) {
+	testCases := []struct {
+		description string
+		resources   map[string][]metav1.APIResource
+		expect      bool
+	}{
+		{
+			description: "not served",
+			resources:   map[string][]metav1.APIResource{},
+			expect:      false,
+		},
+		{
+			description: "served as v1beta1",
+			resources: map[string][]metav1.APIResource{
+				"discovery.k8s.io/v1beta1": {{Name: "endpointslices", Kind: "EndpointSlice"}},
+			},
+			expect: true,
+		},
+		{
+			description: "served as v1",
+			resources: map[string][]metav1.APIResource{
+				"discovery.k8s.io/v1": {{Name: "endpointslices", Kind: "EndpointSlice"}},
+			},
+			expect: true,
+		},
+	}
+	for _, tc := range testCases {
+		r := &reconciler{
+			discovery: &fakeDiscovery{resources: tc.resources},
+			log:       logrus.NewEntry(logrus.New()),
+		}
+		served, err := r.discoverEndpointSlices()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if served != tc.expect {
+			t.Errorf("%s: expected endpointslices served to be %t, got %t", tc.description, tc.expect, served)
+		}
+
+		found := false
+		for _, rule := range desiredDNSClusterRole(served).Rules {
+			for _, resource := range rule.Resources {
+				if resource == "endpointslices" && len(rule.APIGroups) == 1 && rule.APIGroups[0] == endpointSliceGroup {
+					found = true
+				}
+			}
+		}
+		if found != tc.expect {
+			t.Errorf("%s: expected the cluster role to allow endpointslices to be %t, got %t", tc.description, tc.expect, found)
+		}
+	}
+}
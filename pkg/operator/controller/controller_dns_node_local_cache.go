@@ -0,0 +1,362 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultNodeLocalCacheAddress is the address on which the node-local
+	// cache listens when the dns does not specify one.
+	defaultNodeLocalCacheAddress = "169.254.20.10"
+)
+
+// linkLocalNetwork is the IPv4 link-local network.
+var linkLocalNetwork = &net.IPNet{IP: net.IPv4(169, 254, 0, 0), Mask: net.CIDRMask(16, 32)}
+
+// ensureNodeLocalCache ensures that the node-local cache runs if, and only
+// if, the dns enables it.  The node-local cache daemonset is returned when it
+// runs.
+func (r *reconciler) ensureNodeLocalCache(dns *operatorv1.DNS, clusterIP, clusterDomain string) (*appsv1.DaemonSet, error) {
+	if !dns.Spec.NodeLocalCache.Enabled {
+		return nil, r.ensureNodeLocalCacheDeleted(dns)
+	}
+
+	localAddress, err := nodeLocalCacheAddress(dns)
+	if err != nil {
+		return nil, err
+	}
+	upstream, err := r.ensureNodeLocalCacheUpstreamService(dns)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ensureNodeLocalCacheConfigMap(dns, clusterIP, upstream.Spec.ClusterIP, clusterDomain, localAddress); err != nil {
+		return nil, err
+	}
+
+	desired := desiredNodeLocalCacheDaemonSet(r.OperandNamespace, dns, localAddress, clusterIP, r.CoreDNSImage, r.OpenshiftCLIImage)
+	current := &appsv1.DaemonSet{}
+	if err := r.client.Get(context.TODO(), NodeLocalCacheDaemonSetName(r.OperandNamespace, dns), current); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get node-local cache daemonset: %v", err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return nil, fmt.Errorf("failed to create node-local cache daemonset %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created node-local cache daemonset")
+		r.recordCreated(dns, desired)
+		return desired, nil
+	}
+	if changed, updated := nodeLocalCacheDaemonSetChanged(current, desired); changed {
+		if err := r.client.Update(context.TODO(), updated); err != nil {
+			r.recordUpdateFailed(dns, updated, err)
+			return nil, fmt.Errorf("failed to update node-local cache daemonset %s/%s: %v", updated.Namespace, updated.Name, err)
+		}
+		recordDriftCorrection("daemonset")
+		r.logObject(updated).Info("updated node-local cache daemonset")
+		r.recordUpdated(dns, updated)
+		return updated, nil
+	}
+	return current, nil
+}
+
+// ensureNodeLocalCacheDeleted ensures deletion of the node-local cache
+// daemonset, configmap and upstream service.  Stopping its pods removes the
+// dummy interface and the rules with which the cache intercepts queries on
+// every node.
+func (r *reconciler) ensureNodeLocalCacheDeleted(dns *operatorv1.DNS) error {
+	daemonset := &appsv1.DaemonSet{}
+	name := NodeLocalCacheDaemonSetName(r.OperandNamespace, dns)
+	daemonset.Name = name.Name
+	daemonset.Namespace = name.Namespace
+	if err := r.client.Delete(context.TODO(), daemonset); err != nil {
+		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, daemonset, err)
+			return fmt.Errorf("failed to delete node-local cache daemonset: %v", err)
+		}
+	} else {
+		r.logObject(daemonset).Info("deleted node-local cache daemonset")
+		r.recordDeleted(dns, daemonset)
+	}
+
+	cm := &corev1.ConfigMap{}
+	name = NodeLocalCacheConfigMapName(r.OperandNamespace, dns)
+	cm.Name = name.Name
+	cm.Namespace = name.Namespace
+	if err := r.client.Delete(context.TODO(), cm); err != nil {
+		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, cm, err)
+			return fmt.Errorf("failed to delete node-local cache configmap: %v", err)
+		}
+	} else {
+		r.logObject(cm).Info("deleted node-local cache configmap")
+		r.recordDeleted(dns, cm)
+	}
+
+	service := &corev1.Service{}
+	name = NodeLocalCacheUpstreamServiceName(r.OperandNamespace, dns)
+	service.Name = name.Name
+	service.Namespace = name.Namespace
+	if err := r.client.Delete(context.TODO(), service); err != nil {
+		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, service, err)
+			return fmt.Errorf("failed to delete node-local cache upstream service: %v", err)
+		}
+	} else {
+		r.logObject(service).Info("deleted node-local cache upstream service")
+		r.recordDeleted(dns, service)
+	}
+	return nil
+}
+
+// ensureNodeLocalCacheUpstreamService ensures that the service through which
+// the node-local cache forwards to CoreDNS exists, and returns it with its
+// cluster IP.
+func (r *reconciler) ensureNodeLocalCacheUpstreamService(dns *operatorv1.DNS) (*corev1.Service, error) {
+	current := &corev1.Service{}
+	if err := r.client.Get(context.TODO(), NodeLocalCacheUpstreamServiceName(r.OperandNamespace, dns), current); err == nil {
+		return current, nil
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get node-local cache upstream service: %v", err)
+	}
+	desired := desiredNodeLocalCacheUpstreamService(r.OperandNamespace, dns)
+	if err := r.client.Create(context.TODO(), desired); err != nil {
+		r.recordCreateFailed(dns, desired, err)
+		return nil, fmt.Errorf("failed to create node-local cache upstream service %s/%s: %v", desired.Namespace, desired.Name, err)
+	}
+	r.logObject(desired).Info("created node-local cache upstream service")
+	r.recordCreated(dns, desired)
+	return desired, nil
+}
+
+// ensureNodeLocalCacheConfigMap ensures that the node-local cache configmap
+// exists and has the desired Corefile.
+func (r *reconciler) ensureNodeLocalCacheConfigMap(dns *operatorv1.DNS, clusterIP, upstreamIP, clusterDomain, localAddress string) error {
+	desired, err := desiredNodeLocalCacheConfigMap(r.OperandNamespace, dns, clusterIP, upstreamIP, clusterDomain, localAddress)
+	if err != nil {
+		return fmt.Errorf("failed to build node-local cache configmap: %v", err)
+	}
+	current := &corev1.ConfigMap{}
+	if err := r.client.Get(context.TODO(), NodeLocalCacheConfigMapName(r.OperandNamespace, dns), current); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get node-local cache configmap: %v", err)
+		}
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return fmt.Errorf("failed to create node-local cache configmap %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created node-local cache configmap")
+		r.recordCreated(dns, desired)
+		return nil
+	}
+	if changed, updated := corefileChanged(current, desired); changed {
+		if err := r.client.Update(context.TODO(), updated); err != nil {
+			r.recordUpdateFailed(dns, updated, err)
+			return fmt.Errorf("failed to update node-local cache configmap %s/%s: %v", updated.Namespace, updated.Name, err)
+		}
+		recordDriftCorrection("configmap")
+		r.logObject(updated).Info("updated node-local cache configmap")
+		r.recordUpdated(dns, updated)
+	}
+	return nil
+}
+
+// nodeLocalCacheAddress returns the address on which the node-local cache
+// of the given dns listens.
+func nodeLocalCacheAddress(dns *operatorv1.DNS) (string, error) {
+	address := dns.Spec.NodeLocalCache.LocalAddress
+	if len(address) == 0 {
+		return defaultNodeLocalCacheAddress, nil
+	}
+	ip := net.ParseIP(address)
+	if ip == nil || ip.To4() == nil || !linkLocalNetwork.Contains(ip) {
+		return "", fmt.Errorf("invalid localAddress %q: must be an IPv4 address in %s", address, linkLocalNetwork)
+	}
+	return ip.String(), nil
+}
+
+// desiredNodeLocalCacheUpstreamService returns the desired service through
+// which the node-local cache forwards to CoreDNS.  It selects the CoreDNS pods
+// like the dns service, and its cluster IP is allocated by the cluster.
+func desiredNodeLocalCacheUpstreamService(namespace string, dns *operatorv1.DNS) *corev1.Service {
+	s := manifests.DNSService()
+
+	name := NodeLocalCacheUpstreamServiceName(namespace, dns)
+	s.Namespace = name.Namespace
+	s.Name = name.Name
+	s.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+
+	s.Labels = map[string]string{
+		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
+	}
+
+	s.Spec.Selector = DNSDaemonSetPodSelector(dns).MatchLabels
+	return s
+}
+
+// desiredNodeLocalCacheConfigMap returns the desired node-local cache
+// configmap.
+func desiredNodeLocalCacheConfigMap(namespace string, dns *operatorv1.DNS, clusterIP, upstreamIP, clusterDomain, localAddress string) (*corev1.ConfigMap, error) {
+	cm := manifests.NodeLocalCacheConfigMap()
+
+	name := NodeLocalCacheConfigMapName(namespace, dns)
+	cm.Namespace = name.Namespace
+	cm.Name = name.Name
+	cm.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+
+	cm.Labels = map[string]string{
+		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
+	}
+
+	corefile, err := renderCorefile(cm.Data["Corefile"], corefileParameters{
+		ClusterDomain: clusterDomain,
+		ClusterIP:     clusterIP,
+		UpstreamIP:    upstreamIP,
+		LocalAddress:  localAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cm.Data["Corefile"] = corefile
+	return cm, nil
+}
+
+// desiredNodeLocalCacheDaemonSet returns the desired node-local cache
+// daemonset, which intercepts queries to localAddress and clusterIP on every
+// node.
+func desiredNodeLocalCacheDaemonSet(namespace string, dns *operatorv1.DNS, localAddress, clusterIP, coreDNSImage, openshiftCLIImage string) *appsv1.DaemonSet {
+	daemonset := manifests.NodeLocalCacheDaemonSet()
+	name := NodeLocalCacheDaemonSetName(namespace, dns)
+	daemonset.Name = name.Name
+	daemonset.Namespace = name.Namespace
+	daemonset.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+
+	daemonset.Labels = map[string]string{
+		// associate the daemonset with the dns
+		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
+	}
+
+	daemonset.Spec.Selector = NodeLocalCacheDaemonSetPodSelector(dns)
+	daemonset.Spec.Template.Labels = daemonset.Spec.Selector.MatchLabels
+
+	spec := &daemonset.Spec.Template.Spec
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == "config-volume" {
+			spec.Volumes[i].ConfigMap.Name = NodeLocalCacheConfigMapName(namespace, dns).Name
+		}
+	}
+	env := []corev1.EnvVar{
+		{Name: "LOCAL_ADDRESS", Value: localAddress},
+		{Name: "CLUSTER_IP", Value: clusterIP},
+	}
+	for i, c := range spec.InitContainers {
+		switch c.Name {
+		case "setup-interface":
+			spec.InitContainers[i].Image = openshiftCLIImage
+			spec.InitContainers[i].Env = append(spec.InitContainers[i].Env, env...)
+		}
+	}
+	for i, c := range spec.Containers {
+		switch c.Name {
+		case "node-local-dns":
+			spec.Containers[i].Image = coreDNSImage
+			if probe := spec.Containers[i].LivenessProbe; probe != nil && probe.HTTPGet != nil {
+				probe.HTTPGet.Host = localAddress
+			}
+		case "teardown-interface":
+			spec.Containers[i].Image = openshiftCLIImage
+			spec.Containers[i].Env = append(spec.Containers[i].Env, env...)
+		}
+	}
+	return daemonset
+}
+
+// nodeLocalCacheDaemonSetChanged checks if current config matches the
+// expected config for the node-local cache daemonset and if not returns the
+// updated config.
+func nodeLocalCacheDaemonSetChanged(current, expected *appsv1.DaemonSet) (bool, *appsv1.DaemonSet) {
+	updated := current.DeepCopy()
+	what := fmt.Sprintf("daemonset %s/%s", current.Namespace, current.Name)
+	changed := podSpecChanged(what, &current.Spec.Template.Spec, &expected.Spec.Template.Spec, &updated.Spec.Template.Spec)
+
+	// The local address and the cluster IP are in the liveness probe and in
+	// the environment of the containers, which podSpecChanged does not
+	// compare.
+	for _, e := range expected.Spec.Template.Spec.Containers {
+		for i, c := range updated.Spec.Template.Spec.Containers {
+			if c.Name != e.Name {
+				continue
+			}
+			if !cmp.Equal(c.LivenessProbe, e.LivenessProbe) {
+				updated.Spec.Template.Spec.Containers[i].LivenessProbe = e.LivenessProbe
+				changed = true
+			}
+			if !cmp.Equal(c.Env, e.Env) {
+				updated.Spec.Template.Spec.Containers[i].Env = e.Env
+				changed = true
+			}
+		}
+	}
+	for _, e := range expected.Spec.Template.Spec.InitContainers {
+		found := false
+		for i, c := range updated.Spec.Template.Spec.InitContainers {
+			if c.Name != e.Name {
+				continue
+			}
+			found = true
+			if c.Image != e.Image {
+				updated.Spec.Template.Spec.InitContainers[i].Image = e.Image
+				changed = true
+			}
+			if !cmp.Equal(c.Env, e.Env) {
+				updated.Spec.Template.Spec.InitContainers[i].Env = e.Env
+				changed = true
+			}
+			if !cmp.Equal(c.Command, e.Command) {
+				updated.Spec.Template.Spec.InitContainers[i].Command = e.Command
+				changed = true
+			}
+		}
+		if !found {
+			updated.Spec.Template.Spec.InitContainers = expected.Spec.Template.Spec.InitContainers
+			changed = true
+			break
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	return true, updated
+}
+
+// nodeLocalCacheCondition returns the NodeLocalCacheAvailable condition for
+// the given node-local cache daemonset.
+func nodeLocalCacheCondition(daemonset *appsv1.DaemonSet) operatorv1.OperatorCondition {
+	desired, available := daemonset.Status.DesiredNumberScheduled, daemonset.Status.NumberAvailable
+	condition := operatorv1.OperatorCondition{
+		Type:    operatorv1.DNSNodeLocalCacheAvailable,
+		Message: fmt.Sprintf("%d of %d node-local cache pods are available", available, desired),
+	}
+	if desired > 0 && available >= desired {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "AsExpected"
+	} else {
+		condition.Status = operatorv1.ConditionFalse
+		condition.Reason = "PodsNotAvailable"
+	}
+	return condition
+}
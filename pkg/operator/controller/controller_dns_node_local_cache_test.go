@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeLocalCacheAddress(t *testing.T) {
+	testCases := []struct {
+		address  string
+		expected string
+		valid    bool
+	}{
+		{"", defaultNodeLocalCacheAddress, true},
+		{"169.254.25.10", "169.254.25.10", true},
+		{"169.254.0.256", "", false},
+		{"10.0.0.10", "", false},
+		{"fe80::10", "", false},
+	}
+
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{}
+		dns.Spec.NodeLocalCache.LocalAddress = tc.address
+		address, err := nodeLocalCacheAddress(dns)
+		if tc.valid && err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.address, err)
+		} else if !tc.valid && err == nil {
+			t.Errorf("%q: expected an error", tc.address)
+		} else if address != tc.expected {
+			t.Errorf("%q: expected address %q, got %q", tc.address, tc.expected, address)
+		}
+	}
+}
+
+func TestDesiredNodeLocalCacheConfigMap(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	expectedCorefile := `cluster.local:53 in-addr.arpa:53 ip6.arpa:53 {
+    errors
+    bind 169.254.20.10 172.30.0.10
+    health 169.254.20.10:8080
+    prometheus 169.254.20.10:9253
+    forward . 172.30.0.20 {
+        force_tcp
+    }
+    cache {
+        success 9984 30
+        denial 9984 5
+    }
+    reload
+}
+.:53 {
+    errors
+    bind 169.254.20.10 172.30.0.10
+    prometheus 169.254.20.10:9253
+    forward . /etc/resolv.conf
+    cache 30
+    reload
+}
+`
+
+	cm, err := desiredNodeLocalCacheConfigMap("openshift-dns", dns, "172.30.0.10", "172.30.0.20", "cluster.local", "169.254.20.10")
+	if err != nil {
+		t.Fatalf("invalid node-local cache configmap: %v", err)
+	}
+	if cm.Name != "node-local-dns-default" {
+		t.Errorf("expected configmap name node-local-dns-default, got %q", cm.Name)
+	}
+	if cm.Data["Corefile"] != expectedCorefile {
+		t.Errorf("unexpected Corefile;\nexpected:\n%s\ngot:\n%s", expectedCorefile, cm.Data["Corefile"])
+	}
+}
+
+func TestDesiredNodeLocalCacheDaemonSet(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	coreDNSImage := "quay.io/openshift/coredns:test"
+	openshiftCLIImage := "openshift/origin-cli:test"
+
+	ds := desiredNodeLocalCacheDaemonSet("openshift-dns", dns, "169.254.20.10", "172.30.0.10", coreDNSImage, openshiftCLIImage)
+	spec := ds.Spec.Template.Spec
+	if !spec.HostNetwork {
+		t.Errorf("expected node-local cache to use the host network")
+	}
+	if len(spec.InitContainers) != 1 {
+		t.Fatalf("expected 1 init container, got %d", len(spec.InitContainers))
+	}
+	if e, a := openshiftCLIImage, spec.InitContainers[0].Image; e != a {
+		t.Errorf("expected init container image %q, got %q", e, a)
+	}
+	expectedEnv := "[{LOCAL_ADDRESS 169.254.20.10 nil} {CLUSTER_IP 172.30.0.10 nil}]"
+	if env := spec.InitContainers[0].Env; fmt.Sprint(env) != expectedEnv {
+		t.Errorf("expected init container env %s, got %v", expectedEnv, env)
+	}
+	if len(spec.Containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(spec.Containers))
+	}
+	if e, a := coreDNSImage, spec.Containers[0].Image; e != a {
+		t.Errorf("expected container image %q, got %q", e, a)
+	}
+	if probe := spec.Containers[0].LivenessProbe; probe == nil || probe.HTTPGet == nil || probe.HTTPGet.Host != "169.254.20.10" {
+		t.Errorf("expected liveness probe against the local address, got %v", probe)
+	}
+	teardown := spec.Containers[1]
+	if teardown.Name != "teardown-interface" || teardown.Image != openshiftCLIImage {
+		t.Errorf("expected teardown-interface container with image %q, got %s with image %q", openshiftCLIImage, teardown.Name, teardown.Image)
+	}
+	if fmt.Sprint(teardown.Env) != expectedEnv {
+		t.Errorf("expected teardown container env %s, got %v", expectedEnv, teardown.Env)
+	}
+	if e, a := "node-local-dns-default", spec.Volumes[0].ConfigMap.Name; e != a {
+		t.Errorf("expected configmap %q, got %q", e, a)
+	}
+	if _, ok := ds.Spec.Template.Labels[controllerDaemonSetLabel]; ok {
+		t.Errorf("expected node-local cache pods not to be selected by the dns service, got labels %v", ds.Spec.Template.Labels)
+	}
+	if !isAuxiliaryDaemonSet(ds) {
+		t.Errorf("expected node-local cache daemonset to be recognized")
+	}
+}
+
+func TestNodeLocalCacheDaemonSetChanged(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	current := desiredNodeLocalCacheDaemonSet("openshift-dns", dns, "169.254.20.10", "172.30.0.10", "coredns:1", "cli:1")
+
+	testCases := []struct {
+		description string
+		expected    *appsv1.DaemonSet
+		changed     bool
+	}{
+		{
+			description: "no change",
+			expected:    desiredNodeLocalCacheDaemonSet("openshift-dns", dns, "169.254.20.10", "172.30.0.10", "coredns:1", "cli:1"),
+		},
+		{
+			description: "local address changed",
+			expected:    desiredNodeLocalCacheDaemonSet("openshift-dns", dns, "169.254.25.10", "172.30.0.10", "coredns:1", "cli:1"),
+			changed:     true,
+		},
+		{
+			description: "cluster IP changed",
+			expected:    desiredNodeLocalCacheDaemonSet("openshift-dns", dns, "169.254.20.10", "172.30.0.11", "coredns:1", "cli:1"),
+			changed:     true,
+		},
+		{
+			description: "cli image changed",
+			expected:    desiredNodeLocalCacheDaemonSet("openshift-dns", dns, "169.254.20.10", "172.30.0.10", "coredns:1", "cli:2"),
+			changed:     true,
+		},
+		{
+			description: "coredns image changed",
+			expected:    desiredNodeLocalCacheDaemonSet("openshift-dns", dns, "169.254.20.10", "172.30.0.10", "coredns:2", "cli:1"),
+			changed:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		changed, updated := nodeLocalCacheDaemonSetChanged(current, tc.expected)
+		if changed != tc.changed {
+			t.Errorf("%q: expected changed to be %t, got %t", tc.description, tc.changed, changed)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if changedAgain, _ := nodeLocalCacheDaemonSetChanged(updated, tc.expected); changedAgain {
+			t.Errorf("%q: expected the updated daemonset to match the expected one", tc.description)
+		}
+	}
+}
+
+func TestNodeLocalCacheCondition(t *testing.T) {
+	testCases := []struct {
+		description        string
+		desired, available int32
+		expected           operatorv1.ConditionStatus
+	}{
+		{"no nodes", 0, 0, operatorv1.ConditionFalse},
+		{"some pods unavailable", 3, 2, operatorv1.ConditionFalse},
+		{"all pods available", 3, 3, operatorv1.ConditionTrue},
+	}
+
+	for _, tc := range testCases {
+		ds := &appsv1.DaemonSet{
+			Status: appsv1.DaemonSetStatus{
+				DesiredNumberScheduled: tc.desired,
+				NumberAvailable:        tc.available,
+			},
+		}
+		condition := nodeLocalCacheCondition(ds)
+		if condition.Type != operatorv1.DNSNodeLocalCacheAvailable {
+			t.Errorf("%q: expected condition type %s, got %s", tc.description, operatorv1.DNSNodeLocalCacheAvailable, condition.Type)
+		}
+		if condition.Status != tc.expected {
+			t.Errorf("%q: expected status %s, got %s", tc.description, tc.expected, condition.Status)
+		}
+	}
+}
+
+func TestDesiredNodeLocalCacheUpstreamService(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	service := desiredNodeLocalCacheUpstreamService("openshift-dns", dns)
+	if service.Name != "node-local-dns-default-upstream" {
+		t.Errorf("expected service name node-local-dns-default-upstream, got %q", service.Name)
+	}
+	if len(service.Spec.ClusterIP) != 0 {
+		t.Errorf("expected the cluster IP to be allocated by the cluster, got %q", service.Spec.ClusterIP)
+	}
+	if e, a := DNSDaemonSetPodSelector(dns).MatchLabels, service.Spec.Selector; fmt.Sprint(e) != fmt.Sprint(a) {
+		t.Errorf("expected selector %v, got %v", e, a)
+	}
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// defaultMaxUnavailable is the number of CoreDNS pods that may be disrupted
+// at once when the dns does not specify it.
+var defaultMaxUnavailable = intstr.FromInt(1)
+
+// ensureDNSPodDisruptionBudget ensures that the pod disruption budget for
+// the CoreDNS pods of a given dns exists and matches the dns.
+func (r *reconciler) ensureDNSPodDisruptionBudget(dns *operatorv1.DNS) error {
+	// The disruption controller cannot compute maxUnavailable for pods
+	// that are owned by a daemonset, so the budget for the daemonset
+	// topology is expressed as minAvailable, computed from the number of
+	// nodes that are to run CoreDNS.
+	var daemonset *appsv1.DaemonSet
+	if dns.Spec.Topology.Mode != operatorv1.DeploymentDNSTopologyMode {
+		current, err := r.currentDNSDaemonSet(dns)
+		if err != nil {
+			return fmt.Errorf("failed to get dns daemonset: %v", err)
+		}
+		if current == nil {
+			return nil
+		}
+		daemonset = current
+	}
+	desired, err := desiredDNSPodDisruptionBudget(r.OperandNamespace, dns, daemonset)
+	if err != nil {
+		return fmt.Errorf("failed to build dns pod disruption budget: %v", err)
+	}
+
+	current, err := r.currentDNSPodDisruptionBudget(dns)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		if err := r.client.Create(context.TODO(), desired); err != nil {
+			r.recordCreateFailed(dns, desired, err)
+			return fmt.Errorf("failed to create dns pod disruption budget %s/%s: %v", desired.Namespace, desired.Name, err)
+		}
+		r.logObject(desired).Info("created dns pod disruption budget")
+		r.recordCreated(dns, desired)
+		return nil
+	}
+	if !podDisruptionBudgetChanged(current, desired) {
+		return nil
+	}
+	// The spec of a policy/v1beta1 pod disruption budget cannot be
+	// updated, so it is replaced.
+	if err := r.client.Delete(context.TODO(), current); err != nil && !errors.IsNotFound(err) {
+		r.recordDeleteFailed(dns, current, err)
+		return fmt.Errorf("failed to delete dns pod disruption budget %s/%s: %v", current.Namespace, current.Name, err)
+	}
+	recordDriftCorrection("poddisruptionbudget")
+	r.logObject(current).Info("deleted dns pod disruption budget for replacement")
+	r.recordDeleted(dns, current)
+	if err := r.client.Create(context.TODO(), desired); err != nil {
+		r.recordCreateFailed(dns, desired, err)
+		return fmt.Errorf("failed to create dns pod disruption budget %s/%s: %v", desired.Namespace, desired.Name, err)
+	}
+	r.logObject(desired).Info("created dns pod disruption budget")
+	r.recordCreated(dns, desired)
+	return nil
+}
+
+// ensureDNSPodDisruptionBudgetDeleted ensures deletion of the pod disruption
+// budget for the CoreDNS pods of a given dns.
+func (r *reconciler) ensureDNSPodDisruptionBudgetDeleted(dns *operatorv1.DNS) error {
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	name := DNSPodDisruptionBudgetName(r.OperandNamespace, dns)
+	pdb.Name = name.Name
+	pdb.Namespace = name.Namespace
+	if err := r.client.Delete(context.TODO(), pdb); err != nil {
+		if !errors.IsNotFound(err) {
+			r.recordDeleteFailed(dns, pdb, err)
+			return err
+		}
+	} else {
+		r.logObject(pdb).Info("deleted dns pod disruption budget")
+		r.recordDeleted(dns, pdb)
+	}
+	return nil
+}
+
+// currentDNSPodDisruptionBudget returns the current pod disruption budget
+// for the CoreDNS pods of a given dns.
+func (r *reconciler) currentDNSPodDisruptionBudget(dns *operatorv1.DNS) (*policyv1beta1.PodDisruptionBudget, error) {
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	if err := r.client.Get(context.TODO(), DNSPodDisruptionBudgetName(r.OperandNamespace, dns), pdb); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pdb, nil
+}
+
+// desiredDNSPodDisruptionBudget returns the desired pod disruption budget
+// for the CoreDNS pods of a given dns.  If daemonset is not nil, the budget
+// is computed as minAvailable from the number of pods that the daemonset is
+// to run.
+func desiredDNSPodDisruptionBudget(namespace string, dns *operatorv1.DNS, daemonset *appsv1.DaemonSet) (*policyv1beta1.PodDisruptionBudget, error) {
+	pdb := manifests.DNSPodDisruptionBudget()
+	name := DNSPodDisruptionBudgetName(namespace, dns)
+	pdb.Name = name.Name
+	pdb.Namespace = name.Namespace
+	pdb.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
+	pdb.Labels = map[string]string{
+		manifests.OwningDNSLabel: DNSDaemonSetLabel(dns),
+	}
+	pdb.Spec.Selector = DNSDaemonSetPodSelector(dns)
+
+	maxUnavailable := defaultMaxUnavailable
+	if dns.Spec.PodDisruptionBudget.MaxUnavailable != nil {
+		maxUnavailable = *dns.Spec.PodDisruptionBudget.MaxUnavailable
+	}
+	// Resolve against 100 pods to validate the value in any topology.
+	if n, err := intstr.GetValueFromIntOrPercent(&maxUnavailable, 100, true); err != nil {
+		return nil, fmt.Errorf("invalid maxUnavailable %q: %v", maxUnavailable.String(), err)
+	} else if n < 0 {
+		return nil, fmt.Errorf("invalid maxUnavailable %q: must not be negative", maxUnavailable.String())
+	}
+
+	if daemonset == nil {
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+		pdb.Spec.MinAvailable = nil
+		return pdb, nil
+	}
+
+	total := int(daemonset.Status.DesiredNumberScheduled)
+	unavailable, _ := intstr.GetValueFromIntOrPercent(&maxUnavailable, total, true)
+	minAvailable := total - unavailable
+	if minAvailable < 0 {
+		minAvailable = 0
+	}
+	min := intstr.FromInt(minAvailable)
+	pdb.Spec.MaxUnavailable = nil
+	pdb.Spec.MinAvailable = &min
+	return pdb, nil
+}
+
+// podDisruptionBudgetChanged returns true if the current pod disruption
+// budget does not match the expected one.
+func podDisruptionBudgetChanged(current, expected *policyv1beta1.PodDisruptionBudget) bool {
+	return !cmp.Equal(current.Spec.Selector, expected.Spec.Selector) ||
+		!cmp.Equal(current.Spec.MinAvailable, expected.Spec.MinAvailable) ||
+		!cmp.Equal(current.Spec.MaxUnavailable, expected.Spec.MaxUnavailable)
+}
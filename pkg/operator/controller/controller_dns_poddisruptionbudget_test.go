@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDesiredDNSPodDisruptionBudget(t *testing.T) {
+	two := intstr.FromInt(2)
+	tenPercent := intstr.FromString("10%")
+	invalid := intstr.FromString("ten")
+	testCases := []struct {
+		description    string
+		maxUnavailable *intstr.IntOrString
+		// nodes is the number of nodes that are to run CoreDNS, or -1
+		// for the deployment topology.
+		nodes int32
+
+		expectMaxUnavailable *intstr.IntOrString
+		expectMinAvailable   *intstr.IntOrString
+		expectError          bool
+	}{
+		{
+			description:          "deployment with default",
+			nodes:                -1,
+			expectMaxUnavailable: &defaultMaxUnavailable,
+		},
+		{
+			description:          "deployment with percentage",
+			maxUnavailable:       &tenPercent,
+			nodes:                -1,
+			expectMaxUnavailable: &tenPercent,
+		},
+		{
+			description:        "daemonset with default",
+			nodes:              5,
+			expectMinAvailable: intstrPtr(4),
+		},
+		{
+			description:        "daemonset with count",
+			maxUnavailable:     &two,
+			nodes:              5,
+			expectMinAvailable: intstrPtr(3),
+		},
+		{
+			description:        "daemonset with percentage rounds up",
+			maxUnavailable:     &tenPercent,
+			nodes:              25,
+			expectMinAvailable: intstrPtr(22),
+		},
+		{
+			description:        "daemonset with more unavailable than nodes",
+			maxUnavailable:     &two,
+			nodes:              1,
+			expectMinAvailable: intstrPtr(0),
+		},
+		{
+			description:    "invalid value",
+			maxUnavailable: &invalid,
+			nodes:          -1,
+			expectError:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: DefaultDNSController,
+			},
+		}
+		dns.Spec.PodDisruptionBudget.MaxUnavailable = tc.maxUnavailable
+		var ds *appsv1.DaemonSet
+		if tc.nodes >= 0 {
+			ds = &appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: tc.nodes}}
+		}
+		pdb, err := desiredDNSPodDisruptionBudget("openshift-dns", dns, ds)
+		if tc.expectError {
+			if err == nil {
+				t.Errorf("%q: expected an error", tc.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if e, a := tc.expectMaxUnavailable, pdb.Spec.MaxUnavailable; !intstrPtrEqual(e, a) {
+			t.Errorf("%q: expected maxUnavailable %v, got %v", tc.description, e, a)
+		}
+		if e, a := tc.expectMinAvailable, pdb.Spec.MinAvailable; !intstrPtrEqual(e, a) {
+			t.Errorf("%q: expected minAvailable %v, got %v", tc.description, e, a)
+		}
+		if pdb.Spec.Selector.MatchLabels[controllerDaemonSetLabel] != DefaultDNSController {
+			t.Errorf("%q: expected the pod disruption budget to select dns pods, got %v", tc.description, pdb.Spec.Selector)
+		}
+	}
+}
+
+func TestPodDisruptionBudgetChanged(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultDNSController,
+		},
+	}
+	ds := &appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3}}
+	current, err := desiredDNSPodDisruptionBudget("openshift-dns", dns, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, _ := desiredDNSPodDisruptionBudget("openshift-dns", dns, ds)
+	if podDisruptionBudgetChanged(current, same) {
+		t.Errorf("expected identical pod disruption budgets to be unchanged")
+	}
+	ds.Status.DesiredNumberScheduled = 4
+	scaled, _ := desiredDNSPodDisruptionBudget("openshift-dns", dns, ds)
+	if !podDisruptionBudgetChanged(current, scaled) {
+		t.Errorf("expected a new node to change the pod disruption budget")
+	}
+	deployment, _ := desiredDNSPodDisruptionBudget("openshift-dns", dns, nil)
+	if !podDisruptionBudgetChanged(current, deployment) {
+		t.Errorf("expected a topology change to change the pod disruption budget")
+	}
+}
+
+func intstrPtr(i int) *intstr.IntOrString {
+	v := intstr.FromInt(i)
+	return &v
+}
+
+func intstrPtrEqual(a, b *intstr.IntOrString) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
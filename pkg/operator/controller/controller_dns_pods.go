@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/manifests"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// verifiedPodsMemoryOverhead is added to the default memory request of the
+// CoreDNS container in "Verified" pods mode, for the cache of every pod in
+// the cluster that the kubernetes plugin keeps in that mode.
+var verifiedPodsMemoryOverhead = resource.MustParse("30Mi")
+
+// effectivePodsMode returns the pods mode of the given dns, defaulting it
+// for autopath, which requires "Verified".
+func effectivePodsMode(dns *operatorv1.DNS) operatorv1.DNSPodsMode {
+	switch {
+	case len(dns.Spec.Pods.Mode) != 0:
+		return dns.Spec.Pods.Mode
+	case dns.Spec.Kubernetes.Autopath:
+		return operatorv1.VerifiedDNSPodsMode
+	default:
+		return operatorv1.InsecureDNSPodsMode
+	}
+}
+
+// dnsPodsMode returns the argument of the kubernetes plugin's pods option
+// for the given dns.
+func dnsPodsMode(dns *operatorv1.DNS) (string, error) {
+	switch mode := effectivePodsMode(dns); mode {
+	case operatorv1.InsecureDNSPodsMode:
+		return "insecure", nil
+	case operatorv1.DisabledDNSPodsMode:
+		return "disabled", nil
+	case operatorv1.VerifiedDNSPodsMode:
+		return "verified", nil
+	default:
+		return "", fmt.Errorf("unsupported pods mode %q", mode)
+	}
+}
+
+// withPodsMemoryOverhead returns the default resources of the CoreDNS
+// container, with the memory request raised if the dns verifies pods.
+func withPodsMemoryOverhead(dns *operatorv1.DNS, defaults corev1.ResourceRequirements) corev1.ResourceRequirements {
+	if effectivePodsMode(dns) != operatorv1.VerifiedDNSPodsMode {
+		return defaults
+	}
+	resources := *defaults.DeepCopy()
+	if memory, ok := resources.Requests[corev1.ResourceMemory]; ok {
+		memory.Add(verifiedPodsMemoryOverhead)
+		resources.Requests[corev1.ResourceMemory] = memory
+	}
+	return resources
+}
+
+// dnsPodsDenied returns the verbs among list and watch that the dns service
+// account may not use on pods if the dns verifies pods, in which case the
+// kubernetes plugin would never become ready.  It asks the API server with
+// subject access reviews, so that the check covers every binding and
+// authorizer rather than the rules of the dns cluster role alone.
+func (r *reconciler) dnsPodsDenied(dns *operatorv1.DNS) ([]string, error) {
+	if effectivePodsMode(dns) != operatorv1.VerifiedDNSPodsMode {
+		return nil, nil
+	}
+	sa := manifests.DNSServiceAccount().Name
+	var denied []string
+	for _, verb := range []string{"list", "watch"} {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   fmt.Sprintf("system:serviceaccount:%s:%s", r.OperandNamespace, sa),
+				Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + r.OperandNamespace, "system:authenticated"},
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     verb,
+					Resource: "pods",
+				},
+			},
+		}
+		if err := r.client.Create(context.TODO(), review); err != nil {
+			return nil, fmt.Errorf("failed to review access of service account %s/%s to %s pods: %v", r.OperandNamespace, sa, verb, err)
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+	return denied, nil
+}
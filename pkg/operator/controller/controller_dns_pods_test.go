@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDesiredDNSConfigMapPodsMode(t *testing.T) {
+	testCases := []struct {
+		mode      operatorv1.DNSPodsMode
+		expected  string
+		expectErr bool
+	}{
+		{mode: "", expected: "pods insecure"},
+		{mode: operatorv1.InsecureDNSPodsMode, expected: "pods insecure"},
+		{mode: operatorv1.DisabledDNSPodsMode, expected: "pods disabled"},
+		{mode: operatorv1.VerifiedDNSPodsMode, expected: "pods verified"},
+		{mode: "verified", expectErr: true},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+			Spec:       operatorv1.DNSSpec{Pods: operatorv1.DNSPods{Mode: tc.mode}},
+		}
+		cm, err := desiredDNSConfigMap("openshift-dns", dns, defaultCoreDNSImage, "cluster.local", nil, nil, metav1.OwnerReference{})
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", tc.mode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.mode, err)
+			continue
+		}
+		if !strings.Contains(cm.Data["Corefile"], "        "+tc.expected+"\n") {
+			t.Errorf("%q: expected Corefile to contain %q, got:\n%s", tc.mode, tc.expected, cm.Data["Corefile"])
+		}
+	}
+}
+
+func TestDesiredDNSDaemonSetPodsMemory(t *testing.T) {
+	testCases := []struct {
+		description string
+		mode        operatorv1.DNSPodsMode
+		autopath    bool
+		resources   corev1.ResourceRequirements
+		expected    string
+	}{
+		{
+			description: "insecure",
+			mode:        operatorv1.InsecureDNSPodsMode,
+			expected:    "70Mi",
+		},
+		{
+			description: "verified",
+			mode:        operatorv1.VerifiedDNSPodsMode,
+			expected:    "100Mi",
+		},
+		{
+			description: "autopath",
+			autopath:    true,
+			expected:    "100Mi",
+		},
+		{
+			description: "verified with an explicit request",
+			mode:        operatorv1.VerifiedDNSPodsMode,
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("80Mi")},
+			},
+			expected: "80Mi",
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+			Spec: operatorv1.DNSSpec{
+				Pods:       operatorv1.DNSPods{Mode: tc.mode},
+				Kubernetes: operatorv1.DNSKubernetes{Autopath: tc.autopath},
+				Resources:  operatorv1.DNSResources{DNS: tc.resources},
+			},
+		}
+		ds, err := desiredDNSDaemonSet("openshift-dns", dns, "172.30.0.10", "cluster.local", defaultCoreDNSImage, "cli")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+			continue
+		}
+		requests := containerRequests(&ds.Spec.Template.Spec, "dns")
+		memory := requests[corev1.ResourceMemory]
+		if memory.Cmp(resource.MustParse(tc.expected)) != 0 {
+			t.Errorf("%s: expected a memory request of %s, got %s", tc.description, tc.expected, memory.String())
+		}
+	}
+}
+
+// reviewClient is a client that answers subject access reviews, allowing
+// the verbs in allowed.
+type reviewClient struct {
+	kclient.Client
+	allowed map[string]bool
+	reviews []authorizationv1.SubjectAccessReviewSpec
+}
+
+func (c *reviewClient) Create(_ context.Context, obj runtime.Object) error {
+	review := obj.(*authorizationv1.SubjectAccessReview)
+	c.reviews = append(c.reviews, review.Spec)
+	review.Status.Allowed = c.allowed[review.Spec.ResourceAttributes.Verb]
+	return nil
+}
+
+func TestDNSPodsDenied(t *testing.T) {
+	testCases := []struct {
+		description   string
+		mode          operatorv1.DNSPodsMode
+		allowed       map[string]bool
+		expectReviews int
+		expected      []string
+	}{
+		{
+			description: "insecure",
+			mode:        operatorv1.InsecureDNSPodsMode,
+		},
+		{
+			description:   "verified and allowed",
+			mode:          operatorv1.VerifiedDNSPodsMode,
+			allowed:       map[string]bool{"list": true, "watch": true},
+			expectReviews: 2,
+		},
+		{
+			description:   "verified and allowed to list only",
+			mode:          operatorv1.VerifiedDNSPodsMode,
+			allowed:       map[string]bool{"list": true},
+			expectReviews: 2,
+			expected:      []string{"watch"},
+		},
+		{
+			description:   "verified and denied",
+			mode:          operatorv1.VerifiedDNSPodsMode,
+			expectReviews: 2,
+			expected:      []string{"list", "watch"},
+		},
+	}
+	for _, tc := range testCases {
+		client := &reviewClient{allowed: tc.allowed}
+		r := &reconciler{Config: Config{OperandNamespace: "openshift-dns"}, client: client}
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{Pods: operatorv1.DNSPods{Mode: tc.mode}}}
+		denied, err := r.dnsPodsDenied(dns)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if fmt.Sprint(denied) != fmt.Sprint(tc.expected) {
+			t.Errorf("%s: expected denied verbs %v, got %v", tc.description, tc.expected, denied)
+		}
+		if len(client.reviews) != tc.expectReviews {
+			t.Errorf("%s: expected %d reviews, got %d", tc.description, tc.expectReviews, len(client.reviews))
+		}
+		for _, review := range client.reviews {
+			if review.User != "system:serviceaccount:openshift-dns:dns" || review.ResourceAttributes.Resource != "pods" {
+				t.Errorf("%s: unexpected review %+v", tc.description, review)
+			}
+		}
+	}
+}
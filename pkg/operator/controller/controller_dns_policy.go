@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxRateLimitRules is the number of rate limit rules that a dns may have.
+// Each rule adds a copy of every server of the Corefile.
+const maxRateLimitRules = 8
+
+// queryTypeRegexp matches the name of a DNS query type, such as "AAAA" or
+// "AXFR".
+var queryTypeRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9]*$`)
+
+// rateLimitParameters are the options of the ratelimit plugin that are
+// substituted into the Corefile template.
+type rateLimitParameters struct {
+	QueriesPerSecond int32
+	ExemptCIDRs      []string
+}
+
+// viewParameters are the options of the view plugin that are substituted
+// into the Corefile template.
+type viewParameters struct {
+	Name       string
+	SourceCIDR string
+}
+
+// aclParameters are the options of an acl stanza that are substituted into
+// the Corefile template.
+type aclParameters struct {
+	Action      string
+	Zones       []string
+	QueryTypes  []string
+	SourceCIDRs []string
+}
+
+// dnsRateLimitParameters returns the rate limit of the given dns, or nil if
+// it does not limit queries.
+func dnsRateLimitParameters(dns *operatorv1.DNS) *rateLimitParameters {
+	if dns.Spec.RateLimit.QueriesPerSecond == 0 {
+		return nil
+	}
+	return &rateLimitParameters{
+		QueriesPerSecond: dns.Spec.RateLimit.QueriesPerSecond,
+		ExemptCIDRs:      dns.Spec.RateLimit.ExemptCIDRs,
+	}
+}
+
+// dnsRateLimitViews returns a copy of params for each rate limit rule of the
+// given dns, in order, with a view of the rule's subnet and the rule's
+// limit.  The ratelimit plugin has a single limit for the clients of a
+// server, so a rule needs servers of its own.
+func dnsRateLimitViews(dns *operatorv1.DNS, params corefileParameters) []corefileParameters {
+	var views []corefileParameters
+	for i, rule := range dns.Spec.RateLimit.Rules {
+		view := params
+		view.View = &viewParameters{
+			Name:       fmt.Sprintf("ratelimit-%d", i),
+			SourceCIDR: rule.SourceCIDR,
+		}
+		view.RateLimit = &rateLimitParameters{
+			QueriesPerSecond: rule.QueriesPerSecond,
+			ExemptCIDRs:      dns.Spec.RateLimit.ExemptCIDRs,
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// dnsACLParameters returns the ACL rules of the given dns, in order.
+func dnsACLParameters(dns *operatorv1.DNS) []aclParameters {
+	var rules []aclParameters
+	for _, rule := range dns.Spec.ACL {
+		rules = append(rules, aclParameters{
+			Action:      strings.ToLower(string(rule.Action)),
+			Zones:       rule.Zones,
+			QueryTypes:  rule.QueryTypes,
+			SourceCIDRs: rule.SourceCIDRs,
+		})
+	}
+	return rules
+}
+
+// validateDNSPolicy returns an error describing every invalid field of the
+// rate limit and ACL of the dns, and every one that the CoreDNS image does
+// not support.
+func validateDNSPolicy(dns *operatorv1.DNS, image coreDNSImage) error {
+	var errs []error
+	rateLimit := dns.Spec.RateLimit
+	switch {
+	case rateLimit.QueriesPerSecond < 0:
+		errs = append(errs, fmt.Errorf("spec.rateLimit.queriesPerSecond: invalid value %d: must not be negative", rateLimit.QueriesPerSecond))
+	case rateLimit.QueriesPerSecond > 0 || len(rateLimit.Rules) != 0:
+		if err := requireCoreDNSOption(image, "ratelimit", "spec.rateLimit"); err != nil {
+			errs = append(errs, err)
+		}
+	case len(rateLimit.ExemptCIDRs) != 0:
+		errs = append(errs, fmt.Errorf("spec.rateLimit.exemptCIDRs: requires queriesPerSecond or rules to be set"))
+	}
+	if len(rateLimit.Rules) > maxRateLimitRules {
+		errs = append(errs, fmt.Errorf("spec.rateLimit.rules: %d rules; at most %d are supported", len(rateLimit.Rules), maxRateLimitRules))
+	}
+	for i, rule := range rateLimit.Rules {
+		field := fmt.Sprintf("spec.rateLimit.rules[%d]", i)
+		if _, _, err := net.ParseCIDR(rule.SourceCIDR); err != nil {
+			errs = append(errs, fmt.Errorf("%s.sourceCIDR: invalid CIDR %q", field, rule.SourceCIDR))
+		}
+		if rule.QueriesPerSecond <= 0 {
+			errs = append(errs, fmt.Errorf("%s.queriesPerSecond: invalid value %d: must be positive", field, rule.QueriesPerSecond))
+		}
+	}
+	if len(rateLimit.Rules) != 0 {
+		if err := requireCoreDNSOption(image, "view", "spec.rateLimit.rules"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, cidr := range rateLimit.ExemptCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("spec.rateLimit.exemptCIDRs: invalid CIDR %q", cidr))
+		}
+	}
+
+	for i, rule := range dns.Spec.ACL {
+		field := fmt.Sprintf("spec.acl[%d]", i)
+		switch rule.Action {
+		case operatorv1.AllowDNSACLAction, operatorv1.BlockDNSACLAction:
+		default:
+			errs = append(errs, fmt.Errorf("%s.action: unsupported value %q; must be %q or %q", field, rule.Action, operatorv1.AllowDNSACLAction, operatorv1.BlockDNSACLAction))
+		}
+		for _, zone := range rule.Zones {
+			if msgs := validation.IsDNS1123Subdomain(zone); len(msgs) != 0 {
+				errs = append(errs, fmt.Errorf("%s.zones: invalid zone %q: %s", field, zone, strings.Join(msgs, ", ")))
+			}
+		}
+		for _, queryType := range rule.QueryTypes {
+			if !queryTypeRegexp.MatchString(queryType) {
+				errs = append(errs, fmt.Errorf("%s.queryTypes: invalid query type %q: must be upper case, for example \"AXFR\"", field, queryType))
+			}
+		}
+		for _, cidr := range rule.SourceCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				errs = append(errs, fmt.Errorf("%s.sourceCIDRs: invalid CIDR %q", field, cidr))
+			}
+		}
+	}
+	if len(dns.Spec.ACL) != 0 {
+		if err := requireCoreDNSOption(image, "acl", "spec.acl"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
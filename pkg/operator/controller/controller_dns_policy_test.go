@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDesiredDNSConfigMapPolicy(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+		Spec: operatorv1.DNSSpec{
+			RateLimit: operatorv1.DNSRateLimit{
+				QueriesPerSecond: 100,
+				ExemptCIDRs:      []string{"10.128.0.0/14", "fd01::/48"},
+			},
+			ACL: []operatorv1.DNSACLRule{
+				{
+					Action:      operatorv1.AllowDNSACLAction,
+					Zones:       []string{"internal.corp"},
+					SourceCIDRs: []string{"10.128.0.0/14"},
+				},
+				{
+					Action:     operatorv1.BlockDNSACLAction,
+					QueryTypes: []string{"AXFR", "IXFR"},
+				},
+			},
+			Servers: []operatorv1.DNSServer{{
+				Name:      "corp",
+				Zones:     []string{"internal.corp"},
+				Upstreams: []operatorv1.DNSUpstream{{Address: "10.0.0.53"}},
+			}},
+		},
+	}
+	policy := `
+    errors
+    ratelimit 100 {
+        whitelist 10.128.0.0/14 fd01::/48
+    }
+    acl internal.corp {
+        allow net 10.128.0.0/14
+    }
+    acl {
+        block type AXFR IXFR
+    }
+`
+	// The oldest release with the acl plugin, which must get a Corefile
+	// that it can load.
+	image := coreDNSImage{version: version.MustParse("1.7.1"), plugins: []string{"ratelimit"}}
+	if err := validateDNS(dns, "cluster.local", image); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	cm, err := desiredDNSConfigMap("openshift-dns", dns, image, "cluster.local", nil, nil, metav1.OwnerReference{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	corefile := cm.Data["Corefile"]
+	if !strings.HasPrefix(corefile, ".:5353 {"+policy) {
+		t.Errorf("expected the main server to start with:\n%s\ngot:\n%s", policy, corefile)
+	}
+	if !strings.Contains(corefile, "internal.corp:5353 {"+policy) {
+		t.Errorf("expected server corp to start with:\n%s\ngot:\n%s", policy, corefile)
+	}
+	for _, removed := range []string{"proxy", "upstream"} {
+		if strings.Contains(corefile, "    "+removed+" ") || strings.Contains(corefile, "    "+removed+"\n") {
+			t.Errorf("expected no %s in the Corefile for CoreDNS %s, got:\n%s", removed, image.version, corefile)
+		}
+	}
+}
+
+func TestDesiredDNSConfigMapRateLimitRules(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+		Spec: operatorv1.DNSSpec{
+			LameDuckDuration: &metav1.Duration{},
+			RateLimit: operatorv1.DNSRateLimit{
+				QueriesPerSecond: 100,
+				Rules: []operatorv1.DNSRateLimitRule{
+					{SourceCIDR: "10.128.0.0/14", QueriesPerSecond: 500},
+					{SourceCIDR: "fd01::/48", QueriesPerSecond: 1000},
+				},
+				ExemptCIDRs: []string{"10.0.0.0/16"},
+			},
+			Servers: []operatorv1.DNSServer{{
+				Name:      "corp",
+				Zones:     []string{"internal.corp"},
+				Upstreams: []operatorv1.DNSUpstream{{Address: "10.0.0.53"}},
+			}},
+		},
+	}
+	server := func(view string, limit int) string {
+		return fmt.Sprintf(`.:5353 {%s
+    errors
+    ratelimit %d {
+        whitelist 10.0.0.0/16
+    }
+    health
+    ready
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf
+    cache 30
+    reload
+}
+# corp
+internal.corp:5353 {%s
+    errors
+    ratelimit %d {
+        whitelist 10.0.0.0/16
+    }
+    forward . 10.0.0.53:53
+    cache 30
+}
+`, view, limit, view, limit)
+	}
+	expected := server(`
+    view ratelimit-0 {
+        expr incidr(client_ip(), '10.128.0.0/14')
+    }`, 500) + server(`
+    view ratelimit-1 {
+        expr incidr(client_ip(), 'fd01::/48')
+    }`, 1000) + server("", 100)
+
+	image := coreDNSImage{version: version.MustParse("1.10.0"), plugins: []string{"ratelimit"}}
+	if err := validateDNS(dns, "cluster.local", image); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	cm, err := desiredDNSConfigMap("openshift-dns", dns, image, "cluster.local", nil, nil, metav1.OwnerReference{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cm.Data["Corefile"] != expected {
+		t.Errorf("unexpected Corefile; expected:\n%s\ngot:\n%s", expected, cm.Data["Corefile"])
+	}
+}
+
+func TestValidateDNSPolicy(t *testing.T) {
+	testCases := []struct {
+		description string
+		rateLimit   operatorv1.DNSRateLimit
+		acl         []operatorv1.DNSACLRule
+		version     string
+		plugins     []string
+		expectErrs  []string
+	}{
+		{
+			description: "none",
+			version:     "1.3.1",
+		},
+		{
+			description: "supported",
+			rateLimit:   operatorv1.DNSRateLimit{QueriesPerSecond: 100, ExemptCIDRs: []string{"10.128.0.0/14"}},
+			acl: []operatorv1.DNSACLRule{{
+				Action:      operatorv1.BlockDNSACLAction,
+				Zones:       []string{"internal.corp"},
+				QueryTypes:  []string{"AXFR"},
+				SourceCIDRs: []string{"0.0.0.0/0"},
+			}},
+			version: "1.8.0",
+			plugins: []string{"ratelimit"},
+		},
+		{
+			description: "unsupported",
+			rateLimit:   operatorv1.DNSRateLimit{QueriesPerSecond: 100},
+			acl:         []operatorv1.DNSACLRule{{Action: operatorv1.AllowDNSACLAction}},
+			version:     "1.3.1",
+			expectErrs: []string{
+				"spec.rateLimit: requires a CoreDNS image built with the ratelimit plugin",
+				`spec.acl: requires CoreDNS 1.7.1 or later for "acl", but the CoreDNS image is version 1.3.1`,
+			},
+		},
+		{
+			description: "acl plugin added to an older release",
+			acl:         []operatorv1.DNSACLRule{{Action: operatorv1.AllowDNSACLAction}},
+			version:     "1.3.1",
+			plugins:     []string{"acl"},
+		},
+		{
+			description: "invalid rate limit",
+			rateLimit:   operatorv1.DNSRateLimit{QueriesPerSecond: -1, ExemptCIDRs: []string{"10.128.0.0"}},
+			version:     "1.8.0",
+			plugins:     []string{"ratelimit"},
+			expectErrs: []string{
+				"spec.rateLimit.queriesPerSecond: invalid value -1: must not be negative",
+				`spec.rateLimit.exemptCIDRs: invalid CIDR "10.128.0.0"`,
+			},
+		},
+		{
+			description: "exemptions without a limit",
+			rateLimit:   operatorv1.DNSRateLimit{ExemptCIDRs: []string{"10.128.0.0/14"}},
+			version:     "1.8.0",
+			expectErrs:  []string{"spec.rateLimit.exemptCIDRs: requires queriesPerSecond or rules to be set"},
+		},
+		{
+			description: "rate limit rules",
+			rateLimit: operatorv1.DNSRateLimit{
+				Rules:       []operatorv1.DNSRateLimitRule{{SourceCIDR: "10.128.0.0/14", QueriesPerSecond: 500}},
+				ExemptCIDRs: []string{"10.0.0.0/16"},
+			},
+			version: "1.10.0",
+			plugins: []string{"ratelimit"},
+		},
+		{
+			description: "rate limit rules before the view plugin",
+			rateLimit: operatorv1.DNSRateLimit{
+				QueriesPerSecond: 100,
+				Rules:            []operatorv1.DNSRateLimitRule{{SourceCIDR: "10.128.0.0/14", QueriesPerSecond: 500}},
+			},
+			version:    "1.8.0",
+			plugins:    []string{"ratelimit"},
+			expectErrs: []string{`spec.rateLimit.rules: requires CoreDNS 1.10.0 or later for "view", but the CoreDNS image is version 1.8.0`},
+		},
+		{
+			description: "invalid rate limit rules",
+			rateLimit: operatorv1.DNSRateLimit{
+				Rules: []operatorv1.DNSRateLimitRule{
+					{SourceCIDR: "10.128.0.0/14", QueriesPerSecond: 500},
+					{SourceCIDR: "10.0.0.0", QueriesPerSecond: 0},
+				},
+			},
+			version: "1.10.0",
+			expectErrs: []string{
+				"spec.rateLimit: requires a CoreDNS image built with the ratelimit plugin",
+				`spec.rateLimit.rules[1].sourceCIDR: invalid CIDR "10.0.0.0"`,
+				"spec.rateLimit.rules[1].queriesPerSecond: invalid value 0: must be positive",
+			},
+		},
+		{
+			description: "too many rate limit rules",
+			rateLimit: operatorv1.DNSRateLimit{
+				Rules: make([]operatorv1.DNSRateLimitRule, maxRateLimitRules+1),
+			},
+			version:    "1.10.0",
+			plugins:    []string{"ratelimit"},
+			expectErrs: []string{"spec.rateLimit.rules: 9 rules; at most 8 are supported"},
+		},
+		{
+			description: "invalid rules",
+			acl: []operatorv1.DNSACLRule{
+				{Action: operatorv1.AllowDNSACLAction},
+				{
+					Action:      "Drop",
+					Zones:       []string{"Bad_Zone"},
+					QueryTypes:  []string{"axfr"},
+					SourceCIDRs: []string{"10.0.0.0/33"},
+				},
+			},
+			version: "1.8.0",
+			expectErrs: []string{
+				`spec.acl[1].action: unsupported value "Drop"`,
+				`spec.acl[1].zones: invalid zone "Bad_Zone"`,
+				`spec.acl[1].queryTypes: invalid query type "axfr"`,
+				`spec.acl[1].sourceCIDRs: invalid CIDR "10.0.0.0/33"`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{RateLimit: tc.rateLimit, ACL: tc.acl}}
+		image := coreDNSImage{version: version.MustParse(tc.version), plugins: tc.plugins}
+		err := validateDNSPolicy(dns, image)
+		if len(tc.expectErrs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.description, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected errors %q, got none", tc.description, tc.expectErrs)
+			continue
+		}
+		for _, expect := range tc.expectErrs {
+			if !strings.Contains(err.Error(), expect) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.description, expect, err)
+			}
+		}
+	}
+}
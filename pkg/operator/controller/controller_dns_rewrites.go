@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var (
+	// rewritePrefixRegexp matches the prefixes that Prefix rewrite rules
+	// may match and replace.
+	rewritePrefixRegexp = regexp.MustCompile(`^[a-z0-9.-]+$`)
+
+	// rewriteGroupRegexp matches the references to groups of a regular
+	// expression in the replacement of a Regex rewrite rule.
+	rewriteGroupRegexp = regexp.MustCompile(`{([0-9]+)}`)
+)
+
+// rewriteParameters are the values of a rewrite rule that are substituted
+// into the Corefile template.  AnswerFrom and AnswerTo are empty if answers
+// are not rewritten.
+type rewriteParameters struct {
+	Match      string
+	From       string
+	To         string
+	AnswerFrom string
+	AnswerTo   string
+}
+
+// validateDNSRewrites returns an error describing every invalid rewrite
+// rule of the dns, and every rule that never applies because an earlier
+// rule in the same server matches every name that it matches.
+func validateDNSRewrites(dns *operatorv1.DNS) error {
+	var errs []error
+	for i, rule := range dns.Spec.Rewrites {
+		field := fmt.Sprintf("spec.rewrites[%d]", i)
+		if err := validateDNSRewrite(dns, rule); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", field, err))
+			continue
+		}
+		for j, earlier := range dns.Spec.Rewrites[:i] {
+			if validateDNSRewrite(dns, earlier) != nil {
+				continue
+			}
+			if rewriteRuleServer(dns, earlier) == rewriteRuleServer(dns, rule) && rewriteShadows(earlier, rule) {
+				errs = append(errs, fmt.Errorf("%s: never applies, since spec.rewrites[%d] comes first and matches every name that it matches", field, j))
+				break
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateDNSRewrite returns an error if the rule is invalid, or if the
+// names that it rewrites to are not answered by the server that receives
+// the names that it matches.
+func validateDNSRewrite(dns *operatorv1.DNS, rule operatorv1.DNSRewriteRule) error {
+	if len(rule.From) == 0 || len(rule.To) == 0 {
+		return fmt.Errorf("from and to must be specified")
+	}
+	for _, s := range []string{rule.From, rule.To} {
+		if strings.ContainsAny(s, " \t\r\n\"") {
+			return fmt.Errorf("%q must not contain whitespace or quotes", s)
+		}
+	}
+	switch rule.Match {
+	case operatorv1.ExactDNSRewriteMatch, operatorv1.SuffixDNSRewriteMatch:
+		for _, name := range []string{rewriteDomain(rule.From), rewriteDomain(rule.To)} {
+			if msgs := validation.IsDNS1123Subdomain(name); len(msgs) != 0 {
+				return fmt.Errorf("invalid domain %q: %s", name, strings.Join(msgs, ", "))
+			}
+		}
+		from := rewriteServerName(dns, rule.From)
+		if to := rewriteServerName(dns, rule.To); to != from {
+			return fmt.Errorf("names of %s are received by %s, but names of %s are answered by %s", rule.From, from, rule.To, to)
+		}
+	case operatorv1.PrefixDNSRewriteMatch:
+		for _, prefix := range []string{rule.From, rule.To} {
+			if !rewritePrefixRegexp.MatchString(prefix) {
+				return fmt.Errorf("invalid prefix %q: must consist of lower case alphanumeric characters, '-' or '.'", prefix)
+			}
+		}
+	case operatorv1.RegexDNSRewriteMatch:
+		if err := validateRewriteRegex(rule.From, rule.To); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("match: unsupported value %q; must be one of %q, %q, %q or %q", rule.Match,
+			operatorv1.ExactDNSRewriteMatch, operatorv1.PrefixDNSRewriteMatch, operatorv1.SuffixDNSRewriteMatch, operatorv1.RegexDNSRewriteMatch)
+	}
+	if answer := rule.Answer; answer != nil {
+		if rule.Match != operatorv1.RegexDNSRewriteMatch {
+			return fmt.Errorf("answer: requires match %q", operatorv1.RegexDNSRewriteMatch)
+		}
+		if len(answer.From) == 0 || len(answer.To) == 0 {
+			return fmt.Errorf("answer: from and to must be specified")
+		}
+		for _, s := range []string{answer.From, answer.To} {
+			if strings.ContainsAny(s, " \t\r\n\"") {
+				return fmt.Errorf("answer: %q must not contain whitespace or quotes", s)
+			}
+		}
+		if err := validateRewriteRegex(answer.From, answer.To); err != nil {
+			return fmt.Errorf("answer: %v", err)
+		}
+	}
+	return nil
+}
+
+// validateRewriteRegex returns an error if the pattern is not a valid
+// regular expression or the replacement refers to a group that the pattern
+// does not have.
+func validateRewriteRegex(pattern, replacement string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression %q: %v", pattern, err)
+	}
+	for _, match := range rewriteGroupRegexp.FindAllStringSubmatch(replacement, -1) {
+		if n, _ := strconv.Atoi(match[1]); n == 0 || n > re.NumSubexp() {
+			return fmt.Errorf("replacement %q refers to group %s, but %q has %d groups", replacement, match[1], pattern, re.NumSubexp())
+		}
+	}
+	return nil
+}
+
+// rewriteShadows returns true if the earlier rule matches every name that
+// the later rule matches, so that the later rule never applies.
+func rewriteShadows(earlier, later operatorv1.DNSRewriteRule) bool {
+	from, laterFrom := rewriteName(earlier.Match, earlier.From), rewriteName(later.Match, later.From)
+	switch earlier.Match {
+	case operatorv1.ExactDNSRewriteMatch:
+		return later.Match == operatorv1.ExactDNSRewriteMatch && laterFrom == from
+	case operatorv1.PrefixDNSRewriteMatch:
+		return (later.Match == operatorv1.ExactDNSRewriteMatch || later.Match == operatorv1.PrefixDNSRewriteMatch) && strings.HasPrefix(laterFrom, from)
+	case operatorv1.SuffixDNSRewriteMatch:
+		return (later.Match == operatorv1.ExactDNSRewriteMatch || later.Match == operatorv1.SuffixDNSRewriteMatch) && strings.HasSuffix(laterFrom, from)
+	case operatorv1.RegexDNSRewriteMatch:
+		return later.Match == operatorv1.RegexDNSRewriteMatch && laterFrom == from
+	}
+	return false
+}
+
+// rewriteName returns the name, prefix, suffix or regular expression of a
+// rewrite rule as CoreDNS matches it: names and suffixes are in lower case
+// with a trailing dot.
+func rewriteName(match operatorv1.DNSRewriteMatch, s string) string {
+	switch match {
+	case operatorv1.ExactDNSRewriteMatch, operatorv1.SuffixDNSRewriteMatch:
+		return strings.TrimSuffix(strings.ToLower(s), ".") + "."
+	case operatorv1.PrefixDNSRewriteMatch:
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// rewriteDomain returns the domain of the given name or suffix of a rewrite
+// rule.
+func rewriteDomain(s string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(s), "."), ".")
+}
+
+// rewriteServer returns the name of the server of the dns whose zones
+// receive the names of the given domain, or the empty string if they are
+// received by the default server.
+func rewriteServer(dns *operatorv1.DNS, domain string) string {
+	server, longest := "", 0
+	for _, s := range dns.Spec.Servers {
+		for _, zone := range s.Zones {
+			if (domain == zone || strings.HasSuffix(domain, "."+zone)) && len(zone) > longest {
+				server, longest = s.Name, len(zone)
+			}
+		}
+	}
+	return server
+}
+
+// rewriteRuleServer returns the name of the server of the dns in which the
+// rule applies, or the empty string for the default server.
+func rewriteRuleServer(dns *operatorv1.DNS, rule operatorv1.DNSRewriteRule) string {
+	switch rule.Match {
+	case operatorv1.ExactDNSRewriteMatch, operatorv1.SuffixDNSRewriteMatch:
+		return rewriteServer(dns, rewriteDomain(rule.From))
+	}
+	return ""
+}
+
+// rewriteServerName describes the server of the dns that receives the
+// names of the given name or suffix of a rewrite rule.
+func rewriteServerName(dns *operatorv1.DNS, s string) string {
+	if server := rewriteServer(dns, rewriteDomain(s)); len(server) != 0 {
+		return fmt.Sprintf("server %q", server)
+	}
+	return "the default server"
+}
+
+// dnsRewriteParameters returns the rewrite rules of the dns, in order, for
+// the default server and for each additional server by name.  Exact and
+// Suffix rules go to the server that receives the names that they match;
+// Prefix and Regex rules go to the default server.
+func dnsRewriteParameters(dns *operatorv1.DNS) ([]rewriteParameters, map[string][]rewriteParameters) {
+	var rewrites []rewriteParameters
+	serverRewrites := map[string][]rewriteParameters{}
+	for _, rule := range dns.Spec.Rewrites {
+		params := rewriteParameters{
+			Match: strings.ToLower(string(rule.Match)),
+			From:  rewriteName(rule.Match, rule.From),
+			To:    rewriteName(rule.Match, rule.To),
+		}
+		if rule.Answer != nil {
+			params.AnswerFrom = rule.Answer.From
+			params.AnswerTo = rule.Answer.To
+		}
+		if server := rewriteRuleServer(dns, rule); len(server) == 0 {
+			rewrites = append(rewrites, params)
+		} else {
+			serverRewrites[server] = append(serverRewrites[server], params)
+		}
+	}
+	return rewrites, serverRewrites
+}
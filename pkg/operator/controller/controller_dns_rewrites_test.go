@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// corpServer is an additional server that receives the names of
+// internal.corp.
+var corpServer = operatorv1.DNSServer{
+	Name:      "corp",
+	Zones:     []string{"internal.corp"},
+	Upstreams: []operatorv1.DNSUpstream{{Address: "10.0.0.53"}},
+}
+
+func TestDesiredDNSConfigMapRewrites(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+		Spec: operatorv1.DNSSpec{
+			Servers: []operatorv1.DNSServer{corpServer},
+			Rewrites: []operatorv1.DNSRewriteRule{
+				{
+					Match: operatorv1.RegexDNSRewriteMatch,
+					From:  `(.*)\.old-cluster\.example\.$`,
+					To:    "{1}.svc.cluster.local",
+					Answer: &operatorv1.DNSRewriteAnswer{
+						From: `(.*)\.svc\.cluster\.local\.$`,
+						To:   "{1}.old-cluster.example",
+					},
+				},
+				{
+					Match: operatorv1.ExactDNSRewriteMatch,
+					From:  "Registry.Old-Cluster.Example.",
+					To:    "image-registry.openshift-image-registry.svc.cluster.local",
+				},
+				{
+					Match: operatorv1.SuffixDNSRewriteMatch,
+					From:  ".legacy.internal.corp",
+					To:    ".internal.corp",
+				},
+			},
+		},
+	}
+	main := `.:5353 {
+    errors
+    rewrite stop {
+        name regex (.*)\.old-cluster\.example\.$ {1}.svc.cluster.local
+        answer name (.*)\.svc\.cluster\.local\.$ {1}.old-cluster.example
+    }
+    rewrite stop name exact registry.old-cluster.example. image-registry.openshift-image-registry.svc.cluster.local.
+    health {
+`
+	server := `internal.corp:5353 {
+    errors
+    rewrite stop name suffix .legacy.internal.corp. .internal.corp.
+    forward . 10.0.0.53:53
+`
+	cm, err := desiredDNSConfigMap("openshift-dns", dns, defaultCoreDNSImage, "cluster.local", nil, nil, metav1.OwnerReference{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	corefile := cm.Data["Corefile"]
+	if !strings.HasPrefix(corefile, main) {
+		t.Errorf("expected Corefile to start with:\n%s\ngot:\n%s", main, corefile)
+	}
+	if !strings.Contains(corefile, server) {
+		t.Errorf("expected Corefile to contain:\n%s\ngot:\n%s", server, corefile)
+	}
+}
+
+func TestValidateDNSRewrites(t *testing.T) {
+	testCases := []struct {
+		description string
+		rewrites    []operatorv1.DNSRewriteRule
+		expectErrs  []string
+	}{
+		{
+			description: "valid",
+			rewrites: []operatorv1.DNSRewriteRule{
+				{Match: operatorv1.ExactDNSRewriteMatch, From: "db.old-cluster.example", To: "db.prod.svc.cluster.local"},
+				{Match: operatorv1.SuffixDNSRewriteMatch, From: ".old-cluster.example", To: ".svc.cluster.local"},
+				{Match: operatorv1.PrefixDNSRewriteMatch, From: "www.", To: "web."},
+				{
+					Match:  operatorv1.RegexDNSRewriteMatch,
+					From:   `(.*)\.legacy\.example\.$`,
+					To:     "{1}.svc.cluster.local",
+					Answer: &operatorv1.DNSRewriteAnswer{From: `(.*)\.svc\.cluster\.local\.$`, To: "{1}.legacy.example"},
+				},
+				{Match: operatorv1.SuffixDNSRewriteMatch, From: ".legacy.internal.corp", To: ".internal.corp"},
+			},
+		},
+		{
+			description: "invalid regular expressions",
+			rewrites: []operatorv1.DNSRewriteRule{
+				{Match: operatorv1.RegexDNSRewriteMatch, From: `(.*\.old-cluster\.example`, To: "{1}.svc.cluster.local"},
+				{Match: operatorv1.RegexDNSRewriteMatch, From: `(.*)\.old-cluster\.example`, To: "{2}.svc.cluster.local"},
+				{
+					Match:  operatorv1.RegexDNSRewriteMatch,
+					From:   `(.*)\.legacy\.example`,
+					To:     "{1}.svc.cluster.local",
+					Answer: &operatorv1.DNSRewriteAnswer{From: `[`, To: "legacy.example"},
+				},
+			},
+			expectErrs: []string{
+				"spec.rewrites[0]: invalid regular expression",
+				`spec.rewrites[1]: replacement "{2}.svc.cluster.local" refers to group 2`,
+				`spec.rewrites[2]: answer: invalid regular expression "["`,
+			},
+		},
+		{
+			description: "ordering",
+			rewrites: []operatorv1.DNSRewriteRule{
+				{Match: operatorv1.SuffixDNSRewriteMatch, From: ".old-cluster.example", To: ".svc.cluster.local"},
+				{Match: operatorv1.ExactDNSRewriteMatch, From: "db.old-cluster.example", To: "db.prod.svc.cluster.local"},
+				{Match: operatorv1.SuffixDNSRewriteMatch, From: ".prod.old-cluster.example.", To: ".prod.svc.cluster.local"},
+				{Match: operatorv1.PrefixDNSRewriteMatch, From: "www", To: "web"},
+				{Match: operatorv1.PrefixDNSRewriteMatch, From: "www.", To: "web."},
+				{Match: operatorv1.ExactDNSRewriteMatch, From: "a.example", To: "b.example"},
+				{Match: operatorv1.ExactDNSRewriteMatch, From: "A.example.", To: "c.example"},
+			},
+			expectErrs: []string{
+				"spec.rewrites[1]: never applies, since spec.rewrites[0] comes first",
+				"spec.rewrites[2]: never applies, since spec.rewrites[0] comes first",
+				"spec.rewrites[4]: never applies, since spec.rewrites[3] comes first",
+				"spec.rewrites[6]: never applies, since spec.rewrites[5] comes first",
+			},
+		},
+		{
+			description: "invalid rules",
+			rewrites: []operatorv1.DNSRewriteRule{
+				{Match: operatorv1.ExactDNSRewriteMatch, From: "db.old-cluster.example"},
+				{Match: "Substring", From: "old", To: "new"},
+				{Match: operatorv1.ExactDNSRewriteMatch, From: "Bad_Name", To: "db.svc.cluster.local"},
+				{Match: operatorv1.PrefixDNSRewriteMatch, From: "w w", To: "web"},
+				{
+					Match:  operatorv1.SuffixDNSRewriteMatch,
+					From:   ".old-cluster.example",
+					To:     ".svc.cluster.local",
+					Answer: &operatorv1.DNSRewriteAnswer{From: `(.*)\.svc\.cluster\.local`, To: "{1}.old-cluster.example"},
+				},
+				{Match: operatorv1.SuffixDNSRewriteMatch, From: ".old-cluster.example", To: ".internal.corp"},
+			},
+			expectErrs: []string{
+				"spec.rewrites[0]: from and to must be specified",
+				`spec.rewrites[1]: match: unsupported value "Substring"`,
+				`spec.rewrites[2]: invalid domain "bad_name"`,
+				`spec.rewrites[3]: "w w" must not contain whitespace or quotes`,
+				`spec.rewrites[4]: answer: requires match "Regex"`,
+				`spec.rewrites[5]: names of .old-cluster.example are received by the default server, but names of .internal.corp are answered by server "corp"`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{
+			Servers:  []operatorv1.DNSServer{corpServer},
+			Rewrites: tc.rewrites,
+		}}
+		err := validateDNSRewrites(dns)
+		if len(tc.expectErrs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.description, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected errors %q, got none", tc.description, tc.expectErrs)
+			continue
+		}
+		for _, expect := range tc.expectErrs {
+			if !strings.Contains(err.Error(), expect) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.description, expect, err)
+			}
+		}
+		if actual := len(strings.Split(err.Error(), ", spec.rewrites")); actual != len(tc.expectErrs) {
+			t.Errorf("%s: expected %d errors, got %d: %v", tc.description, len(tc.expectErrs), actual, err)
+		}
+	}
+}
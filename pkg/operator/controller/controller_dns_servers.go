@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// defaultUpstreamPort is the port of an upstream resolver that does not
+// specify one.
+const defaultUpstreamPort = 53
+
+// serverParameters are the values of an additional server that are
+// substituted into the Corefile template.
+type serverParameters struct {
+	Name      string
+	Zones     []string
+	Upstreams []string
+	Rewrites  []rewriteParameters
+}
+
+// validateDNSServers returns an error describing every invalid server of
+// the dns.
+func validateDNSServers(dns *operatorv1.DNS, clusterDomain string) error {
+	var errs []error
+	names := map[string]bool{}
+	zones := map[string]string{}
+	for i, server := range dns.Spec.Servers {
+		field := fmt.Sprintf("spec.servers[%d]", i)
+		if len(server.Name) == 0 {
+			errs = append(errs, fmt.Errorf("%s.name: must be specified", field))
+		} else if names[server.Name] {
+			errs = append(errs, fmt.Errorf("%s.name: duplicate name %q", field, server.Name))
+		}
+		names[server.Name] = true
+
+		if len(server.Zones) == 0 {
+			errs = append(errs, fmt.Errorf("%s.zones: must not be empty", field))
+		}
+		for _, zone := range server.Zones {
+			if msgs := validation.IsDNS1123Subdomain(zone); len(msgs) != 0 {
+				errs = append(errs, fmt.Errorf("%s.zones: invalid zone %q: %s", field, zone, strings.Join(msgs, ", ")))
+				continue
+			}
+			if zone == clusterDomain || strings.HasSuffix(zone, "."+clusterDomain) {
+				errs = append(errs, fmt.Errorf("%s.zones: zone %q must not be in the cluster domain %s", field, zone, clusterDomain))
+			}
+			if other, ok := zones[zone]; ok {
+				errs = append(errs, fmt.Errorf("%s.zones: zone %q is already a zone of server %q", field, zone, other))
+			}
+			zones[zone] = server.Name
+		}
+
+		if len(server.Upstreams) == 0 {
+			errs = append(errs, fmt.Errorf("%s.upstreams: must not be empty", field))
+		}
+		for j, upstream := range server.Upstreams {
+			if err := validateDNSUpstream(upstream); err != nil {
+				errs = append(errs, fmt.Errorf("%s.upstreams[%d]: %v", field, j, err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// validateDNSUpstream returns an error if the upstream does not set exactly
+// one of address and service, or if the one that it sets is invalid.
+func validateDNSUpstream(upstream operatorv1.DNSUpstream) error {
+	switch {
+	case len(upstream.Address) != 0 && upstream.Service != nil:
+		return fmt.Errorf("address and service are mutually exclusive")
+	case len(upstream.Address) != 0:
+		_, err := upstreamAddress(upstream.Address)
+		return err
+	case upstream.Service != nil:
+		service := upstream.Service
+		if msgs := validation.IsDNS1123Label(service.Namespace); len(msgs) != 0 {
+			return fmt.Errorf("service: invalid namespace %q: %s", service.Namespace, strings.Join(msgs, ", "))
+		}
+		if msgs := validation.IsDNS1035Label(service.Name); len(msgs) != 0 {
+			return fmt.Errorf("service: invalid name %q: %s", service.Name, strings.Join(msgs, ", "))
+		}
+		if service.Port != 0 && len(validation.IsValidPortNum(int(service.Port))) != 0 {
+			return fmt.Errorf("service: invalid port %d", service.Port)
+		}
+		return nil
+	default:
+		return fmt.Errorf("one of address and service must be specified")
+	}
+}
+
+// upstreamAddress returns the given IP address, with an optional port, as
+// an address and port for the proxy plugin.
+func upstreamAddress(address string) (string, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		return net.JoinHostPort(ip.String(), strconv.Itoa(defaultUpstreamPort)), nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %v", address, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", fmt.Errorf("invalid address %q: %q is not an IP address", address, host)
+	}
+	if n, err := strconv.Atoi(port); err != nil || len(validation.IsValidPortNum(n)) != 0 {
+		return "", fmt.Errorf("invalid address %q: invalid port %q", address, port)
+	}
+	return net.JoinHostPort(ip.String(), port), nil
+}
+
+// upstreamServiceNames returns the names of the Services that the servers
+// of the dns forward to.
+func upstreamServiceNames(dns *operatorv1.DNS) []types.NamespacedName {
+	var names []types.NamespacedName
+	seen := map[types.NamespacedName]bool{}
+	for _, server := range dns.Spec.Servers {
+		for _, upstream := range server.Upstreams {
+			if upstream.Service == nil {
+				continue
+			}
+			name := types.NamespacedName{Namespace: upstream.Service.Namespace, Name: upstream.Service.Name}
+			if !seen[name] {
+				names = append(names, name)
+				seen[name] = true
+			}
+		}
+	}
+	return names
+}
+
+// currentUpstreamServices returns the Services that the servers of the dns
+// forward to, by name.  Services that do not exist are left out.  Services
+// are read through the cluster cache, whose watch on Services requeues the
+// dns when one of them changes.
+func (r *reconciler) currentUpstreamServices(dns *operatorv1.DNS) (map[types.NamespacedName]*corev1.Service, error) {
+	names := upstreamServiceNames(dns)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if err := r.ensureClusterWatch(&corev1.Service{}); err != nil {
+		return nil, err
+	}
+	services := map[types.NamespacedName]*corev1.Service{}
+	for _, name := range names {
+		service := &corev1.Service{}
+		if err := r.clusterCache.Get(context.TODO(), name, service); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get upstream service %s: %v", name, err)
+		}
+		services[name] = service
+	}
+	return services, nil
+}
+
+// dnsServerParameters returns the servers of the dns, with each upstream
+// Service resolved to its cluster IP.  It returns an error if an upstream
+// Service does not exist, has no cluster IP, or lacks the upstream's port.
+func dnsServerParameters(dns *operatorv1.DNS, services map[types.NamespacedName]*corev1.Service) ([]serverParameters, error) {
+	var servers []serverParameters
+	for _, server := range dns.Spec.Servers {
+		params := serverParameters{Name: server.Name, Zones: server.Zones}
+		for _, upstream := range server.Upstreams {
+			if upstream.Service == nil {
+				address, err := upstreamAddress(upstream.Address)
+				if err != nil {
+					return nil, fmt.Errorf("server %s: %v", server.Name, err)
+				}
+				params.Upstreams = append(params.Upstreams, address)
+				continue
+			}
+			address, err := serviceUpstreamAddress(upstream.Service, services)
+			if err != nil {
+				return nil, fmt.Errorf("server %s: %v", server.Name, err)
+			}
+			params.Upstreams = append(params.Upstreams, address)
+		}
+		servers = append(servers, params)
+	}
+	return servers, nil
+}
+
+// serviceUpstreamAddress returns the cluster IP and port of the referenced
+// Service.
+func serviceUpstreamAddress(ref *operatorv1.DNSUpstreamService, services map[types.NamespacedName]*corev1.Service) (string, error) {
+	name := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	service, ok := services[name]
+	if !ok {
+		return "", fmt.Errorf("upstream service %s not found", name)
+	}
+	if ip := net.ParseIP(service.Spec.ClusterIP); ip == nil {
+		return "", fmt.Errorf("upstream service %s has no cluster IP", name)
+	}
+	port := ref.Port
+	if port == 0 {
+		port = defaultUpstreamPort
+	}
+	for _, p := range service.Spec.Ports {
+		if p.Port == port {
+			return net.JoinHostPort(service.Spec.ClusterIP, strconv.Itoa(int(port))), nil
+		}
+	}
+	return "", fmt.Errorf("upstream service %s has no port %d", name, port)
+}
+
+// serviceChangedPredicate filters out service events that change neither
+// the number of services nor the cluster IP or ports of a service.
+func serviceChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, ok := e.ObjectOld.(*corev1.Service)
+			if !ok {
+				return false
+			}
+			new, ok := e.ObjectNew.(*corev1.Service)
+			if !ok {
+				return false
+			}
+			if old.Spec.ClusterIP != new.Spec.ClusterIP || len(old.Spec.Ports) != len(new.Spec.Ports) {
+				return true
+			}
+			for i := range old.Spec.Ports {
+				if old.Spec.Ports[i].Port != new.Spec.Ports[i].Port {
+					return true
+				}
+			}
+			return false
+		},
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+}
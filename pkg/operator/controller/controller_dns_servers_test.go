@@ -0,0 +1,295 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestDesiredDNSConfigMapServers(t *testing.T) {
+	corpService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "corp-dns", Name: "authoritative"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "172.30.12.34",
+			Ports:     []corev1.ServicePort{{Name: "dns", Port: 53}, {Name: "alt", Port: 5353}},
+		},
+	}
+	headless := corpService.DeepCopy()
+	headless.Spec.ClusterIP = corev1.ClusterIPNone
+	corpRef := &operatorv1.DNSUpstreamService{Namespace: "corp-dns", Name: "authoritative"}
+	corpName := types.NamespacedName{Namespace: "corp-dns", Name: "authoritative"}
+
+	testCases := []struct {
+		description string
+		servers     []operatorv1.DNSServer
+		services    map[types.NamespacedName]*corev1.Service
+		expected    string
+		expectErr   string
+	}{
+		{
+			description: "addresses",
+			servers: []operatorv1.DNSServer{{
+				Name:  "partner",
+				Zones: []string{"partner.example", "partner.test"},
+				Upstreams: []operatorv1.DNSUpstream{
+					{Address: "10.0.0.53"},
+					{Address: "[fd00::53]:5353"},
+				},
+			}},
+			expected: `    reload
+}
+# partner
+partner.example:5353 partner.test:5353 {
+    errors
+    forward . 10.0.0.53:53 [fd00::53]:5353
+    cache 30
+}
+`,
+		},
+		{
+			description: "services",
+			servers: []operatorv1.DNSServer{
+				{
+					Name:      "corp",
+					Zones:     []string{"internal.corp"},
+					Upstreams: []operatorv1.DNSUpstream{{Service: corpRef}},
+				},
+				{
+					Name:  "corp-alt",
+					Zones: []string{"alt.corp"},
+					Upstreams: []operatorv1.DNSUpstream{{Service: &operatorv1.DNSUpstreamService{
+						Namespace: "corp-dns",
+						Name:      "authoritative",
+						Port:      5353,
+					}}},
+				},
+			},
+			services: map[types.NamespacedName]*corev1.Service{corpName: corpService},
+			expected: `
+# corp
+internal.corp:5353 {
+    errors
+    forward . 172.30.12.34:53
+    cache 30
+}
+# corp-alt
+alt.corp:5353 {
+    errors
+    forward . 172.30.12.34:5353
+    cache 30
+}
+`,
+		},
+		{
+			description: "missing service",
+			servers: []operatorv1.DNSServer{{
+				Name:      "corp",
+				Zones:     []string{"internal.corp"},
+				Upstreams: []operatorv1.DNSUpstream{{Service: corpRef}},
+			}},
+			expectErr: "upstream service corp-dns/authoritative not found",
+		},
+		{
+			description: "headless service",
+			servers: []operatorv1.DNSServer{{
+				Name:      "corp",
+				Zones:     []string{"internal.corp"},
+				Upstreams: []operatorv1.DNSUpstream{{Service: corpRef}},
+			}},
+			services:  map[types.NamespacedName]*corev1.Service{corpName: headless},
+			expectErr: "upstream service corp-dns/authoritative has no cluster IP",
+		},
+		{
+			description: "missing port",
+			servers: []operatorv1.DNSServer{{
+				Name:  "corp",
+				Zones: []string{"internal.corp"},
+				Upstreams: []operatorv1.DNSUpstream{{Service: &operatorv1.DNSUpstreamService{
+					Namespace: "corp-dns",
+					Name:      "authoritative",
+					Port:      853,
+				}}},
+			}},
+			services:  map[types.NamespacedName]*corev1.Service{corpName: corpService},
+			expectErr: "upstream service corp-dns/authoritative has no port 853",
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+			Spec:       operatorv1.DNSSpec{Servers: tc.servers},
+		}
+		cm, err := desiredDNSConfigMap("openshift-dns", dns, defaultCoreDNSImage, "cluster.local", tc.services, nil, metav1.OwnerReference{})
+		if len(tc.expectErr) != 0 {
+			if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.description, tc.expectErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if !strings.HasSuffix(cm.Data["Corefile"], tc.expected) {
+			t.Errorf("%s: expected Corefile to end with:\n%s\ngot:\n%s", tc.description, tc.expected, cm.Data["Corefile"])
+		}
+	}
+}
+
+func TestDesiredDNSConfigMapServersForward(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+		Spec: operatorv1.DNSSpec{Servers: []operatorv1.DNSServer{{
+			Name:      "corp",
+			Zones:     []string{"internal.corp"},
+			Upstreams: []operatorv1.DNSUpstream{{Address: "10.0.0.53"}},
+		}}},
+	}
+	expected := `
+# corp
+internal.corp:5353 {
+    errors
+    forward . 10.0.0.53:53
+    cache 30
+}
+`
+	image := coreDNSImage{version: version.MustParse("1.5.0")}
+	cm, err := desiredDNSConfigMap("openshift-dns", dns, image, "cluster.local", nil, nil, metav1.OwnerReference{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(cm.Data["Corefile"], expected) {
+		t.Errorf("expected Corefile to end with:\n%s\ngot:\n%s", expected, cm.Data["Corefile"])
+	}
+}
+
+func TestValidateDNSServers(t *testing.T) {
+	valid := operatorv1.DNSServer{
+		Name:      "corp",
+		Zones:     []string{"internal.corp"},
+		Upstreams: []operatorv1.DNSUpstream{{Address: "10.0.0.53"}},
+	}
+	testCases := []struct {
+		description string
+		servers     []operatorv1.DNSServer
+		expectErrs  []string
+	}{
+		{
+			description: "valid",
+			servers: []operatorv1.DNSServer{valid, {
+				Name:  "services",
+				Zones: []string{"svc.corp"},
+				Upstreams: []operatorv1.DNSUpstream{{Service: &operatorv1.DNSUpstreamService{
+					Namespace: "corp-dns",
+					Name:      "authoritative",
+				}}},
+			}},
+		},
+		{
+			description: "duplicates",
+			servers:     []operatorv1.DNSServer{valid, valid},
+			expectErrs: []string{
+				`spec.servers[1].name: duplicate name "corp"`,
+				`spec.servers[1].zones: zone "internal.corp" is already a zone of server "corp"`,
+			},
+		},
+		{
+			description: "cluster domain",
+			servers: []operatorv1.DNSServer{{
+				Name:      "shadow",
+				Zones:     []string{"cluster.local", "svc.cluster.local", "Bad_Zone"},
+				Upstreams: valid.Upstreams,
+			}},
+			expectErrs: []string{
+				`zone "cluster.local" must not be in the cluster domain cluster.local`,
+				`zone "svc.cluster.local" must not be in the cluster domain cluster.local`,
+				`invalid zone "Bad_Zone"`,
+			},
+		},
+		{
+			description: "invalid upstreams",
+			servers: []operatorv1.DNSServer{{
+				Name:  "corp",
+				Zones: []string{"internal.corp"},
+				Upstreams: []operatorv1.DNSUpstream{
+					{},
+					{Address: "ns1.corp"},
+					{Address: "10.0.0.53:0"},
+					{Address: "10.0.0.53", Service: &operatorv1.DNSUpstreamService{Namespace: "corp-dns", Name: "authoritative"}},
+					{Service: &operatorv1.DNSUpstreamService{Namespace: "corp-dns", Name: "Authoritative"}},
+				},
+			}},
+			expectErrs: []string{
+				"spec.servers[0].upstreams[0]: one of address and service must be specified",
+				`spec.servers[0].upstreams[1]: invalid address "ns1.corp"`,
+				`spec.servers[0].upstreams[2]: invalid address "10.0.0.53:0": invalid port "0"`,
+				"spec.servers[0].upstreams[3]: address and service are mutually exclusive",
+				`spec.servers[0].upstreams[4]: service: invalid name "Authoritative"`,
+			},
+		},
+		{
+			description: "empty",
+			servers:     []operatorv1.DNSServer{{}},
+			expectErrs: []string{
+				"spec.servers[0].name: must be specified",
+				"spec.servers[0].zones: must not be empty",
+				"spec.servers[0].upstreams: must not be empty",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{Spec: operatorv1.DNSSpec{Servers: tc.servers}}
+		err := validateDNSServers(dns, "cluster.local")
+		if len(tc.expectErrs) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.description, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected errors %q, got none", tc.description, tc.expectErrs)
+			continue
+		}
+		for _, expect := range tc.expectErrs {
+			if !strings.Contains(err.Error(), expect) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.description, expect, err)
+			}
+		}
+	}
+}
+
+func TestServiceChangedPredicate(t *testing.T) {
+	pred := serviceChangedPredicate()
+	old := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "corp-dns", Name: "authoritative"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "172.30.12.34",
+			Ports:     []corev1.ServicePort{{Name: "dns", Port: 53}},
+		},
+	}
+	testCases := []struct {
+		description string
+		mutate      func(*corev1.Service)
+		expect      bool
+	}{
+		{"labels", func(s *corev1.Service) { s.Labels = map[string]string{"team": "corp"} }, false},
+		{"cluster IP", func(s *corev1.Service) { s.Spec.ClusterIP = "172.30.56.78" }, true},
+		{"port", func(s *corev1.Service) { s.Spec.Ports[0].Port = 5353 }, true},
+		{"added port", func(s *corev1.Service) { s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{Port: 853}) }, true},
+	}
+	for _, tc := range testCases {
+		new := old.DeepCopy()
+		tc.mutate(new)
+		if actual := pred.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: new, ObjectNew: new}); actual != tc.expect {
+			t.Errorf("%s: expected update to return %t, got %t", tc.description, tc.expect, actual)
+		}
+	}
+}
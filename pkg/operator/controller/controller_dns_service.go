@@ -9,14 +9,12 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
-	"github.com/sirupsen/logrus"
-
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ensureDNSService ensures that a service exists for a given DNS.
-func (r *reconciler) ensureDNSService(dns *operatorv1.DNS, clusterIP string, daemonsetRef metav1.OwnerReference) (*corev1.Service, error) {
+func (r *reconciler) ensureDNSService(dns *operatorv1.DNS, clusterIP string, workloadRef metav1.OwnerReference) (*corev1.Service, error) {
 	current, err := r.currentDNSService(dns)
 	if err != nil {
 		return nil, err
@@ -25,17 +23,19 @@ func (r *reconciler) ensureDNSService(dns *operatorv1.DNS, clusterIP string, dae
 		return current, nil
 	}
 
-	desired := desiredDNSService(dns, clusterIP, daemonsetRef)
+	desired := desiredDNSService(r.OperandNamespace, dns, clusterIP, workloadRef)
 	if err := r.client.Create(context.TODO(), desired); err != nil {
+		r.recordCreateFailed(dns, desired, err)
 		return nil, fmt.Errorf("failed to create dns service: %v", err)
 	}
-	logrus.Infof("created dns service: %s/%s", desired.Namespace, desired.Name)
+	r.logObject(desired).Info("created dns service")
+	r.recordCreated(dns, desired)
 	return desired, nil
 }
 
 func (r *reconciler) currentDNSService(dns *operatorv1.DNS) (*corev1.Service, error) {
 	current := &corev1.Service{}
-	err := r.client.Get(context.TODO(), DNSServiceName(dns), current)
+	err := r.client.Get(context.TODO(), DNSServiceName(r.OperandNamespace, dns), current)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return nil, nil
@@ -45,10 +45,10 @@ func (r *reconciler) currentDNSService(dns *operatorv1.DNS) (*corev1.Service, er
 	return current, nil
 }
 
-func desiredDNSService(dns *operatorv1.DNS, clusterIP string, daemonsetRef metav1.OwnerReference) *corev1.Service {
+func desiredDNSService(namespace string, dns *operatorv1.DNS, clusterIP string, workloadRef metav1.OwnerReference) *corev1.Service {
 	s := manifests.DNSService()
 
-	name := DNSServiceName(dns)
+	name := DNSServiceName(namespace, dns)
 	s.Namespace = name.Namespace
 	s.Name = name.Name
 	s.SetOwnerReferences([]metav1.OwnerReference{dnsOwnerRef(dns)})
@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// defaultSizingTolerancePercent is the default for how far, in percent,
+	// a computed request must move away from the applied request before it
+	// is applied.
+	defaultSizingTolerancePercent = 10
+)
+
+var (
+	// defaultMemorySizingFormula is the memory formula for
+	// ClusterProportional sizing when the dns does not specify one.
+	defaultMemorySizingFormula = operatorv1.DNSSizingFormula{
+		Base:         resource.MustParse("70Mi"),
+		PerService:   resource.MustParse("1Ki"),
+		PerEndpoints: resource.MustParse("1Ki"),
+	}
+
+	// defaultCPUSizingFormula is the CPU formula for ClusterProportional
+	// sizing when the dns does not specify one.
+	defaultCPUSizingFormula = operatorv1.DNSSizingFormula{
+		Base: resource.MustParse("100m"),
+	}
+)
+
+// clusterSize holds the counts of the resources that drive
+// ClusterProportional sizing.
+type clusterSize struct {
+	nodes     int64
+	services  int64
+	endpoints int64
+}
+
+// applyDNSSizing sets the requests of the dns container in the desired pod
+// spec according to the sizing mode of the given dns.  current is the pod
+// spec of the existing workload, if any.
+func (r *reconciler) applyDNSSizing(dns *operatorv1.DNS, current, desired *corev1.PodSpec) error {
+	switch dns.Spec.Sizing.Mode {
+	case "", operatorv1.StaticDNSSizingMode:
+		return nil
+	case operatorv1.ClusterProportionalDNSSizingMode:
+		for _, obj := range []runtime.Object{&corev1.Node{}, &corev1.Service{}, &corev1.Endpoints{}} {
+			if err := r.ensureClusterWatch(obj); err != nil {
+				return err
+			}
+		}
+		size, err := r.currentClusterSize()
+		if err != nil {
+			return err
+		}
+		return applyClusterProportionalSizing(dns, size, current, desired)
+	default:
+		return fmt.Errorf("unsupported sizing mode %q", dns.Spec.Sizing.Mode)
+	}
+}
+
+// applyClusterProportionalSizing sets the requests of the dns container in
+// the desired pod spec for a cluster of the given size.  current is the pod
+// spec of the existing workload, if any.
+func applyClusterProportionalSizing(dns *operatorv1.DNS, size clusterSize, current, desired *corev1.PodSpec) error {
+	sizing := dns.Spec.Sizing.ClusterProportional
+	if sizing == nil {
+		sizing = &operatorv1.ClusterProportionalDNSSizing{}
+	}
+	if sizing.Memory == nil && effectivePodsMode(dns) == operatorv1.VerifiedDNSPodsMode {
+		memory := *defaultMemorySizingFormula.DeepCopy()
+		memory.Base.Add(verifiedPodsMemoryOverhead)
+		sizing = sizing.DeepCopy()
+		sizing.Memory = &memory
+	}
+	tolerancePercent := int64(defaultSizingTolerancePercent)
+	if sizing.TolerancePercent != nil {
+		if *sizing.TolerancePercent < 0 {
+			return fmt.Errorf("invalid tolerancePercent %d: must not be negative", *sizing.TolerancePercent)
+		}
+		tolerancePercent = int64(*sizing.TolerancePercent)
+	}
+	requests := clusterProportionalRequests(sizing, size)
+	if current != nil {
+		requests = applySizingTolerance(containerRequests(current, "dns"), requests, tolerancePercent)
+	}
+	setContainerRequests(desired, "dns", requests)
+	return nil
+}
+
+// ensureClusterWatch starts watching the given kind of cluster resource
+// through the cluster cache.  Watches are only started once a dns needs
+// them so that the operator does not otherwise cache every Service and
+// Endpoints in the cluster.
+func (r *reconciler) ensureClusterWatch(obj runtime.Object) error {
+	kind := fmt.Sprintf("%T", obj)
+	if r.clusterWatches[kind] {
+		return nil
+	}
+	pred := clusterSizeChangedPredicate()
+	switch obj.(type) {
+	case *corev1.Node:
+		pred = nodeChangedPredicate()
+	case *corev1.Service:
+		pred = serviceChangedPredicate()
+	case *apiextensionsv1beta1.CustomResourceDefinition:
+		pred = monitoringCRDPredicate()
+	}
+	src := &source.Kind{Type: obj}
+	if err := src.InjectCache(r.clusterCache); err != nil {
+		return err
+	}
+	if err := r.controller.Watch(src, enqueueDefaultDNS(), pred); err != nil {
+		return fmt.Errorf("failed to watch %s: %v", kind, err)
+	}
+	if r.clusterWatches == nil {
+		r.clusterWatches = map[string]bool{}
+	}
+	r.clusterWatches[kind] = true
+	r.log.WithField("kind", kind).Info("started watching cluster resources for dns")
+	return nil
+}
+
+// enqueueDefaultDNS returns an event handler that enqueues the default dns
+// for any event.
+func enqueueDefaultDNS() handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(_ handler.MapObject) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: DefaultDNSController}}}
+		}),
+	}
+}
+
+// clusterSizeChangedPredicate filters out events that cannot change the
+// number of objects of a kind.
+func clusterSizeChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc:  func(_ event.UpdateEvent) bool { return false },
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+}
+
+// nodeChangedPredicate filters out node events that change neither the
+// number of nodes nor their schedulability or allocatable cores.
+func nodeChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			new, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			oldCPU, newCPU := old.Status.Allocatable[corev1.ResourceCPU], new.Status.Allocatable[corev1.ResourceCPU]
+			return old.Spec.Unschedulable != new.Spec.Unschedulable || oldCPU.Cmp(newCPU) != 0
+		},
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+}
+
+// currentClusterSize counts the resources that drive ClusterProportional
+// sizing.
+func (r *reconciler) currentClusterSize() (clusterSize, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.clusterCache.List(context.TODO(), nodes); err != nil {
+		return clusterSize{}, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	services := &corev1.ServiceList{}
+	if err := r.clusterCache.List(context.TODO(), services); err != nil {
+		return clusterSize{}, fmt.Errorf("failed to list services: %v", err)
+	}
+	endpoints := &corev1.EndpointsList{}
+	if err := r.clusterCache.List(context.TODO(), endpoints); err != nil {
+		return clusterSize{}, fmt.Errorf("failed to list endpoints: %v", err)
+	}
+	return clusterSize{
+		nodes:     int64(len(nodes.Items)),
+		services:  int64(len(services.Items)),
+		endpoints: int64(len(endpoints.Items)),
+	}, nil
+}
+
+// clusterProportionalRequests computes the requests of the dns container
+// for a cluster of the given size.
+func clusterProportionalRequests(sizing *operatorv1.ClusterProportionalDNSSizing, size clusterSize) corev1.ResourceList {
+	memory := &defaultMemorySizingFormula
+	if sizing.Memory != nil {
+		memory = sizing.Memory
+	}
+	cpu := &defaultCPUSizingFormula
+	if sizing.CPU != nil {
+		cpu = sizing.CPU
+	}
+
+	// Round memory up to whole mebibytes so that requests read well.
+	const mebibyte = 1024 * 1024
+	memoryBytes := (evaluateSizingFormula(memory, size) + 999) / 1000
+	memoryBytes = (memoryBytes + mebibyte - 1) / mebibyte * mebibyte
+
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(evaluateSizingFormula(cpu, size), resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(memoryBytes, resource.BinarySI),
+	}
+}
+
+// evaluateSizingFormula returns the result of the formula, in thousandths of
+// the base unit of the resource, for a cluster of the given size.
+func evaluateSizingFormula(f *operatorv1.DNSSizingFormula, size clusterSize) int64 {
+	total := f.Base.MilliValue() +
+		size.nodes*f.PerNode.MilliValue() +
+		size.services*f.PerService.MilliValue() +
+		size.endpoints*f.PerEndpoints.MilliValue()
+	if f.Max != nil && total > f.Max.MilliValue() {
+		total = f.Max.MilliValue()
+	}
+	return total
+}
+
+// applySizingTolerance returns the computed requests, except that requests
+// that are within tolerancePercent of the applied requests keep their
+// applied values.
+func applySizingTolerance(applied, computed corev1.ResourceList, tolerancePercent int64) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for name, quantity := range computed {
+		result[name] = quantity
+		current, ok := applied[name]
+		if !ok || current.MilliValue() == 0 {
+			continue
+		}
+		delta := quantity.MilliValue() - current.MilliValue()
+		if delta < 0 {
+			delta = -delta
+		}
+		if delta*100 <= current.MilliValue()*tolerancePercent {
+			result[name] = current
+		}
+	}
+	return result
+}
+
+// containerRequests returns the requests of the named container in the
+// given pod spec.
+func containerRequests(spec *corev1.PodSpec, name string) corev1.ResourceList {
+	for _, c := range spec.Containers {
+		if c.Name == name {
+			return c.Resources.Requests
+		}
+	}
+	return nil
+}
+
+// setContainerRequests sets requests on the named container in the given
+// pod spec.  Requests never exceed the container's limits.
+func setContainerRequests(spec *corev1.PodSpec, name string, requests corev1.ResourceList) {
+	for i, c := range spec.Containers {
+		if c.Name != name {
+			continue
+		}
+		resources := &spec.Containers[i].Resources
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		for resourceName, quantity := range requests {
+			if limit, ok := resources.Limits[resourceName]; ok && quantity.Cmp(limit) > 0 {
+				quantity = limit
+			}
+			resources.Requests[resourceName] = quantity
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestClusterProportionalRequests(t *testing.T) {
+	max := resource.MustParse("500m")
+	testCases := []struct {
+		description string
+		sizing      operatorv1.ClusterProportionalDNSSizing
+		size        clusterSize
+		cpu         string
+		memory      string
+	}{
+		{
+			description: "defaults for an empty cluster",
+			cpu:         "100m",
+			memory:      "70Mi",
+		},
+		{
+			description: "defaults round memory up to whole mebibytes",
+			size:        clusterSize{nodes: 10, services: 100, endpoints: 100},
+			cpu:         "100m",
+			memory:      "71Mi",
+		},
+		{
+			description: "defaults scale with services and endpoints",
+			size:        clusterSize{nodes: 10, services: 5120, endpoints: 5120},
+			cpu:         "100m",
+			memory:      "80Mi",
+		},
+		{
+			description: "custom cpu formula per node",
+			sizing: operatorv1.ClusterProportionalDNSSizing{
+				CPU: &operatorv1.DNSSizingFormula{
+					Base:    resource.MustParse("100m"),
+					PerNode: resource.MustParse("5m"),
+				},
+			},
+			size:   clusterSize{nodes: 20},
+			cpu:    "200m",
+			memory: "70Mi",
+		},
+		{
+			description: "custom cpu formula is capped at max",
+			sizing: operatorv1.ClusterProportionalDNSSizing{
+				CPU: &operatorv1.DNSSizingFormula{
+					Base:    resource.MustParse("100m"),
+					PerNode: resource.MustParse("5m"),
+					Max:     &max,
+				},
+			},
+			size:   clusterSize{nodes: 1000},
+			cpu:    "500m",
+			memory: "70Mi",
+		},
+	}
+
+	for _, tc := range testCases {
+		requests := clusterProportionalRequests(&tc.sizing, tc.size)
+		cpu, memory := requests[corev1.ResourceCPU], requests[corev1.ResourceMemory]
+		if cpu.Cmp(resource.MustParse(tc.cpu)) != 0 {
+			t.Errorf("%q: expected cpu %s, got %s", tc.description, tc.cpu, cpu.String())
+		}
+		if memory.Cmp(resource.MustParse(tc.memory)) != 0 {
+			t.Errorf("%q: expected memory %s, got %s", tc.description, tc.memory, memory.String())
+		}
+	}
+}
+
+func TestApplySizingTolerance(t *testing.T) {
+	testCases := []struct {
+		description string
+		applied     string
+		computed    string
+		expected    string
+	}{
+		{
+			description: "nothing applied yet",
+			computed:    "100Mi",
+			expected:    "100Mi",
+		},
+		{
+			description: "small increase is ignored",
+			applied:     "100Mi",
+			computed:    "105Mi",
+			expected:    "100Mi",
+		},
+		{
+			description: "small decrease is ignored",
+			applied:     "100Mi",
+			computed:    "90Mi",
+			expected:    "100Mi",
+		},
+		{
+			description: "large increase is applied",
+			applied:     "100Mi",
+			computed:    "111Mi",
+			expected:    "111Mi",
+		},
+		{
+			description: "large decrease is applied",
+			applied:     "100Mi",
+			computed:    "89Mi",
+			expected:    "89Mi",
+		},
+	}
+
+	for _, tc := range testCases {
+		applied := corev1.ResourceList{}
+		if len(tc.applied) != 0 {
+			applied[corev1.ResourceMemory] = resource.MustParse(tc.applied)
+		}
+		computed := corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(tc.computed)}
+		result := applySizingTolerance(applied, computed, defaultSizingTolerancePercent)
+		memory := result[corev1.ResourceMemory]
+		if memory.Cmp(resource.MustParse(tc.expected)) != 0 {
+			t.Errorf("%q: expected memory %s, got %s", tc.description, tc.expected, memory.String())
+		}
+	}
+}
+
+func TestSetContainerRequests(t *testing.T) {
+	spec := &corev1.PodSpec{}
+	spec.Containers = []corev1.Container{
+		{
+			Name: "dns",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi")},
+			},
+		},
+		{Name: "dns-node-resolver"},
+	}
+	setContainerRequests(spec, "dns", corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+		corev1.ResourceMemory: resource.MustParse("150Mi"),
+	})
+
+	requests := spec.Containers[0].Resources.Requests
+	cpu, memory := requests[corev1.ResourceCPU], requests[corev1.ResourceMemory]
+	if cpu.Cmp(resource.MustParse("200m")) != 0 {
+		t.Errorf("expected cpu request 200m, got %s", cpu.String())
+	}
+	if memory.Cmp(resource.MustParse("100Mi")) != 0 {
+		t.Errorf("expected memory request to be capped at the 100Mi limit, got %s", memory.String())
+	}
+	if requests := spec.Containers[1].Resources.Requests; len(requests) != 0 {
+		t.Errorf("expected no requests on dns-node-resolver, got %v", requests)
+	}
+}
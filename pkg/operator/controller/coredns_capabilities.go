@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+)
+
+// coreDNSOptionVersions maps the Corefile options that the operator renders
+// only when a dns asks for them, or only when the CoreDNS image supports
+// them, to the first CoreDNS release that supports them.  Other options of
+// the Corefile asset are supported by every CoreDNS image that the operator
+// manages and are not listed.
+var coreDNSOptionVersions = map[string]version.Version{
+	"acl":                           version.MustParse("1.7.1"),
+	"autopath":                      version.MustParse("1.0.0"),
+	"kubernetes namespaces":         version.MustParse("1.0.0"),
+	"kubernetes endpoint_pod_names": version.MustParse("1.0.5"),
+	"kubernetes ttl":                version.MustParse("1.1.2"),
+	"ready":                         version.MustParse("1.5.0"),
+	"view":                          version.MustParse("1.10.0"),
+}
+
+var (
+	// minCoreDNSVersion is the oldest CoreDNS release for which the
+	// operator renders a Corefile.  Options of the Corefile assets that
+	// coreDNSOptionVersions does not list are supported from this release
+	// on.
+	minCoreDNSVersion = version.MustParse("1.3.0")
+
+	// coreDNSProxyRemovedVersion is the first CoreDNS release without the
+	// proxy plugin.  For this release and later ones, the Corefile
+	// forwards with the forward plugin instead, and leaves out the
+	// upstream option of the kubernetes plugin, which the release
+	// deprecated.
+	coreDNSProxyRemovedVersion = version.MustParse("1.5.0")
+
+	// coreDNSMetricsRenamedVersion is the first CoreDNS release that
+	// exports its counters under their Prometheus-conventional names, such
+	// as coredns_dns_responses_total for
+	// coredns_dns_response_rcode_count_total.
+	coreDNSMetricsRenamedVersion = version.MustParse("1.7.0")
+)
+
+// coreDNSExternalPlugins are the plugins that the operator may render but
+// that no CoreDNS release includes.  An image supports them only if it is
+// configured as built with them.
+var coreDNSExternalPlugins = map[string]bool{
+	"ratelimit": true,
+}
+
+// coreDNSImage describes the CoreDNS image and the Corefile options that it
+// supports.
+type coreDNSImage struct {
+	// name is the pull spec of the image.
+	name string
+
+	// version is the CoreDNS release of the image.
+	version version.Version
+
+	// plugins are the plugins that the image is built with in addition to
+	// those of its release.
+	plugins []string
+}
+
+// coreDNSImage returns what the configured CoreDNS image supports.
+func (c *Config) coreDNSImage() coreDNSImage {
+	return coreDNSImage{name: c.CoreDNSImage, version: c.CoreDNSVersion, plugins: c.CoreDNSPlugins}
+}
+
+// validateCoreDNSVersion returns an error if the operator does not render a
+// Corefile for the given CoreDNS release.
+func validateCoreDNSVersion(v version.Version) error {
+	if !v.AtLeast(minCoreDNSVersion) {
+		return fmt.Errorf("CoreDNS %s is not supported: the oldest supported release is %s", v, minCoreDNSVersion)
+	}
+	return nil
+}
+
+// usesProxy returns true if the Corefile for the image forwards with the
+// proxy plugin rather than the forward plugin.
+func (image coreDNSImage) usesProxy() bool {
+	return !image.version.AtLeast(coreDNSProxyRemovedVersion)
+}
+
+// hasRenamedMetrics returns true if the image exports its counters under
+// the names that CoreDNS 1.7.0 introduced.
+func (image coreDNSImage) hasRenamedMetrics() bool {
+	return image.version.AtLeast(coreDNSMetricsRenamedVersion)
+}
+
+// supports returns true if the CoreDNS release of the image supports the
+// given Corefile option.
+func (image coreDNSImage) supports(option string) bool {
+	min, ok := coreDNSOptionVersions[option]
+	return ok && image.version.AtLeast(min)
+}
+
+// hasPlugin returns true if the image is built with the given plugin in
+// addition to those of its release.
+func (image coreDNSImage) hasPlugin(plugin string) bool {
+	for _, p := range image.plugins {
+		if p == plugin {
+			return true
+		}
+	}
+	return false
+}
+
+// requireCoreDNSOption returns an error if the CoreDNS image does not
+// support the given Corefile option, which is rendered for the named field
+// of the dns spec.  An option that is a whole plugin is supported if the
+// image is built with the plugin; otherwise, the option is supported if the
+// CoreDNS release of the image supports it.
+func requireCoreDNSOption(image coreDNSImage, option, field string) error {
+	plugin := strings.Fields(option)[0]
+	if option == plugin && image.hasPlugin(plugin) {
+		return nil
+	}
+	if coreDNSExternalPlugins[plugin] {
+		return fmt.Errorf("%s: requires a CoreDNS image built with the %s plugin, but the image is not configured as having it", field, plugin)
+	}
+	min, ok := coreDNSOptionVersions[option]
+	if !ok {
+		return fmt.Errorf("%s: unknown CoreDNS option %q", field, option)
+	}
+	if !image.version.AtLeast(min) {
+		return fmt.Errorf("%s: requires CoreDNS %s or later for %q, but the CoreDNS image is version %s", field, min, option, image.version)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"fmt"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorclient "github.com/openshift/cluster-dns-operator/pkg/operator/client"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Reasons of the events that the operator records.  Events are recorded
+// both on the dns and on the affected resource, so that they can be found
+// from either and filtered by reason.
+const (
+	eventReasonSuccessfulCreate = "SuccessfulCreate"
+	eventReasonSuccessfulUpdate = "SuccessfulUpdate"
+	eventReasonSuccessfulDelete = "SuccessfulDelete"
+	eventReasonFailedCreate     = "FailedCreate"
+	eventReasonFailedUpdate     = "FailedUpdate"
+	eventReasonFailedDelete     = "FailedDelete"
+	eventReasonReconcileFailed  = "ReconcileFailed"
+	eventReasonDriftCorrected   = "DriftCorrected"
+)
+
+// recordCreated records that the operator created obj for the dns.
+func (r *reconciler) recordCreated(dns *operatorv1.DNS, obj runtime.Object) {
+	r.recordEvent(dns, obj, corev1.EventTypeNormal, eventReasonSuccessfulCreate, "Created %s", describeObject(obj))
+}
+
+// recordUpdated records that the operator updated obj for the dns.
+func (r *reconciler) recordUpdated(dns *operatorv1.DNS, obj runtime.Object) {
+	r.recordEvent(dns, obj, corev1.EventTypeNormal, eventReasonSuccessfulUpdate, "Updated %s", describeObject(obj))
+}
+
+// recordDeleted records that the operator deleted obj for the dns.
+func (r *reconciler) recordDeleted(dns *operatorv1.DNS, obj runtime.Object) {
+	r.recordEvent(dns, obj, corev1.EventTypeNormal, eventReasonSuccessfulDelete, "Deleted %s", describeObject(obj))
+}
+
+// recordDriftCorrected records on the dns that the operator corrected the
+// drift of the described object from its manifest.
+func (r *reconciler) recordDriftCorrected(dns *operatorv1.DNS, described string) {
+	r.recordEvent(dns, nil, corev1.EventTypeNormal, eventReasonDriftCorrected, "Corrected drift of %s from its manifest", described)
+}
+
+// recordCreateFailed, recordUpdateFailed and recordDeleteFailed record that
+// the operator failed to create, update or delete obj for the dns.
+func (r *reconciler) recordCreateFailed(dns *operatorv1.DNS, obj runtime.Object, err error) {
+	r.recordEvent(dns, obj, corev1.EventTypeWarning, eventReasonFailedCreate, "Failed to create %s: %v", describeObject(obj), err)
+}
+
+func (r *reconciler) recordUpdateFailed(dns *operatorv1.DNS, obj runtime.Object, err error) {
+	r.recordEvent(dns, obj, corev1.EventTypeWarning, eventReasonFailedUpdate, "Failed to update %s: %v", describeObject(obj), err)
+}
+
+func (r *reconciler) recordDeleteFailed(dns *operatorv1.DNS, obj runtime.Object, err error) {
+	r.recordEvent(dns, obj, corev1.EventTypeWarning, eventReasonFailedDelete, "Failed to delete %s: %v", describeObject(obj), err)
+}
+
+// recordEvent records an event on the dns, unless it is nil, and on obj,
+// unless it is nil.
+func (r *reconciler) recordEvent(dns *operatorv1.DNS, obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	if dns != nil {
+		r.recorder.Eventf(dns, eventType, reason, messageFmt, args...)
+	}
+	if obj != nil {
+		r.recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+	}
+}
+
+// describeObject returns the kind and name of obj for use in event messages,
+// for example "DaemonSet openshift-dns/dns-default".
+func describeObject(obj runtime.Object) string {
+	kind := objectKind(obj)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return kind
+	}
+	if len(accessor.GetNamespace()) == 0 {
+		return fmt.Sprintf("%s %s", kind, accessor.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", kind, accessor.GetNamespace(), accessor.GetName())
+}
+
+// objectKind returns the kind of obj, or "object" if it is not known.
+func objectKind(obj runtime.Object) string {
+	if gvks, _, err := operatorclient.GetScheme().ObjectKinds(obj); err == nil && len(gvks) > 0 {
+		return gvks[0].Kind
+	}
+	return "object"
+}
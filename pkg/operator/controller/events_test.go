@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordEvents(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+	}
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-dns", Name: "dns-default"},
+	}
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(serviceMonitorGVK)
+	sm.SetNamespace("openshift-dns")
+	sm.SetName("dns-default")
+
+	testCases := []struct {
+		description string
+		record      func(r *reconciler)
+		expect      []string
+	}{
+		{
+			description: "created daemonset",
+			record:      func(r *reconciler) { r.recordCreated(dns, daemonset) },
+			expect: []string{
+				"Normal SuccessfulCreate Created DaemonSet openshift-dns/dns-default",
+				"Normal SuccessfulCreate Created DaemonSet openshift-dns/dns-default",
+			},
+		},
+		{
+			description: "failed to update service monitor",
+			record:      func(r *reconciler) { r.recordUpdateFailed(dns, sm, errors.New("conflict")) },
+			expect: []string{
+				"Warning FailedUpdate Failed to update ServiceMonitor openshift-dns/dns-default: conflict",
+				"Warning FailedUpdate Failed to update ServiceMonitor openshift-dns/dns-default: conflict",
+			},
+		},
+		{
+			description: "corrected drift",
+			record:      func(r *reconciler) { r.recordDriftCorrected(dns, "ClusterRole openshift-dns") },
+			expect: []string{
+				"Normal DriftCorrected Corrected drift of ClusterRole openshift-dns from its manifest",
+			},
+		},
+		{
+			description: "deleted without dns",
+			record:      func(r *reconciler) { r.recordDeleted(nil, daemonset) },
+			expect: []string{
+				"Normal SuccessfulDelete Deleted DaemonSet openshift-dns/dns-default",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		recorder := record.NewFakeRecorder(10)
+		tc.record(&reconciler{recorder: recorder})
+		close(recorder.Events)
+		actual := []string{}
+		for event := range recorder.Events {
+			actual = append(actual, event)
+		}
+		if len(actual) != len(tc.expect) {
+			t.Errorf("%s: expected events %q, got %q", tc.description, tc.expect, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != tc.expect[i] {
+				t.Errorf("%s: expected event %q, got %q", tc.description, tc.expect[i], actual[i])
+			}
+		}
+	}
+}
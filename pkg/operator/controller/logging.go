@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// startReconcileLog sets up the logger of a reconcile.  Every message that
+// is logged during the reconcile has the name of the dns, a reconcile ID
+// that is unique within the process, and the attempt, which counts the
+// reconciles since the last one that succeeded.
+func (r *reconciler) startReconcileLog(request reconcile.Request) {
+	r.reconcileCount++
+	r.log = logrus.WithFields(logrus.Fields{
+		"dns":       request.Name,
+		"reconcile": r.reconcileCount,
+		"attempt":   r.failedReconciles + 1,
+	})
+}
+
+// finishReconcileLog records whether a reconcile succeeded, for the attempt
+// field of the next reconcile.
+func (r *reconciler) finishReconcileLog(succeeded bool) {
+	if succeeded {
+		r.failedReconciles = 0
+	} else {
+		r.failedReconciles++
+	}
+}
+
+// logObject returns the logger of the current reconcile with fields that
+// identify obj.
+func (r *reconciler) logObject(obj runtime.Object) *logrus.Entry {
+	fields := logrus.Fields{"kind": objectKind(obj)}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		fields["name"] = accessor.GetName()
+		if len(accessor.GetNamespace()) != 0 {
+			fields["namespace"] = accessor.GetNamespace()
+		}
+	}
+	return r.log.WithFields(fields)
+}
@@ -11,12 +11,25 @@ const (
 	// controllerDaemonSetLabel identifies a daemonset as a dns
 	// daemonset, and the value is the name of the owning dns.
 	controllerDaemonSetLabel = "dns.operator.openshift.io/daemonset-dns"
+
+	// controllerDeploymentLabel identifies a deployment as a dns
+	// deployment, and the value is the name of the owning dns.
+	controllerDeploymentLabel = "dns.operator.openshift.io/deployment-dns"
+
+	// controllerNodeResolverDaemonSetLabel identifies a daemonset as a
+	// node resolver daemonset, and the value is the name of the owning dns.
+	controllerNodeResolverDaemonSetLabel = "dns.operator.openshift.io/daemonset-node-resolver"
+
+	// controllerNodeLocalCacheDaemonSetLabel identifies a daemonset as a
+	// node-local cache daemonset, and the value is the name of the owning
+	// dns.
+	controllerNodeLocalCacheDaemonSetLabel = "dns.operator.openshift.io/daemonset-node-local-dns"
 )
 
 // DNSDaemonSetName returns the namespaced name for the dns daemonset.
-func DNSDaemonSetName(dns *operatorv1.DNS) types.NamespacedName {
+func DNSDaemonSetName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
 	return types.NamespacedName{
-		Namespace: "openshift-dns",
+		Namespace: namespace,
 		Name:      "dns-" + dns.Name,
 	}
 }
@@ -33,16 +46,116 @@ func DNSDaemonSetPodSelector(dns *operatorv1.DNS) *metav1.LabelSelector {
 	}
 }
 
-func DNSServiceName(dns *operatorv1.DNS) types.NamespacedName {
+// DNSDeploymentName returns the namespaced name for the dns deployment.
+func DNSDeploymentName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "dns-" + dns.Name,
+	}
+}
+
+// DNSDeploymentPodSelector returns the selector of the dns deployment.
+// Pods of the deployment also carry the labels of DNSDaemonSetPodSelector
+// so that the dns service selects them.
+func DNSDeploymentPodSelector(dns *operatorv1.DNS) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			controllerDeploymentLabel: DNSDaemonSetLabel(dns),
+		},
+	}
+}
+
+// NodeResolverDaemonSetName returns the namespaced name for the daemonset
+// that runs the node resolver when CoreDNS does not run on every node.
+func NodeResolverDaemonSetName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "node-resolver-" + dns.Name,
+	}
+}
+
+func NodeResolverDaemonSetPodSelector(dns *operatorv1.DNS) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			controllerNodeResolverDaemonSetLabel: DNSDaemonSetLabel(dns),
+		},
+	}
+}
+
+// NodeLocalCacheDaemonSetName returns the namespaced name for the node-local
+// cache daemonset.
+func NodeLocalCacheDaemonSetName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "node-local-dns-" + dns.Name,
+	}
+}
+
+func NodeLocalCacheDaemonSetPodSelector(dns *operatorv1.DNS) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			controllerNodeLocalCacheDaemonSetLabel: DNSDaemonSetLabel(dns),
+		},
+	}
+}
+
+func NodeLocalCacheConfigMapName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "node-local-dns-" + dns.Name,
+	}
+}
+
+// NodeLocalCacheUpstreamServiceName returns the namespaced name for the
+// service through which the node-local cache forwards to CoreDNS.  The cache
+// binds the cluster IP of the dns service, so it cannot forward through it.
+func NodeLocalCacheUpstreamServiceName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "node-local-dns-" + dns.Name + "-upstream",
+	}
+}
+
+func DNSPodDisruptionBudgetName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "dns-" + dns.Name,
+	}
+}
+
+// DNSMetricsServiceName returns the namespaced name for the service that
+// exposes CoreDNS metrics.
+func DNSMetricsServiceName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "dns-" + dns.Name + "-metrics",
+	}
+}
+
+func DNSServiceMonitorName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "dns-" + dns.Name,
+	}
+}
+
+func DNSPrometheusRuleName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      "dns-" + dns.Name,
+	}
+}
+
+func DNSServiceName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
 	return types.NamespacedName{
-		Namespace: "openshift-dns",
+		Namespace: namespace,
 		Name:      "dns-" + dns.Name,
 	}
 }
 
-func DNSConfigMapName(dns *operatorv1.DNS) types.NamespacedName {
+func DNSConfigMapName(namespace string, dns *operatorv1.DNS) types.NamespacedName {
 	return types.NamespacedName{
-		Namespace: "openshift-dns",
+		Namespace: namespace,
 		Name:      "dns-" + dns.Name,
 	}
 }
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// operatorMetricsNamespace prefixes the names of the operator's
+	// metrics.
+	operatorMetricsNamespace = "dns_operator"
+)
+
+// conditionStatuses are the values of the status label of dnsCondition.
+var conditionStatuses = []operatorv1.ConditionStatus{
+	operatorv1.ConditionTrue,
+	operatorv1.ConditionFalse,
+	operatorv1.ConditionUnknown,
+}
+
+var (
+	// reconcileDuration is the time taken to reconcile each kind of
+	// resource.
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: operatorMetricsNamespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Time taken to reconcile resources of each kind.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"kind"})
+
+	// reconcileTotal counts reconciliations of each kind of resource by
+	// result.
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: operatorMetricsNamespace,
+		Name:      "reconcile_total",
+		Help:      "Number of reconciliations of resources of each kind by result.",
+	}, []string{"kind", "result"})
+
+	// lastSuccessfulReconcile is the time of the last reconciliation of a
+	// dns that completed without errors.
+	lastSuccessfulReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: operatorMetricsNamespace,
+		Name:      "last_successful_reconcile_timestamp_seconds",
+		Help:      "Unix time of the last reconciliation of a dns that completed without errors.",
+	}, []string{"dns"})
+
+	// driftCorrections counts updates of resources that had drifted from
+	// their desired state.
+	driftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: operatorMetricsNamespace,
+		Name:      "drift_corrections_total",
+		Help:      "Number of updates of resources of each kind that had drifted from their desired state.",
+	}, []string{"kind"})
+
+	// dnsCondition reports the status of each condition of each dns, with
+	// a value of 1 for the current status and 0 for the others.
+	dnsCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: operatorMetricsNamespace,
+		Name:      "dns_condition",
+		Help:      "Status of the conditions of each dns; 1 for the current status and 0 otherwise.",
+	}, []string{"dns", "condition", "status"})
+
+	// dnsClusterIP reports the cluster IP that the operator computed for
+	// each dns.
+	dnsClusterIP = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: operatorMetricsNamespace,
+		Name:      "dns_cluster_ip_info",
+		Help:      "Cluster IP computed for each dns; always 1.",
+	}, []string{"dns", "cluster_ip"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileDuration,
+		reconcileTotal,
+		lastSuccessfulReconcile,
+		driftCorrections,
+		dnsCondition,
+		dnsClusterIP,
+	)
+}
+
+// observeReconcile records the duration and result of reconciling a kind of
+// resource that started at the given time.
+func observeReconcile(kind string, start time.Time, err error) {
+	reconcileDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	reconcileTotal.WithLabelValues(kind, result).Inc()
+}
+
+// recordDriftCorrection records that a resource of the given kind was
+// updated because it had drifted from its desired state.
+func recordDriftCorrection(kind string) {
+	driftCorrections.WithLabelValues(kind).Inc()
+}
+
+// recordDNSStatus records the conditions and cluster IP of a dns.
+func recordDNSStatus(dns *operatorv1.DNS) {
+	for _, c := range dns.Status.Conditions {
+		for _, status := range conditionStatuses {
+			value := float64(0)
+			if c.Status == status {
+				value = 1
+			}
+			dnsCondition.WithLabelValues(dns.Name, c.Type, string(status)).Set(value)
+		}
+	}
+
+	// Only the default dns is reconciled, so resetting drops the series
+	// of a cluster IP that is no longer in use.
+	dnsClusterIP.Reset()
+	if len(dns.Status.ClusterIP) != 0 {
+		dnsClusterIP.WithLabelValues(dns.Name, dns.Status.ClusterIP).Set(1)
+	}
+}
+
+// forgetDNS removes the series of a deleted dns.
+func forgetDNS(dns *operatorv1.DNS) {
+	for _, c := range dns.Status.Conditions {
+		forgetDNSCondition(dns.Name, c.Type)
+	}
+	dnsClusterIP.Reset()
+	lastSuccessfulReconcile.DeleteLabelValues(dns.Name)
+}
+
+// forgetDNSCondition removes the series of a condition that a dns no longer
+// has.
+func forgetDNSCondition(dnsName, conditionType string) {
+	for _, status := range conditionStatuses {
+		dnsCondition.DeleteLabelValues(dnsName, conditionType, string(status))
+	}
+}
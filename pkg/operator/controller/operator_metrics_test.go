@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// gaugeValue returns the value of a gauge.
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	m := &dto.Metric{}
+	if err := g.Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+// counterValue returns the value of a counter.
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestObserveReconcile(t *testing.T) {
+	success := counterValue(t, reconcileTotal.WithLabelValues("test", "success"))
+	failure := counterValue(t, reconcileTotal.WithLabelValues("test", "error"))
+
+	observeReconcile("test", time.Now(), nil)
+	observeReconcile("test", time.Now(), errors.New("boom"))
+	observeReconcile("test", time.Now(), errors.New("boom"))
+
+	if actual := counterValue(t, reconcileTotal.WithLabelValues("test", "success")) - success; actual != 1 {
+		t.Errorf("expected 1 successful reconcile, got %v", actual)
+	}
+	if actual := counterValue(t, reconcileTotal.WithLabelValues("test", "error")) - failure; actual != 2 {
+		t.Errorf("expected 2 failed reconciles, got %v", actual)
+	}
+}
+
+func TestRecordDNSStatus(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Status: operatorv1.DNSStatus{
+			ClusterIP: "172.30.0.10",
+			Conditions: []operatorv1.OperatorCondition{{
+				Type:   operatorv1.DNSNodeLocalCacheAvailable,
+				Status: operatorv1.ConditionFalse,
+			}},
+		},
+	}
+	recordDNSStatus(dns)
+
+	expected := map[operatorv1.ConditionStatus]float64{
+		operatorv1.ConditionTrue:    0,
+		operatorv1.ConditionFalse:   1,
+		operatorv1.ConditionUnknown: 0,
+	}
+	for status, value := range expected {
+		if actual := gaugeValue(t, dnsCondition.WithLabelValues("test", operatorv1.DNSNodeLocalCacheAvailable, string(status))); actual != value {
+			t.Errorf("expected condition status %s to be %v, got %v", status, value, actual)
+		}
+	}
+	if actual := gaugeValue(t, dnsClusterIP.WithLabelValues("test", "172.30.0.10")); actual != 1 {
+		t.Errorf("expected cluster IP to be recorded, got %v", actual)
+	}
+
+	forgetDNS(dns)
+	for status := range expected {
+		if dnsCondition.DeleteLabelValues("test", operatorv1.DNSNodeLocalCacheAvailable, string(status)) {
+			t.Errorf("expected condition status %s to be forgotten", status)
+		}
+	}
+}
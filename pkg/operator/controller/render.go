@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RenderCluster describes the cluster for which Render renders the objects
+// that depend on the state of a cluster.  The zero value is an empty
+// cluster that serves neither EndpointSlices nor the monitoring APIs.
+type RenderCluster struct {
+	// Nodes and Cores are the number of schedulable nodes and their
+	// allocatable cores.  They size a deployment and, with Services and
+	// Endpoints, ClusterProportional requests.  Every node is assumed to
+	// run a CoreDNS pod of the daemonset topology.
+	Nodes int64
+	Cores int64
+
+	// Services and Endpoints are the number of Services and Endpoints in
+	// the cluster, for ClusterProportional requests.
+	Services  int64
+	Endpoints int64
+
+	// EndpointSlices is whether the API serves EndpointSlices, which
+	// CoreDNS is then allowed to read.
+	EndpointSlices bool
+
+	// Monitoring is whether the cluster serves the ServiceMonitor and
+	// PrometheusRule APIs, in which case the metrics objects are rendered.
+	Monitoring bool
+}
+
+// Render returns the objects that the operator applies for the given dns
+// and cluster network config in the described cluster, without a cluster:
+// the namespace and RBAC scaffolding, followed by the dns's workload,
+// configmap, service, pod disruption budget and metrics objects, in the
+// order in which the operator ensures them.  The images, what
+// the CoreDNS image supports and the operand namespace, which must be set,
+// are taken from config; its other fields are ignored.
+//
+// Render also returns notes on the objects that it approximated or left out
+// because they depend on the cluster, for the reader of the objects.  If
+// the network config has no status, as in install manifests, the service
+// network of its spec is used.  Servers that forward to Services cannot be
+// rendered, since their cluster IPs are not known, nor can blocklists that
+// are read from ConfigMaps.
+func Render(config Config, cluster RenderCluster, dns *operatorv1.DNS, network *configv1.Network) ([]runtime.Object, []string, error) {
+	// TODO: fetch this from higher level openshift resource when it is exposed
+	clusterDomain := "cluster.local"
+	namespace := config.OperandNamespace
+	if len(namespace) == 0 {
+		return nil, nil, fmt.Errorf("operand namespace must be specified")
+	}
+	if err := validateCoreDNSVersion(config.CoreDNSVersion); err != nil {
+		return nil, nil, err
+	}
+	if err := validateDNS(dns, clusterDomain, config.coreDNSImage()); err != nil {
+		return nil, nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	if names := upstreamServiceNames(dns); len(names) != 0 {
+		return nil, nil, fmt.Errorf("upstream services %v cannot be resolved without a cluster", names)
+	}
+	if names := blocklistConfigMapNames(dns); len(names) != 0 {
+		return nil, nil, fmt.Errorf("blocklist configmaps %v cannot be read without a cluster", names)
+	}
+	blocklists, err := dnsBlocklistParameters(dns, clusterDomain, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	serviceNetwork := network.Status.ServiceNetwork
+	if len(serviceNetwork) == 0 {
+		serviceNetwork = network.Spec.ServiceNetwork
+	}
+	clusterIP, err := clusterIPForServiceNetwork(serviceNetwork)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var notes []string
+	if cluster.EndpointSlices {
+		notes = append(notes, "The cluster role allows CoreDNS to read EndpointSlices, assuming that the API serves them.")
+	} else {
+		notes = append(notes, "The cluster role does not allow CoreDNS to read EndpointSlices, assuming that the API does not serve them.")
+	}
+	objs := []runtime.Object{
+		desiredDNSNamespace(namespace),
+		desiredDNSClusterRole(cluster.EndpointSlices),
+		desiredDNSClusterRoleBinding(namespace),
+		desiredDNSServiceAccount(namespace),
+		desiredMetricsRole(namespace),
+		desiredMetricsRoleBinding(namespace),
+	}
+
+	daemonset, err := desiredDNSDaemonSet(namespace, dns, clusterIP, clusterDomain, config.coreDNSImage(), config.OpenshiftCLIImage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build dns daemonset: %v", err)
+	}
+	size := clusterSize{nodes: cluster.Nodes, services: cluster.Services, endpoints: cluster.Endpoints}
+	if dns.Spec.Sizing.Mode == operatorv1.ClusterProportionalDNSSizingMode {
+		if err := applyClusterProportionalSizing(dns, size, nil, &daemonset.Spec.Template.Spec); err != nil {
+			return nil, nil, fmt.Errorf("failed to size dns workload: %v", err)
+		}
+		notes = append(notes, fmt.Sprintf("The requests of CoreDNS are sized for %d nodes, %d services and %d endpoints.", cluster.Nodes, cluster.Services, cluster.Endpoints))
+	}
+	trueVar := true
+	var workloadRef metav1.OwnerReference
+	var pdbDaemonSet *appsv1.DaemonSet
+	switch dns.Spec.Topology.Mode {
+	case "", operatorv1.DaemonSetDNSTopologyMode:
+		objs = append(objs, daemonset)
+		workloadRef = metav1.OwnerReference{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+			Name:       daemonset.Name,
+			Controller: &trueVar,
+		}
+		// The budget of the daemonset topology is computed from the
+		// number of nodes that run CoreDNS.
+		pdbDaemonSet = daemonset.DeepCopy()
+		pdbDaemonSet.Status.DesiredNumberScheduled = int32(cluster.Nodes)
+		notes = append(notes, fmt.Sprintf("The minAvailable of the pod disruption budget is computed for CoreDNS pods on %d nodes.", cluster.Nodes))
+	case operatorv1.DeploymentDNSTopologyMode:
+		topology := dns.Spec.Topology.Deployment
+		if topology == nil {
+			topology = &operatorv1.DeploymentDNSTopology{}
+		}
+		replicas, err := dnsReplicas(topology, cluster.Nodes, cluster.Cores)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compute dns replicas: %v", err)
+		}
+		deployment := desiredDNSDeployment(namespace, dns, daemonset, replicas)
+		objs = append(objs, desiredNodeResolverDaemonSet(namespace, dns, daemonset), deployment)
+		workloadRef = metav1.OwnerReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       deployment.Name,
+			Controller: &trueVar,
+		}
+		notes = append(notes, fmt.Sprintf("The replicas of the deployment are computed for %d nodes and %d cores.", cluster.Nodes, cluster.Cores))
+	default:
+		return nil, nil, fmt.Errorf("unsupported topology mode %q for dns %s", dns.Spec.Topology.Mode, dns.Name)
+	}
+
+	configmap, err := desiredDNSConfigMap(namespace, dns, config.coreDNSImage(), clusterDomain, nil, blocklists, workloadRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build dns configmap: %v", err)
+	}
+	pdb, err := desiredDNSPodDisruptionBudget(namespace, dns, pdbDaemonSet)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build dns pod disruption budget: %v", err)
+	}
+	objs = append(objs, configmap, desiredDNSService(namespace, dns, clusterIP, workloadRef), pdb)
+
+	if cluster.Monitoring {
+		sm, err := desiredDNSServiceMonitor(namespace, dns)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build dns service monitor: %v", err)
+		}
+		pr, err := desiredDNSPrometheusRule(namespace, dns, config.coreDNSImage())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build dns prometheus rule: %v", err)
+		}
+		objs = append(objs, desiredDNSMetricsService(namespace, dns), sm, pr)
+	} else {
+		notes = append(notes, "The metrics service, service monitor and alerting rules are left out, assuming that the cluster does not serve the monitoring APIs.")
+	}
+
+	if dns.Spec.NodeLocalCache.Enabled {
+		if _, err := nodeLocalCacheAddress(dns); err != nil {
+			return nil, nil, err
+		}
+		notes = append(notes, "The node-local cache is left out, since it forwards to a service whose cluster IP is allocated by the cluster.")
+	}
+	return objs, notes, nil
+}
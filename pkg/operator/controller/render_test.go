@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// renderConfig is the operator config with which objects are rendered in
+// tests.
+var renderConfig = Config{
+	CoreDNSImage:      "coredns:latest",
+	CoreDNSVersion:    version.MustParse("1.3.1"),
+	OpenshiftCLIImage: "cli:latest",
+	OperandNamespace:  "openshift-dns",
+}
+
+func TestRender(t *testing.T) {
+	scaffolding := []string{"Namespace", "ClusterRole", "ClusterRoleBinding", "ServiceAccount", "Role", "RoleBinding"}
+	testCases := []struct {
+		description     string
+		mode            operatorv1.DNSTopologyMode
+		network         configv1.Network
+		expectKinds     []string
+		expectClusterIP string
+		expectErr       bool
+	}{
+		{
+			description: "daemonset topology",
+			network: configv1.Network{
+				Status: configv1.NetworkStatus{ServiceNetwork: []string{"172.30.0.0/16"}},
+			},
+			expectKinds:     append(scaffolding, "DaemonSet", "ConfigMap", "Service", "PodDisruptionBudget"),
+			expectClusterIP: "172.30.0.10",
+		},
+		{
+			description: "deployment topology from an install manifest",
+			mode:        operatorv1.DeploymentDNSTopologyMode,
+			network: configv1.Network{
+				Spec: configv1.NetworkSpec{ServiceNetwork: []string{"10.96.0.0/12"}},
+			},
+			expectKinds:     append(scaffolding, "DaemonSet", "Deployment", "ConfigMap", "Service", "PodDisruptionBudget"),
+			expectClusterIP: "10.96.0.10",
+		},
+		{
+			description: "no service network",
+			expectErr:   true,
+		},
+	}
+	for _, tc := range testCases {
+		dns := &operatorv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+			Spec: operatorv1.DNSSpec{
+				Topology: operatorv1.DNSTopology{Mode: tc.mode},
+			},
+		}
+		objs, _, err := Render(renderConfig, RenderCluster{}, dns, &tc.network)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+			continue
+		}
+
+		var kinds []string
+		for _, obj := range objs {
+			kind := reflect.TypeOf(obj).Elem().Name()
+			kinds = append(kinds, kind)
+			switch o := obj.(type) {
+			case *corev1.Service:
+				if o.Spec.ClusterIP != tc.expectClusterIP {
+					t.Errorf("%s: expected cluster IP %s, got %s", tc.description, tc.expectClusterIP, o.Spec.ClusterIP)
+				}
+			case *appsv1.Deployment:
+				if o.Spec.Replicas == nil || *o.Spec.Replicas != defaultMinDNSReplicas {
+					t.Errorf("%s: expected %d replicas, got %v", tc.description, defaultMinDNSReplicas, o.Spec.Replicas)
+				}
+			}
+		}
+		if fmt.Sprint(kinds) != fmt.Sprint(tc.expectKinds) {
+			t.Errorf("%s: expected kinds %v, got %v", tc.description, tc.expectKinds, kinds)
+		}
+	}
+}
+
+func TestRenderUnsupportedCoreDNSVersion(t *testing.T) {
+	config := renderConfig
+	config.CoreDNSVersion = version.MustParse("1.2.6")
+
+	dns := &operatorv1.DNS{ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController}}
+	network := &configv1.Network{
+		Status: configv1.NetworkStatus{ServiceNetwork: []string{"172.30.0.0/16"}},
+	}
+	expected := "CoreDNS 1.2.6 is not supported: the oldest supported release is 1.3.0"
+	if _, _, err := Render(config, RenderCluster{}, dns, network); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestRenderOperandNamespace(t *testing.T) {
+	config := renderConfig
+	config.OperandNamespace = "dns"
+
+	dns := &operatorv1.DNS{ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController}}
+	network := &configv1.Network{
+		Status: configv1.NetworkStatus{ServiceNetwork: []string{"172.30.0.0/16"}},
+	}
+	objs, _, err := Render(config, RenderCluster{Monitoring: true}, dns, network)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *corev1.Namespace:
+			if o.Name != "dns" {
+				t.Errorf("expected namespace dns, got %s", o.Name)
+			}
+		case *rbacv1.ClusterRole:
+			// The cluster role has no namespace or subjects.
+		case *rbacv1.ClusterRoleBinding:
+			for _, subject := range o.Subjects {
+				if subject.Namespace != "dns" {
+					t.Errorf("expected subject %s in namespace dns, got %s", subject.Name, subject.Namespace)
+				}
+			}
+		default:
+			meta, err := apimeta.Accessor(obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if meta.GetNamespace() != "dns" {
+				t.Errorf("expected %T %s in namespace dns, got %s", obj, meta.GetName(), meta.GetNamespace())
+			}
+		}
+	}
+
+	sm, err := desiredDNSServiceMonitor(config.OperandNamespace, dns)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names, _, _ := unstructured.NestedStringSlice(sm.Object, "spec", "namespaceSelector", "matchNames")
+	if fmt.Sprint(names) != "[dns]" {
+		t.Errorf("expected service monitor to select namespace dns, got %v", names)
+	}
+}
+
+func TestRenderCluster(t *testing.T) {
+	dns := &operatorv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDNSController},
+		Spec: operatorv1.DNSSpec{
+			Topology:       operatorv1.DNSTopology{Mode: operatorv1.DeploymentDNSTopologyMode},
+			Sizing:         operatorv1.DNSSizing{Mode: operatorv1.ClusterProportionalDNSSizingMode},
+			Pods:           operatorv1.DNSPods{Mode: operatorv1.VerifiedDNSPodsMode},
+			NodeLocalCache: operatorv1.DNSNodeLocalCache{Enabled: true},
+		},
+	}
+	network := &configv1.Network{
+		Status: configv1.NetworkStatus{ServiceNetwork: []string{"172.30.0.0/16"}},
+	}
+	cluster := RenderCluster{
+		Nodes:          20,
+		Cores:          600,
+		Services:       1000,
+		Endpoints:      1000,
+		EndpointSlices: true,
+		Monitoring:     true,
+	}
+	objs, notes, err := Render(renderConfig, cluster, dns, network)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var kinds []string
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *unstructured.Unstructured:
+			kinds = append(kinds, o.GetKind())
+		case *rbacv1.ClusterRole:
+			kinds = append(kinds, "ClusterRole")
+			allowed := false
+			for _, rule := range o.Rules {
+				for _, group := range rule.APIGroups {
+					allowed = allowed || group == endpointSliceGroup
+				}
+			}
+			if !allowed {
+				t.Errorf("expected the cluster role to allow reading EndpointSlices, got %v", o.Rules)
+			}
+		case *appsv1.Deployment:
+			kinds = append(kinds, "Deployment")
+			if o.Spec.Replicas == nil || *o.Spec.Replicas != 5 {
+				t.Errorf("expected 5 replicas, got %v", o.Spec.Replicas)
+			}
+			requests := containerRequests(&o.Spec.Template.Spec, "dns")
+			if memory := requests[corev1.ResourceMemory]; memory.String() != "102Mi" {
+				t.Errorf("expected a memory request of 102Mi, got %s", memory.String())
+			}
+		default:
+			kinds = append(kinds, reflect.TypeOf(obj).Elem().Name())
+		}
+	}
+	expectKinds := []string{
+		"Namespace", "ClusterRole", "ClusterRoleBinding", "ServiceAccount", "Role", "RoleBinding",
+		"DaemonSet", "Deployment", "ConfigMap", "Service", "PodDisruptionBudget",
+		"Service", "ServiceMonitor", "PrometheusRule",
+	}
+	if fmt.Sprint(kinds) != fmt.Sprint(expectKinds) {
+		t.Errorf("expected kinds %v, got %v", expectKinds, kinds)
+	}
+	expectNotes := []string{
+		"The cluster role allows CoreDNS to read EndpointSlices, assuming that the API serves them.",
+		"The requests of CoreDNS are sized for 20 nodes, 1000 services and 1000 endpoints.",
+		"The replicas of the deployment are computed for 20 nodes and 600 cores.",
+		"The node-local cache is left out, since it forwards to a service whose cluster IP is allocated by the cluster.",
+	}
+	if fmt.Sprint(notes) != fmt.Sprint(expectNotes) {
+		t.Errorf("expected notes %q, got %q", expectNotes, notes)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -11,12 +12,9 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 
-	"github.com/openshift/cluster-dns-operator/pkg/manifests"
-
-	"github.com/sirupsen/logrus"
-
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,28 +36,30 @@ func (r *reconciler) syncOperatorStatus() error {
 			if err := r.client.Create(context.TODO(), co); err != nil {
 				return fmt.Errorf("failed to create clusteroperator %s: %v", co.Name, err)
 			}
-			logrus.Infof("created clusteroperator %s", co.Name)
+			r.logObject(co).Info("created clusteroperator")
 		} else {
 			return fmt.Errorf("failed to get clusteroperator %s: %v", co.Name, err)
 		}
 	}
 
-	ns, dnses, daemonsets, err := r.getOperatorState()
+	ns, dnses, daemonsets, deployments, pdbs, err := r.getOperatorState()
 	if err != nil {
 		return fmt.Errorf("failed to get operator state: %v", err)
 	}
 
 	oldStatus := co.Status.DeepCopy()
-	co.Status.Conditions = computeStatusConditions(oldStatus.Conditions, ns, dnses, daemonsets)
+	co.Status.Conditions = computeStatusConditions(oldStatus.Conditions, ns, r.scaffoldingErr, r.scaffoldingDrift, dnses, daemonsets, deployments, pdbs)
 	co.Status.RelatedObjects = []configv1.ObjectReference{
 		{
 			Resource: "namespaces",
-			Name:     "openshift-dns-operator",
+			Name:     r.OperandNamespace,
 		},
-		{
+	}
+	if len(r.OperatorNamespace) != 0 {
+		co.Status.RelatedObjects = append(co.Status.RelatedObjects, configv1.ObjectReference{
 			Resource: "namespaces",
-			Name:     ns.Name,
-		},
+			Name:     r.OperatorNamespace,
+		})
 	}
 
 	if len(r.OperatorReleaseVersion) > 0 {
@@ -95,38 +95,65 @@ func (r *reconciler) syncOperatorStatus() error {
 
 // getOperatorState gets and returns the resources necessary to compute the
 // operator's current state.
-func (r *reconciler) getOperatorState() (*corev1.Namespace, []operatorv1.DNS, []appsv1.DaemonSet, error) {
-	ns := manifests.DNSNamespace()
+func (r *reconciler) getOperatorState() (*corev1.Namespace, []operatorv1.DNS, []appsv1.DaemonSet, []appsv1.Deployment, []policyv1beta1.PodDisruptionBudget, error) {
+	ns := desiredDNSNamespace(r.OperandNamespace)
 	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: ns.Name}, ns); err != nil {
 		if errors.IsNotFound(err) {
-			return nil, nil, nil, nil
+			return nil, nil, nil, nil, nil, nil
 		}
-		return nil, nil, nil, fmt.Errorf("failed to get namespace %s: %v", ns.Name, err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to get namespace %s: %v", ns.Name, err)
 	}
 
 	dnsList := &operatorv1.DNSList{}
 	if err := r.client.List(context.TODO(), dnsList); err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to list dnses: %v", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to list dnses: %v", err)
 	}
 
 	daemonsetList := &appsv1.DaemonSetList{}
 	if err := r.client.List(context.TODO(), daemonsetList, client.InNamespace(ns.Name)); err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to list daemonsets: %v", err)
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to list daemonsets: %v", err)
+	}
+
+	deploymentList := &appsv1.DeploymentList{}
+	if err := r.client.List(context.TODO(), deploymentList, client.InNamespace(ns.Name)); err != nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to list deployments: %v", err)
+	}
+
+	pdbList := &policyv1beta1.PodDisruptionBudgetList{}
+	if err := r.client.List(context.TODO(), pdbList, client.InNamespace(ns.Name)); err != nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("failed to list pod disruption budgets: %v", err)
 	}
-	return ns, dnsList.Items, daemonsetList.Items, nil
+	return ns, dnsList.Items, daemonsetList.Items, deploymentList.Items, pdbList.Items, nil
 }
 
-// computeStatusConditions computes the operator's current state.
-func computeStatusConditions(conditions []configv1.ClusterOperatorStatusCondition, ns *corev1.Namespace, dnses []operatorv1.DNS, daemonsets []appsv1.DaemonSet) []configv1.ClusterOperatorStatusCondition {
+// computeStatusConditions computes the operator's current state.  A dns is
+// available if either its daemonset or its deployment has available pods.
+// The operator is progressing while the number of dns workloads differs from
+// the number of dnses, and it is failing if the scaffolding objects (the
+// namespace and RBAC) could not be ensured; otherwise the last corrected
+// drift of the scaffolding, if any, is reported in the message of the
+// failing condition.  Pod disruption budgets with fewer healthy pods than
+// they want are not a rollout, so they are reported in the message of the
+// available condition.
+func computeStatusConditions(conditions []configv1.ClusterOperatorStatusCondition, ns *corev1.Namespace, scaffoldingErr error, scaffoldingDrift *scaffoldingDrift, dnses []operatorv1.DNS, daemonsets []appsv1.DaemonSet, deployments []appsv1.Deployment, pdbs []policyv1beta1.PodDisruptionBudget) []configv1.ClusterOperatorStatusCondition {
 	failingCondition := &configv1.ClusterOperatorStatusCondition{
 		Type:   configv1.OperatorFailing,
 		Status: configv1.ConditionUnknown,
 	}
-	if ns == nil {
+	switch {
+	case ns == nil:
 		failingCondition.Status = configv1.ConditionTrue
 		failingCondition.Reason = "NoNamespace"
 		failingCondition.Message = "DNS namespace does not exist"
-	} else {
+	case scaffoldingErr != nil:
+		failingCondition.Status = configv1.ConditionTrue
+		failingCondition.Reason = "ScaffoldingFailed"
+		failingCondition.Message = fmt.Sprintf("failed to ensure DNS namespace and RBAC: %v", scaffoldingErr)
+	case scaffoldingDrift != nil:
+		failingCondition.Status = configv1.ConditionFalse
+		failingCondition.Reason = "ScaffoldingDriftCorrected"
+		failingCondition.Message = fmt.Sprintf("corrected drift of %s from its manifest at %s", scaffoldingDrift.described, scaffoldingDrift.corrected.UTC().Format(time.RFC3339))
+	default:
 		failingCondition.Status = configv1.ConditionFalse
 	}
 	conditions = setStatusCondition(conditions, failingCondition)
@@ -135,14 +162,30 @@ func computeStatusConditions(conditions []configv1.ClusterOperatorStatusConditio
 		Type:   configv1.OperatorProgressing,
 		Status: configv1.ConditionUnknown,
 	}
+	// Workloads are keyed by name, which the daemonset and the deployment
+	// of a dns share.
+	workloadsAvailable := map[string]bool{}
+	for _, d := range daemonsets {
+		if isAuxiliaryDaemonSet(&d) {
+			continue
+		}
+		workloadsAvailable[d.Name] = workloadsAvailable[d.Name] || d.Status.NumberAvailable > 0
+	}
+	for _, d := range deployments {
+		workloadsAvailable[d.Name] = workloadsAvailable[d.Name] || d.Status.AvailableReplicas > 0
+	}
 	numDNSes := len(dnses)
-	numDaemonSets := len(daemonsets)
-	if numDNSes == numDaemonSets {
+	numWorkloads := len(workloadsAvailable)
+	progressing := []string{}
+	if numDNSes != numWorkloads {
+		progressing = append(progressing, fmt.Sprintf("have %d dns workloads, want %d", numWorkloads, numDNSes))
+	}
+	if len(progressing) == 0 {
 		progressingCondition.Status = configv1.ConditionFalse
 	} else {
 		progressingCondition.Status = configv1.ConditionTrue
 		progressingCondition.Reason = "Reconciling"
-		progressingCondition.Message = fmt.Sprintf("have %d daemonsets, want %d", numDaemonSets, numDNSes)
+		progressingCondition.Message = strings.Join(progressing, "\n")
 	}
 	conditions = setStatusCondition(conditions, progressingCondition)
 
@@ -150,33 +193,57 @@ func computeStatusConditions(conditions []configv1.ClusterOperatorStatusConditio
 		Type:   configv1.OperatorAvailable,
 		Status: configv1.ConditionUnknown,
 	}
-	daemonsetsAvailable := map[string]bool{}
-	for _, d := range daemonsets {
-		daemonsetsAvailable[d.Name] = d.Status.NumberAvailable > 0
-	}
 	unavailable := []string{}
 	for _, dns := range dnses {
-		name := DNSDaemonSetName(&dns).Name
-		if available, exists := daemonsetsAvailable[name]; !exists {
-			msg := fmt.Sprintf("no daemonset for dns %q", dns.Name)
+		// Workloads are keyed by name alone, so the namespace is left out.
+		name := DNSDaemonSetName("", &dns).Name
+		if available, exists := workloadsAvailable[name]; !exists {
+			msg := fmt.Sprintf("no daemonset or deployment for dns %q", dns.Name)
 			unavailable = append(unavailable, msg)
 		} else if !available {
-			msg := fmt.Sprintf("daemonset %q is not available", name)
+			msg := fmt.Sprintf("workload %q is not available", name)
 			unavailable = append(unavailable, msg)
 		}
 	}
-	if len(unavailable) == 0 {
-		availableCondition.Status = configv1.ConditionTrue
-	} else {
+	unhealthy := []string{}
+	for _, pdb := range pdbs {
+		if pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy {
+			msg := fmt.Sprintf("pod disruption budget %q has %d healthy pods, want %d", pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy)
+			unhealthy = append(unhealthy, msg)
+		}
+	}
+	switch {
+	case len(unavailable) != 0:
 		availableCondition.Status = configv1.ConditionFalse
 		availableCondition.Reason = "DaemonSetNotAvailable"
-		availableCondition.Message = strings.Join(unavailable, "\n")
+		availableCondition.Message = strings.Join(append(unavailable, unhealthy...), "\n")
+	case len(unhealthy) != 0:
+		availableCondition.Status = configv1.ConditionTrue
+		availableCondition.Reason = "PodDisruptionBudgetNotSatisfied"
+		availableCondition.Message = strings.Join(unhealthy, "\n")
+	default:
+		availableCondition.Status = configv1.ConditionTrue
 	}
 	conditions = setStatusCondition(conditions, availableCondition)
 
 	return conditions
 }
 
+// isAuxiliaryDaemonSet returns true if the given daemonset runs something
+// other than CoreDNS for a dns, such as the node resolver or the node-local
+// cache.
+func isAuxiliaryDaemonSet(daemonset *appsv1.DaemonSet) bool {
+	if daemonset.Spec.Selector == nil {
+		return false
+	}
+	for _, label := range []string{controllerNodeResolverDaemonSetLabel, controllerNodeLocalCacheDaemonSetLabel} {
+		if _, ok := daemonset.Spec.Selector.MatchLabels[label]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // setStatusCondition returns the result of setting the specified condition in
 // the given slice of conditions.
 func setStatusCondition(oldConditions []configv1.ClusterOperatorStatusCondition, condition *configv1.ClusterOperatorStatusCondition) []configv1.ClusterOperatorStatusCondition {
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -9,6 +10,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -16,6 +18,9 @@ func TestComputeStatusConditions(t *testing.T) {
 	type testInputs struct {
 		haveNamespace                           bool
 		numWanted, numAvailable, numUnavailable int
+		// deployments is true if the workloads are deployments, each with
+		// a node resolver daemonset.
+		deployments bool
 	}
 	type testOutputs struct {
 		failing, progressing, available bool
@@ -25,20 +30,25 @@ func TestComputeStatusConditions(t *testing.T) {
 		inputs      testInputs
 		outputs     testOutputs
 	}{
-		{"no namespace", testInputs{false, 0, 0, 0}, testOutputs{true, false, true}},
-		{"no dnses, no daemonsets", testInputs{true, 0, 0, 0}, testOutputs{false, false, true}},
-		{"scaling up", testInputs{true, 1, 0, 0}, testOutputs{false, true, false}},
-		{"scaling down", testInputs{true, 0, 1, 0}, testOutputs{false, true, true}},
-		{"0/2 daemonsets available", testInputs{true, 2, 0, 2}, testOutputs{false, false, false}},
-		{"1/2 daemonsets available", testInputs{true, 2, 1, 1}, testOutputs{false, false, false}},
-		{"2/2 daemonsets available", testInputs{true, 2, 2, 0}, testOutputs{false, false, true}},
+		{"no namespace", testInputs{false, 0, 0, 0, false}, testOutputs{true, false, true}},
+		{"no dnses, no daemonsets", testInputs{true, 0, 0, 0, false}, testOutputs{false, false, true}},
+		{"scaling up", testInputs{true, 1, 0, 0, false}, testOutputs{false, true, false}},
+		{"scaling down", testInputs{true, 0, 1, 0, false}, testOutputs{false, true, true}},
+		{"0/2 daemonsets available", testInputs{true, 2, 0, 2, false}, testOutputs{false, false, false}},
+		{"1/2 daemonsets available", testInputs{true, 2, 1, 1, false}, testOutputs{false, false, false}},
+		{"2/2 daemonsets available", testInputs{true, 2, 2, 0, false}, testOutputs{false, false, true}},
+		{"scaling up deployments", testInputs{true, 1, 0, 0, true}, testOutputs{false, true, false}},
+		{"scaling down deployments", testInputs{true, 0, 1, 0, true}, testOutputs{false, true, true}},
+		{"1/2 deployments available", testInputs{true, 2, 1, 1, true}, testOutputs{false, false, false}},
+		{"2/2 deployments available", testInputs{true, 2, 2, 0, true}, testOutputs{false, false, true}},
 	}
 
 	for _, tc := range testCases {
 		var (
-			namespace  *corev1.Namespace
-			dnses      []operatorv1.DNS
-			daemonsets []appsv1.DaemonSet
+			namespace   *corev1.Namespace
+			dnses       []operatorv1.DNS
+			daemonsets  []appsv1.DaemonSet
+			deployments []appsv1.Deployment
 
 			failing, progressing, available configv1.ConditionStatus
 		)
@@ -53,18 +63,41 @@ func TestComputeStatusConditions(t *testing.T) {
 					},
 				})
 		}
-		numDaemonsets := tc.inputs.numAvailable + tc.inputs.numUnavailable
-		for i := 0; i < numDaemonsets; i++ {
+		numWorkloads := tc.inputs.numAvailable + tc.inputs.numUnavailable
+		for i := 0; i < numWorkloads; i++ {
 			numberAvailable := 0
 			if i < tc.inputs.numAvailable {
 				numberAvailable = 1
 			}
-			daemonsets = append(daemonsets, appsv1.DaemonSet{
+			if !tc.inputs.deployments {
+				daemonsets = append(daemonsets, appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: fmt.Sprintf("dns-%d", i+1),
+					},
+					Status: appsv1.DaemonSetStatus{
+						NumberAvailable: int32(numberAvailable),
+					},
+				})
+				continue
+			}
+			dns := &operatorv1.DNS{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%d", i+1)}}
+			deployments = append(deployments, appsv1.Deployment{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: fmt.Sprintf("dns-%d", i+1),
 				},
+				Status: appsv1.DeploymentStatus{
+					AvailableReplicas: int32(numberAvailable),
+				},
+			})
+			daemonsets = append(daemonsets, appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: NodeResolverDaemonSetName("openshift-dns", dns).Name,
+				},
+				Spec: appsv1.DaemonSetSpec{
+					Selector: NodeResolverDaemonSetPodSelector(dns),
+				},
 				Status: appsv1.DaemonSetStatus{
-					NumberAvailable: int32(numberAvailable),
+					NumberAvailable: 1,
 				},
 			})
 		}
@@ -100,8 +133,12 @@ func TestComputeStatusConditions(t *testing.T) {
 		new := computeStatusConditions(
 			[]configv1.ClusterOperatorStatusCondition{},
 			namespace,
+			nil,
+			nil,
 			dnses,
 			daemonsets,
+			deployments,
+			nil,
 		)
 		gotExpected := true
 		if len(new) != len(expected) {
@@ -128,6 +165,51 @@ func TestComputeStatusConditions(t *testing.T) {
 	}
 }
 
+func TestComputeStatusConditionsPodDisruptionBudget(t *testing.T) {
+	testCases := []struct {
+		description                    string
+		currentHealthy, desiredHealthy int32
+		reason                         string
+	}{
+		{"budget satisfied", 3, 3, ""},
+		{"budget not satisfied", 2, 3, "PodDisruptionBudgetNotSatisfied"},
+	}
+
+	for _, tc := range testCases {
+		dnses := []operatorv1.DNS{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}
+		daemonsets := []appsv1.DaemonSet{{
+			ObjectMeta: metav1.ObjectMeta{Name: "dns-default"},
+			Status:     appsv1.DaemonSetStatus{NumberAvailable: tc.currentHealthy},
+		}}
+		pdbs := []policyv1beta1.PodDisruptionBudget{{
+			ObjectMeta: metav1.ObjectMeta{Name: "dns-default"},
+			Status: policyv1beta1.PodDisruptionBudgetStatus{
+				CurrentHealthy: tc.currentHealthy,
+				DesiredHealthy: tc.desiredHealthy,
+			},
+		}}
+		conditions := computeStatusConditions(nil, &corev1.Namespace{}, nil, nil, dnses, daemonsets, nil, pdbs)
+		for _, c := range conditions {
+			switch c.Type {
+			case configv1.OperatorProgressing:
+				if c.Status != configv1.ConditionFalse {
+					t.Errorf("%q: expected progressing %s, got %s", tc.description, configv1.ConditionFalse, c.Status)
+				}
+			case configv1.OperatorAvailable:
+				if c.Status != configv1.ConditionTrue {
+					t.Errorf("%q: expected available %s, got %s", tc.description, configv1.ConditionTrue, c.Status)
+				}
+				if c.Reason != tc.reason {
+					t.Errorf("%q: expected available reason %q, got %q", tc.description, tc.reason, c.Reason)
+				}
+				if len(tc.reason) != 0 && !strings.Contains(c.Message, `pod disruption budget "dns-default" has 2 healthy pods, want 3`) {
+					t.Errorf("%q: expected available message to report the budget, got %q", tc.description, c.Message)
+				}
+			}
+		}
+	}
+}
+
 func TestSetStatusCondition(t *testing.T) {
 	testCases := []struct {
 		description   string
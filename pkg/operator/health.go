@@ -0,0 +1,145 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// maxReconcileAge is how long ago the last reconcile may have finished for
+// the leader to be ready.  The default dns is reconciled at least every
+// resync period, so an older reconcile means the controller is wedged.  The
+// bound is raised to a few resync periods when the resync period is long.
+const maxReconcileAge = 15 * time.Minute
+
+// health reports the health and readiness of the operator process.
+type health struct {
+	// manager is the operator's manager.
+	manager manager.Manager
+
+	// running is 1 while the manager is running.
+	running int32
+
+	// isLeader returns true if the process is the leader and reconciling.
+	isLeader func() bool
+
+	// lastReconcile returns the time at which the last reconcile finished.
+	lastReconcile func() time.Time
+
+	// now returns the current time.
+	now func() time.Time
+
+	// maxAge overrides maxReconcileAge if it is longer.
+	maxAge time.Duration
+}
+
+// setRunning records whether the manager is running.
+func (h *health) setRunning(running bool) {
+	value := int32(0)
+	if running {
+		value = 1
+	}
+	atomic.StoreInt32(&h.running, value)
+}
+
+// healthz reports whether the manager is running and, on the leader,
+// whether the manager's informers have synced.  A standby does not start
+// informers until it becomes the leader.
+func (h *health) healthz(w http.ResponseWriter, _ *http.Request) {
+	if atomic.LoadInt32(&h.running) != 1 {
+		http.Error(w, "manager is not running", http.StatusInternalServerError)
+		return
+	}
+	if h.isLeader() && !h.cachesSynced() {
+		http.Error(w, "informers have not synced", http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
+
+// readyz reports whether the process is the leader and has finished a
+// reconcile recently.  A standby is not ready, so readyz is not the readiness
+// probe of the operator deployment: a rolling update would wait for a new
+// pod to become ready, which it cannot until an old pod gives up leadership.
+func (h *health) readyz(w http.ResponseWriter, _ *http.Request) {
+	if !h.isLeader() {
+		http.Error(w, "not the leader", http.StatusServiceUnavailable)
+		return
+	}
+	last := h.lastReconcile()
+	if last.IsZero() {
+		http.Error(w, "no reconcile has finished", http.StatusServiceUnavailable)
+		return
+	}
+	maxAge := maxReconcileAge
+	if h.maxAge > maxAge {
+		maxAge = h.maxAge
+	}
+	if age := h.now().Sub(last); age > maxAge {
+		http.Error(w, fmt.Sprintf("last reconcile finished %v ago", age.Round(time.Second)), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprint(w, "ok")
+}
+
+// cachesSynced returns true if the manager's informers have synced, without
+// waiting for them.
+func (h *health) cachesSynced() bool {
+	stop := make(chan struct{})
+	close(stop)
+	return h.manager.GetCache().WaitForCacheSync(stop)
+}
+
+// handler returns the handler for the metrics and health endpoints, for
+// when they share a listener.
+func (h *health) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsHandler())
+	h.register(mux)
+	return mux
+}
+
+// healthHandler returns the handler for the health endpoints alone.
+func (h *health) healthHandler() http.Handler {
+	mux := http.NewServeMux()
+	h.register(mux)
+	return mux
+}
+
+// register registers the health endpoints with mux.
+func (h *health) register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", h.healthz)
+	mux.HandleFunc("/readyz", h.readyz)
+}
+
+// metricsHandler returns the handler for the metrics endpoint alone.
+func metricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{ErrorHandling: promhttp.HTTPErrorOnError}))
+	return mux
+}
+
+// serve serves handler on the given listener until stop is closed.
+func serve(listener net.Listener, handler http.Handler, stop <-chan struct{}) error {
+	server := &http.Server{Handler: handler}
+	errChan := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+	}()
+	select {
+	case <-stop:
+		return server.Shutdown(context.Background())
+	case err := <-errChan:
+		return err
+	}
+}
@@ -0,0 +1,119 @@
+package operator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// fakeCache is a cache whose informers have synced if synced is true.
+type fakeCache struct {
+	cache.Cache
+	synced bool
+}
+
+func (c *fakeCache) WaitForCacheSync(_ <-chan struct{}) bool {
+	return c.synced
+}
+
+// fakeManager is a manager with a fake cache.
+type fakeManager struct {
+	manager.Manager
+	cache *fakeCache
+}
+
+func (m *fakeManager) GetCache() cache.Cache {
+	return m.cache
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		description   string
+		running       bool
+		leader        bool
+		synced        bool
+		lastReconcile time.Time
+
+		expectHealthz int
+		expectReadyz  int
+	}{
+		{
+			description:   "manager not running",
+			running:       false,
+			expectHealthz: http.StatusInternalServerError,
+			expectReadyz:  http.StatusServiceUnavailable,
+		},
+		{
+			description:   "standby",
+			running:       true,
+			expectHealthz: http.StatusOK,
+			expectReadyz:  http.StatusServiceUnavailable,
+		},
+		{
+			description:   "leader with unsynced informers",
+			running:       true,
+			leader:        true,
+			lastReconcile: now.Add(-time.Minute),
+			expectHealthz: http.StatusInternalServerError,
+			expectReadyz:  http.StatusOK,
+		},
+		{
+			description:   "leader before the first reconcile",
+			running:       true,
+			leader:        true,
+			synced:        true,
+			expectHealthz: http.StatusOK,
+			expectReadyz:  http.StatusServiceUnavailable,
+		},
+		{
+			description:   "leader with a recent reconcile",
+			running:       true,
+			leader:        true,
+			synced:        true,
+			lastReconcile: now.Add(-time.Minute),
+			expectHealthz: http.StatusOK,
+			expectReadyz:  http.StatusOK,
+		},
+		{
+			description:   "leader with a stale reconcile",
+			running:       true,
+			leader:        true,
+			synced:        true,
+			lastReconcile: now.Add(-maxReconcileAge - time.Second),
+			expectHealthz: http.StatusOK,
+			expectReadyz:  http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		h := &health{
+			manager:       &fakeManager{cache: &fakeCache{synced: tc.synced}},
+			isLeader:      func() bool { return tc.leader },
+			lastReconcile: func() time.Time { return tc.lastReconcile },
+			now:           func() time.Time { return now },
+		}
+		h.setRunning(tc.running)
+		handler := h.handler()
+		for path, expect := range map[string]int{"/healthz": tc.expectHealthz, "/readyz": tc.expectReadyz} {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			if recorder.Code != expect {
+				t.Errorf("%s: expected %s to return %d, got %d: %s", tc.description, path, expect, recorder.Code, recorder.Body.String())
+			}
+		}
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	h := &health{manager: &fakeManager{cache: &fakeCache{}}}
+	recorder := httptest.NewRecorder()
+	h.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("expected /metrics to return %d, got %d", http.StatusOK, recorder.Code)
+	}
+}
@@ -0,0 +1,64 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// leaderGauge is 1 while this process is the leader and 0 otherwise.
+var leaderGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "dns_operator",
+	Name:      "leader",
+	Help:      "Whether this process is the leader and is reconciling; 1 if it is and 0 otherwise.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(leaderGauge)
+}
+
+// setLeader records whether this process is the leader.
+func (o *Operator) setLeader(leader bool) {
+	value := int32(0)
+	if leader {
+		value = 1
+	}
+	atomic.StoreInt32(&o.leader, value)
+	leaderGauge.Set(float64(value))
+}
+
+// releaseLeaderLease releases the leader election lock if it is held by a
+// process with the given identity prefix.  The manager prefixes the
+// identity of its lock with the hostname, which is the pod name, and does
+// not release the lock when it stops, so a standby would wait for a lease
+// that this process keeps renewing until it exits.  Clearing the holder
+// stops the renewals and lets the standby take the lock once it has
+// observed the release for a lease duration.
+func releaseLeaderLease(lock resourcelock.Interface, identityPrefix string) error {
+	record, err := lock.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get leader election lock %s: %v", lock.Describe(), err)
+	}
+	if !strings.HasPrefix(record.HolderIdentity, identityPrefix) {
+		return nil
+	}
+	now := metav1.Now()
+	released := resourcelock.LeaderElectionRecord{
+		LeaseDurationSeconds: 1,
+		AcquireTime:          now,
+		RenewTime:            now,
+		LeaderTransitions:    record.LeaderTransitions,
+	}
+	if err := lock.Update(released); err != nil {
+		return fmt.Errorf("failed to release leader election lock %s: %v", lock.Describe(), err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package operator
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// fakeLock is a leader election lock that holds a record in memory.
+type fakeLock struct {
+	record  resourcelock.LeaderElectionRecord
+	updated bool
+}
+
+func (l *fakeLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	record := l.record
+	return &record, nil
+}
+
+func (l *fakeLock) Create(record resourcelock.LeaderElectionRecord) error {
+	l.record = record
+	return nil
+}
+
+func (l *fakeLock) Update(record resourcelock.LeaderElectionRecord) error {
+	l.record = record
+	l.updated = true
+	return nil
+}
+
+func (l *fakeLock) RecordEvent(string) {}
+
+func (l *fakeLock) Identity() string { return "" }
+
+func (l *fakeLock) Describe() string { return "openshift-dns-operator/dns-operator-lock" }
+
+func TestReleaseLeaderLease(t *testing.T) {
+	testCases := []struct {
+		description   string
+		holder        string
+		expectRelease bool
+	}{
+		{"held by this process", "dns-operator-abc_1234", true},
+		{"held by another process", "dns-operator-def_5678", false},
+		{"not held", "", false},
+	}
+	for _, tc := range testCases {
+		lock := &fakeLock{record: resourcelock.LeaderElectionRecord{
+			HolderIdentity:       tc.holder,
+			LeaseDurationSeconds: 15,
+			LeaderTransitions:    3,
+		}}
+		if err := releaseLeaderLease(lock, "dns-operator-abc_"); err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.description, err)
+			continue
+		}
+		if lock.updated != tc.expectRelease {
+			t.Errorf("%s: expected release %t, got %t", tc.description, tc.expectRelease, lock.updated)
+			continue
+		}
+		if !tc.expectRelease {
+			continue
+		}
+		if len(lock.record.HolderIdentity) != 0 || lock.record.LeaseDurationSeconds != 1 || lock.record.LeaderTransitions != 3 {
+			t.Errorf("%s: expected a released record, got %#v", tc.description, lock.record)
+		}
+	}
+}
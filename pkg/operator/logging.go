@@ -0,0 +1,37 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// log is the logger of the operator package.
+var log = logrus.WithField("component", "operator")
+
+// configureLogging sets the level and format of the standard logger, which
+// all of the operator's packages log through.  Empty values select the
+// defaults, "info" and "text".
+func configureLogging(level, format string) error {
+	if len(level) == 0 {
+		level = "info"
+	}
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	var formatter logrus.Formatter
+	switch format {
+	case "", "text":
+		formatter = &logrus.TextFormatter{FullTimestamp: true}
+	case "json":
+		formatter = &logrus.JSONFormatter{}
+	default:
+		return fmt.Errorf("unsupported log format %q; must be \"text\" or \"json\"", format)
+	}
+
+	logrus.SetLevel(parsed)
+	logrus.SetFormatter(formatter)
+	return nil
+}
@@ -0,0 +1,41 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestConfigureLogging(t *testing.T) {
+	defer logrus.SetLevel(logrus.GetLevel())
+	defer logrus.SetFormatter(logrus.StandardLogger().Formatter)
+
+	testCases := []struct {
+		level, format string
+		expectLevel   logrus.Level
+		expectJSON    bool
+		expectError   bool
+	}{
+		{level: "", format: "", expectLevel: logrus.InfoLevel},
+		{level: "debug", format: "text", expectLevel: logrus.DebugLevel},
+		{level: "warning", format: "json", expectLevel: logrus.WarnLevel, expectJSON: true},
+		{level: "loud", format: "text", expectError: true},
+		{level: "info", format: "xml", expectError: true},
+	}
+	for _, tc := range testCases {
+		err := configureLogging(tc.level, tc.format)
+		switch {
+		case tc.expectError && err == nil:
+			t.Errorf("level %q, format %q: expected an error", tc.level, tc.format)
+		case !tc.expectError && err != nil:
+			t.Errorf("level %q, format %q: unexpected error: %v", tc.level, tc.format, err)
+		case err == nil:
+			if logrus.GetLevel() != tc.expectLevel {
+				t.Errorf("level %q: expected level %v, got %v", tc.level, tc.expectLevel, logrus.GetLevel())
+			}
+			if _, isJSON := logrus.StandardLogger().Formatter.(*logrus.JSONFormatter); isJSON != tc.expectJSON {
+				t.Errorf("format %q: expected json formatter to be %t", tc.format, tc.expectJSON)
+			}
+		}
+	}
+}
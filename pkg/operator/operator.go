@@ -3,21 +3,26 @@ package operator
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
 	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	operatorclient "github.com/openshift/cluster-dns-operator/pkg/operator/client"
 	operatorconfig "github.com/openshift/cluster-dns-operator/pkg/operator/config"
 	operatorcontroller "github.com/openshift/cluster-dns-operator/pkg/operator/controller"
-
-	"github.com/sirupsen/logrus"
+	"github.com/openshift/cluster-dns-operator/pkg/util/version"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,72 +37,242 @@ type Operator struct {
 	manager manager.Manager
 	caches  []cache.Cache
 	client  client.Client
+
+	// leader is 1 while this process is the leader, or while it is
+	// running if leader election is disabled.
+	leader int32
+
+	// leaderLock is the leader election lock, and leaderIdentityPrefix
+	// prefixes the identity with which this process holds it.  The lock
+	// is nil if leader election is disabled.
+	leaderLock           resourcelock.Interface
+	leaderIdentityPrefix string
+
+	// lastReconcile is the time, in Unix nanoseconds, at which the last
+	// reconcile finished, or 0 if none has.
+	lastReconcile int64
+
+	// health serves the health endpoints on healthBindAddress, and the
+	// metrics endpoint on metricsBindAddress.  If the addresses are the
+	// same, both are served on one listener.
+	health             *health
+	metricsBindAddress string
+	healthBindAddress  string
+}
+
+// IsLeader returns true if this process is the leader and is reconciling.
+func (o *Operator) IsLeader() bool {
+	return atomic.LoadInt32(&o.leader) == 1
+}
+
+// recordReconcileFinished records the time at which a reconcile finished.
+func (o *Operator) recordReconcileFinished(t time.Time) {
+	atomic.StoreInt64(&o.lastReconcile, t.UnixNano())
 }
 
-// New creates (but does not start) a new operator from configuration.
+// lastReconcileTime returns the time at which the last reconcile finished,
+// or the zero time if none has.
+func (o *Operator) lastReconcileTime() time.Time {
+	nanos := atomic.LoadInt64(&o.lastReconcile)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// New creates (but does not start) a new operator from configuration.  Empty
+// configuration fields are defaulted.
 func New(config operatorconfig.Config) (*Operator, error) {
+	config.SetDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %v", err)
+	}
+	if err := configureLogging(config.LogLevel, config.LogFormat); err != nil {
+		return nil, fmt.Errorf("failed to configure logging: %v", err)
+	}
+
 	kubeConfig, err := kconfig.GetConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kube config: %v", err)
 	}
 	scheme := operatorclient.GetScheme()
 	operatorManager, err := manager.New(kubeConfig, manager.Options{
-		Scheme:    scheme,
-		Namespace: "openshift-dns",
+		Scheme:                  scheme,
+		Namespace:               config.OperandNamespace,
+		LeaderElection:          config.LeaderElection,
+		LeaderElectionNamespace: config.LeaderElectionNamespace,
+		LeaderElectionID:        config.LeaderElectionID,
+		// Metrics are served by the operator alongside the health
+		// endpoints.
+		MetricsBindAddress: "0",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create operator manager: %v", err)
 	}
 
+	coreDNSVersion, err := version.Parse(config.CoreDNSVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CoreDNS version: %v", err)
+	}
+
+	kubeClient, err := operatorclient.NewClient(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kube client: %v", err)
+	}
+	op := &Operator{
+		manager:            operatorManager,
+		metricsBindAddress: config.MetricsBindAddress,
+		healthBindAddress:  config.HealthBindAddress,
+
+		// TODO: These are only needed for the default dns stuff, which
+		// should be refactored away.
+		client: kubeClient,
+	}
+
 	// Create and register the operator controller with the operator manager.
 	cfg := operatorcontroller.Config{
 		KubeConfig:             kubeConfig,
 		CoreDNSImage:           config.CoreDNSImage,
+		CoreDNSVersion:         coreDNSVersion,
+		CoreDNSPlugins:         config.CoreDNSPlugins,
 		OpenshiftCLIImage:      config.OpenshiftCLIImage,
 		OperatorReleaseVersion: config.OperatorReleaseVersion,
+		OperandNamespace:       config.OperandNamespace,
+		OperatorNamespace:      config.OperatorNamespace,
+		ResyncPeriod:           config.ResyncPeriod.Duration,
+		ReconcileFinished:      op.recordReconcileFinished,
 	}
 	if _, err := operatorcontroller.New(operatorManager, cfg); err != nil {
 		return nil, fmt.Errorf("failed to create operator controller: %v", err)
 	}
 
-	kubeClient, err := operatorclient.NewClient(kubeConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kube client: %v", err)
+	if config.LeaderElection {
+		coreClient, err := corev1client.NewForConfig(kubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create core client: %v", err)
+		}
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %v", err)
+		}
+		op.leaderLock = &resourcelock.ConfigMapLock{
+			ConfigMapMeta: metav1.ObjectMeta{Namespace: config.LeaderElectionNamespace, Name: config.LeaderElectionID},
+			Client:        coreClient,
+		}
+		op.leaderIdentityPrefix = hostname + "_"
 	}
-	return &Operator{
-		manager: operatorManager,
-
-		// TODO: These are only needed for the default dns stuff, which
-		// should be refactored away.
-		client: kubeClient,
-	}, nil
+	op.health = &health{
+		manager:       operatorManager,
+		isLeader:      op.IsLeader,
+		lastReconcile: op.lastReconcileTime,
+		now:           time.Now,
+		maxAge:        3 * config.ResyncPeriod.Duration,
+	}
+	return op, nil
 }
 
 // Start creates the default DNS and then starts the operator
-// synchronously until a message is received on the stop channel.
+// synchronously until a message is received on the stop channel.  When
+// leader election is enabled, the operator only creates the default DNS and
+// reconciles once it has become the leader.  On stop, Start waits for the
+// manager to exit and, if this process led, releases the leader election
+// lock.
 // TODO: Move the default DNS logic elsewhere.
 func (o *Operator) Start(stop <-chan struct{}) error {
-	// Periodicaly ensure the default controller exists.
-	go wait.Until(func() {
-		if err := o.ensureDefaultDNS(); err != nil {
-			logrus.Errorf("failed to ensure default dns: %v", err)
-		}
-	}, 1*time.Minute, stop)
+	// The manager starts its runnables once it is the leader and stops
+	// them before it exits, so the default dns is only ensured by the
+	// leader.
+	led := make(chan struct{})
+	leadDone := make(chan struct{})
+	lead := manager.RunnableFunc(func(stop <-chan struct{}) error {
+		close(led)
+		defer close(leadDone)
+		o.setLeader(true)
+		log.Info("became leader")
+		defer o.setLeader(false)
+
+		// Periodicaly ensure the default controller exists.
+		wait.Until(func() {
+			if err := o.ensureDefaultDNS(); err != nil {
+				log.WithError(err).Error("failed to ensure default dns")
+			}
+		}, 1*time.Minute, stop)
+		return nil
+	})
+	if err := o.manager.Add(lead); err != nil {
+		return fmt.Errorf("failed to add leader runnable to manager: %v", err)
+	}
+
+	errChan := make(chan error, 3)
 
-	errChan := make(chan error)
+	// Serve metrics and the health endpoints whether or not this process
+	// is the leader.
+	if o.healthBindAddress == o.metricsBindAddress {
+		if err := o.serve(o.metricsBindAddress, o.health.handler(), "metrics and health endpoints", stop, errChan); err != nil {
+			return err
+		}
+	} else {
+		if err := o.serve(o.metricsBindAddress, metricsHandler(), "metrics endpoint", stop, errChan); err != nil {
+			return err
+		}
+		if err := o.serve(o.healthBindAddress, o.health.healthHandler(), "health endpoints", stop, errChan); err != nil {
+			return err
+		}
+	}
 
 	// Start the manager.
+	managerErr := make(chan error, 1)
+	o.health.setRunning(true)
 	go func() {
-		errChan <- o.manager.Start(stop)
+		err := o.manager.Start(stop)
+		o.health.setRunning(false)
+		managerErr <- err
 	}()
 
-	// Wait for the manager to exit or a stop signal.
+	// Wait for the manager to exit, a failure serving, or a stop signal.
+	// The manager returns once stop is closed.
 	select {
 	case <-stop:
-		return nil
+		if err := <-managerErr; err != nil {
+			return err
+		}
+	case err := <-managerErr:
+		return err
 	case err := <-errChan:
 		return err
 	}
+
+	// The manager stops its runnables without waiting for them, so the
+	// leader waits for the leader runnable before giving up the lock.
+	select {
+	case <-led:
+		<-leadDone
+	default:
+		return nil
+	}
+	if o.leaderLock != nil {
+		if err := releaseLeaderLease(o.leaderLock, o.leaderIdentityPrefix); err != nil {
+			return err
+		}
+		log.Info("released leader election lock")
+	}
+	return nil
+}
+
+// serve listens on address and serves handler in the background until stop
+// is closed, sending any error serving to errChan.  What describes the
+// endpoints in errors.
+func (o *Operator) serve(address string, handler http.Handler, what string, stop <-chan struct{}, errChan chan<- error) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", address, err)
+	}
+	go func() {
+		if err := serve(listener, handler, stop); err != nil {
+			errChan <- fmt.Errorf("failed to serve %s: %v", what, err)
+		}
+	}()
+	return nil
 }
 
 // ensureDefaultDNS creates the default dns if it doesn't already exist.
@@ -114,7 +289,7 @@ func (o *Operator) ensureDefaultDNS() error {
 		if err := o.client.Create(context.TODO(), dns); err != nil {
 			return fmt.Errorf("failed to create default dns: %v", err)
 		}
-		logrus.Infof("created default dns: %s", dns.Name)
+		log.WithField("dns", dns.Name).Info("created default dns")
 	}
 	return nil
 }
@@ -0,0 +1,56 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expected  Version
+		expectErr bool
+	}{
+		{input: "1.3.1", expected: Version{1, 3, 1}},
+		{input: "v1.6.2", expected: Version{1, 6, 2}},
+		{input: "1.7", expected: Version{1, 7, 0}},
+		{input: "1", expectErr: true},
+		{input: "1.2.3.4", expectErr: true},
+		{input: "1.x.0", expectErr: true},
+		{input: "1.-2.0", expectErr: true},
+		{input: "1.+2.0", expectErr: true},
+		{input: "", expectErr: true},
+	}
+	for _, tc := range testCases {
+		v, err := Parse(tc.input)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %v", tc.input, v)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if v != tc.expected {
+			t.Errorf("%q: expected %v, got %v", tc.input, tc.expected, v)
+		}
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	testCases := []struct {
+		v, min   string
+		expected bool
+	}{
+		{"1.3.1", "1.3.1", true},
+		{"1.3.1", "1.3.0", true},
+		{"1.3.1", "1.4.0", false},
+		{"1.10.0", "1.9.5", true},
+		{"2.0.0", "1.12.0", true},
+		{"0.9.9", "1.0.0", false},
+	}
+	for _, tc := range testCases {
+		if actual := MustParse(tc.v).AtLeast(MustParse(tc.min)); actual != tc.expected {
+			t.Errorf("expected %s at least %s to be %t, got %t", tc.v, tc.min, tc.expected, actual)
+		}
+	}
+}